@@ -0,0 +1,164 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/audit"
+	"github.com/cduffaut/matcha/internal/chat"
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/cduffaut/matcha/internal/moderation"
+	"github.com/cduffaut/matcha/internal/notifications"
+	"github.com/cduffaut/matcha/internal/user"
+)
+
+// Bornes de l'export : l'historique et les notifications sont conservés au
+// plus quelques années, les messages sont lus par pages
+const (
+	exportHistoryDays   = 10 * 365
+	exportNotifications = 100000
+	exportMessagePage   = 500
+)
+
+// conversationExport regroupe les messages échangés avec un autre utilisateur
+type conversationExport struct {
+	UserID   int             `json:"user_id"`
+	Username string          `json:"username"`
+	Messages []*chat.Message `json:"messages"`
+}
+
+// userExport rassemble les données personnelles d'un utilisateur
+type userExport struct {
+	ExportedAt    time.Time                     `json:"exported_at"`
+	User          *models.User                  `json:"user"`
+	Profile       *user.Profile                 `json:"profile"`
+	Discovery     *user.DiscoveryPreferences    `json:"discovery_preferences"`
+	SavedSearches []*user.SavedSearch           `json:"saved_searches"`
+	FameHistory   []user.FameHistoryPoint       `json:"fame_history"`
+	Visitors      []user.ProfileVisit           `json:"visitors"`
+	Likes         []user.UserLike               `json:"likes"`
+	BlockedUsers  []user.BlockedUser            `json:"blocked_users"`
+	Notifications []*notifications.Notification `json:"notifications"`
+	Conversations []conversationExport          `json:"conversations"`
+	Appeals       []*moderation.Appeal          `json:"appeals"`
+}
+
+// exportUser écrit en JSON les données d'un utilisateur, sur la sortie
+// standard ou dans le fichier donné par -o
+func (a *app) exportUser(args []string) error {
+	flags := flag.NewFlagSet("users export", flag.ContinueOnError)
+	file := flags.String("o", "", "fichier de destination (sortie standard par défaut)")
+	args, err := parseArgs("users export", flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	actor, err := a.actor()
+	if err != nil {
+		return err
+	}
+	u, err := a.lookupUser(args[0])
+	if err != nil {
+		return err
+	}
+	export, err := a.collectExport(u)
+	if err != nil {
+		return err
+	}
+	a.record(actor, audit.ActionUserExport, audit.TargetUser, u.ID, map[string]interface{}{"file": *file})
+
+	if *file == "" {
+		return (&output{json: true, w: a.out.w}).value(export)
+	}
+
+	f, err := os.OpenFile(*file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la création de %s: %w", *file, err)
+	}
+	if err := (&output{json: true, w: f}).value(export); err != nil {
+		f.Close()
+		return fmt.Errorf("erreur lors de l'écriture de %s: %w", *file, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("erreur lors de l'écriture de %s: %w", *file, err)
+	}
+
+	return a.out.result(map[string]interface{}{"user_id": u.ID, "file": *file},
+		fmt.Sprintf("Données de %s exportées dans %s", u.Username, *file))
+}
+
+// collectExport lit les données de l'utilisateur dans chaque repository
+func (a *app) collectExport(u *models.User) (*userExport, error) {
+	export := &userExport{ExportedAt: time.Now().UTC(), User: u}
+	var err error
+
+	if export.Profile, err = a.profiles.GetByUserID(u.ID); err != nil {
+		return nil, err
+	}
+	if export.Profile.Tags, err = a.profiles.GetTagsByUserID(u.ID); err != nil {
+		return nil, err
+	}
+	if export.Profile.Photos, err = a.profiles.GetPhotosByUserID(u.ID); err != nil {
+		return nil, err
+	}
+	if export.Discovery, err = a.profiles.GetDiscoveryPreferences(u.ID); err != nil {
+		return nil, err
+	}
+	if export.SavedSearches, err = a.searches.ListByUser(u.ID); err != nil {
+		return nil, err
+	}
+	if export.FameHistory, err = a.profiles.GetFameHistory(u.ID, exportHistoryDays); err != nil {
+		return nil, err
+	}
+	if export.Visitors, err = a.profiles.GetVisitorsForUser(u.ID); err != nil {
+		return nil, err
+	}
+	if export.Likes, err = a.profiles.GetLikesForUser(u.ID); err != nil {
+		return nil, err
+	}
+	if export.BlockedUsers, err = a.profiles.GetBlockedUsers(u.ID); err != nil {
+		return nil, err
+	}
+	if export.Notifications, err = a.notifs.GetByUserID(u.ID, exportNotifications); err != nil {
+		return nil, err
+	}
+	if _, export.Appeals, err = a.appeals.Account(u.ID); err != nil {
+		return nil, err
+	}
+
+	conversations, err := a.messages.GetConversations(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	export.Conversations = make([]conversationExport, 0, len(conversations))
+	for _, conversation := range conversations {
+		messages, err := a.conversationMessages(u.ID, conversation.UserID)
+		if err != nil {
+			return nil, err
+		}
+		export.Conversations = append(export.Conversations, conversationExport{
+			UserID:   conversation.UserID,
+			Username: conversation.Username,
+			Messages: messages,
+		})
+	}
+
+	return export, nil
+}
+
+// conversationMessages lit tous les messages d'une conversation, page par page
+func (a *app) conversationMessages(userID, otherUserID int) ([]*chat.Message, error) {
+	all := []*chat.Message{}
+	for offset := 0; ; offset += exportMessagePage {
+		page, err := a.messages.GetMessages(userID, otherUserID, exportMessagePage, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < exportMessagePage {
+			return all, nil
+		}
+	}
+}
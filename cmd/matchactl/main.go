@@ -0,0 +1,223 @@
+// matchactl regroupe les opérations d'exploitation courantes (comptes, fame
+// rating, signalements, fichiers téléversés, export de données) en s'appuyant
+// sur les repositories et services du serveur, avec la même configuration.
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cduffaut/matcha/internal/audit"
+	"github.com/cduffaut/matcha/internal/auth"
+	"github.com/cduffaut/matcha/internal/chat"
+	"github.com/cduffaut/matcha/internal/config"
+	"github.com/cduffaut/matcha/internal/database"
+	"github.com/cduffaut/matcha/internal/email"
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/cduffaut/matcha/internal/moderation"
+	"github.com/cduffaut/matcha/internal/notifications"
+	"github.com/cduffaut/matcha/internal/session"
+	"github.com/cduffaut/matcha/internal/user"
+)
+
+const usage = `Usage : matchactl [-format table|json] [-as administrateur] <commande> [options]
+
+Comptes (un utilisateur est désigné par son nom d'utilisateur ou son email) :
+  users find <utilisateur>...
+  users verify <utilisateur>
+  users unverify <utilisateur>
+  users reset-password <utilisateur>             envoie l'email de réinitialisation
+  users suspend [-hours N] -reason MOTIF <utilisateur>
+  users ban -reason MOTIF <utilisateur>
+  users reinstate <utilisateur>
+  users export [-o fichier] <utilisateur>        export JSON des données de l'utilisateur
+
+Fame rating :
+  fame recompute
+
+Signalements :
+  reports list [-status S] [-category C] [-limit N] [-offset N]
+  reports resolve -resolution upheld|dismissed [-comment TEXTE] <id>
+
+Fichiers téléversés :
+  uploads purge [-dry-run] [-dir web/static/uploads]
+
+Les commandes qui modifient des données ou les exportent sont tracées dans le
+journal d'audit au nom du compte administrateur donné par -as, obligatoire pour
+celles-ci.
+`
+
+// app regroupe les dépendances partagées par les commandes
+type app struct {
+	cfg        *config.Config
+	out        *output
+	actorName  string
+	users      user.Repository
+	profiles   user.ProfileRepository
+	searches   user.SavedSearchRepository
+	messages   chat.MessageRepository
+	notifs     notifications.NotificationRepository
+	auth       *auth.Service
+	moderation *moderation.Service
+	appeals    *moderation.AppealService
+	audit      *audit.Service
+}
+
+func main() {
+	flags := flag.NewFlagSet("matchactl", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	format := flags.String("format", "table", "format de sortie : table ou json")
+	actorName := flags.String("as", "", "compte administrateur au nom duquel agir")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	if *format != "table" && *format != "json" {
+		fail(fmt.Errorf("format inconnu: %q", *format))
+	}
+
+	args := flags.Args()
+	if len(args) < 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fail(fmt.Errorf("erreur lors du chargement de la configuration: %w", err))
+	}
+
+	db, err := database.Connect(cfg.Database)
+	if err != nil {
+		fail(fmt.Errorf("erreur lors de la connexion à la base de données: %w", err))
+	}
+	defer db.Close()
+
+	a := newApp(cfg, db, &output{json: *format == "json", w: os.Stdout}, *actorName)
+	if err := a.run(args[0], args[1], args[2:]); err != nil {
+		db.Close()
+		fail(err)
+	}
+}
+
+// newApp construit les repositories et services comme le serveur
+func newApp(cfg *config.Config, db *sql.DB, out *output, actorName string) *app {
+	emailService := email.NewService(
+		os.Getenv("SMTP_HOST"),
+		os.Getenv("SMTP_PORT"),
+		os.Getenv("SMTP_USERNAME"),
+		os.Getenv("SMTP_PASSWORD"),
+		os.Getenv("FROM_EMAIL"),
+	)
+	baseURL := fmt.Sprintf("http://localhost:%s", cfg.Server.Port)
+
+	userRepo := user.NewPostgresRepository(db)
+	notificationRepo := notifications.NewPostgresNotificationRepository(db)
+	// Sans diffusion temps réel : les notifications sont lues à la prochaine visite
+	notificationService := notifications.NewService(notificationRepo, notifications.NewBroker())
+
+	return &app{
+		cfg:        cfg,
+		out:        out,
+		actorName:  actorName,
+		users:      userRepo,
+		profiles:   user.NewPostgresProfileRepository(db),
+		searches:   user.NewPostgresSavedSearchRepository(db),
+		messages:   chat.NewPostgresMessageRepository(db),
+		notifs:     notificationRepo,
+		auth:       auth.NewService(userRepo, emailService, baseURL),
+		moderation: moderation.NewService(moderation.NewPostgresRepository(db), offlineSessions{}, emailService, notificationService, baseURL),
+		appeals:    moderation.NewAppealService(moderation.NewPostgresAppealRepository(db), offlineSessions{}, emailService, baseURL),
+		audit:      audit.NewService(audit.NewPostgresRepository(db)),
+	}
+}
+
+// run exécute la commande demandée
+func (a *app) run(group, command string, args []string) error {
+	switch group + " " + command {
+	case "users find":
+		return a.findUsers(args)
+	case "users verify":
+		return a.setVerified(args, true)
+	case "users unverify":
+		return a.setVerified(args, false)
+	case "users reset-password":
+		return a.resetPassword(args)
+	case "users suspend":
+		return a.sanction(moderation.SanctionSuspend, args)
+	case "users ban":
+		return a.sanction(moderation.SanctionBan, args)
+	case "users reinstate":
+		return a.sanction(moderation.SanctionReinstate, args)
+	case "users export":
+		return a.exportUser(args)
+	case "fame recompute":
+		return a.recomputeFame()
+	case "reports list":
+		return a.listReports(args)
+	case "reports resolve":
+		return a.resolveReport(args)
+	case "uploads purge":
+		return a.purgeUploads(args)
+	default:
+		return fmt.Errorf("commande inconnue: %s %s (voir matchactl -h)", group, command)
+	}
+}
+
+// lookupUser retrouve un utilisateur par son nom d'utilisateur ou son email
+func (a *app) lookupUser(identifier string) (*models.User, error) {
+	if u, err := a.users.GetByUsername(identifier); err == nil {
+		return u, nil
+	}
+	if u, err := a.users.GetByEmail(identifier); err == nil {
+		return u, nil
+	}
+	return nil, fmt.Errorf("utilisateur introuvable: %s", identifier)
+}
+
+// actor retourne le compte administrateur donné par -as, requis pour les
+// actions tracées
+func (a *app) actor() (moderation.Actor, error) {
+	if a.actorName == "" {
+		return moderation.Actor{}, fmt.Errorf("cette commande doit être journalisée : précisez -as <administrateur>")
+	}
+	admin, err := a.lookupUser(a.actorName)
+	if err != nil {
+		return moderation.Actor{}, err
+	}
+	if !models.HasRole(admin.Role, models.RoleAdmin) {
+		return moderation.Actor{}, fmt.Errorf("%s n'est pas administrateur", admin.Username)
+	}
+	return moderation.Actor{UserID: admin.ID, Role: admin.Role}, nil
+}
+
+// record trace une action de matchactl dans le journal d'audit
+func (a *app) record(actor moderation.Actor, action, targetType string, targetID int, details map[string]interface{}) {
+	a.audit.Record(&audit.Event{
+		ActorID:    actor.UserID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		UserAgent:  "matchactl",
+		Details:    details,
+	})
+}
+
+// offlineSessions tient lieu de gestionnaire de sessions : celles du serveur
+// vivent dans son propre processus, qui relit l'état des comptes périodiquement
+type offlineSessions struct{}
+
+func (offlineSessions) UpdateRole(userID int, role string) {}
+
+func (offlineSessions) RevokeUser(userID int) {
+	fmt.Fprintf(os.Stderr, "Les sessions ouvertes de l'utilisateur %d seront fermées par le serveur d'ici %v\n", userID, session.StatusCheckEvery)
+}
+
+func (offlineSessions) RevokeRestricted(userID int) {}
+
+// fail affiche l'erreur et termine le programme
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "matchactl: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// output écrit le résultat des commandes en tableau ou en JSON
+type output struct {
+	json bool
+	w    io.Writer
+}
+
+// table affiche des lignes sous un en-tête ; en JSON, value est écrit tel quel
+func (o *output) table(value interface{}, header []string, rows [][]string) error {
+	if o.json {
+		return o.value(value)
+	}
+
+	tw := tabwriter.NewWriter(o.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// result affiche le compte rendu d'une action
+func (o *output) result(value interface{}, message string) error {
+	if o.json {
+		return o.value(value)
+	}
+	_, err := fmt.Fprintln(o.w, message)
+	return err
+}
+
+// value écrit value en JSON indenté
+func (o *output) value(value interface{}) error {
+	encoder := json.NewEncoder(o.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// formatTime formate une date pour les tableaux
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
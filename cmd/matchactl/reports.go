@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/cduffaut/matcha/internal/moderation"
+)
+
+// listReports affiche les signalements selon les filtres donnés
+func (a *app) listReports(args []string) error {
+	flags := flag.NewFlagSet("reports list", flag.ContinueOnError)
+	status := flags.String("status", "", "open, claimed ou resolved")
+	category := flags.String("category", "", "catégorie de signalement")
+	limit := flags.Int("limit", 50, "nombre maximum de signalements")
+	offset := flags.Int("offset", 0, "décalage dans la liste")
+	if _, err := parseArgs("reports list", flags, args, 0); err != nil {
+		return err
+	}
+
+	reports, err := a.moderation.ListReports(moderation.ReportFilter{
+		Status:   *status,
+		Category: *category,
+		Limit:    *limit,
+		Offset:   *offset,
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, 0, len(reports))
+	for _, report := range reports {
+		resolution := report.Resolution
+		if resolution == "" {
+			resolution = "-"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(report.ID), report.Category, report.Status, resolution,
+			report.Reporter.Username, report.Reported.Username,
+			formatTime(&report.CreatedAt), report.Reason,
+		})
+	}
+
+	return a.out.table(reports,
+		[]string{"ID", "CATÉGORIE", "STATUT", "ISSUE", "SIGNALÉ PAR", "SIGNALÉ", "CRÉÉ LE", "MOTIF"},
+		rows)
+}
+
+// resolveReport prend en charge puis clôt un signalement au nom de l'administrateur -as
+func (a *app) resolveReport(args []string) error {
+	flags := flag.NewFlagSet("reports resolve", flag.ContinueOnError)
+	resolution := flags.String("resolution", "", "upheld ou dismissed")
+	comment := flags.String("comment", "", "commentaire de résolution")
+	args, err := parseArgs("reports resolve", flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	reportID, err := strconv.Atoi(args[0])
+	if err != nil || reportID <= 0 {
+		return fmt.Errorf("ID de signalement invalide: %q", args[0])
+	}
+	actor, err := a.actor()
+	if err != nil {
+		return err
+	}
+
+	report, err := a.moderation.GetReport(reportID)
+	if err != nil {
+		return err
+	}
+	// Un administrateur peut reprendre un signalement attribué à un autre modérateur
+	if report.Status != moderation.StatusResolved {
+		if _, err := a.moderation.ClaimReport(actor, reportID); err != nil {
+			return err
+		}
+		a.record(actor, moderation.ActionReportClaim, moderation.TargetReport, reportID, nil)
+	}
+
+	report, err = a.moderation.ResolveReport(actor, reportID, *resolution, *comment, nil)
+	if err != nil {
+		return err
+	}
+	a.record(actor, moderation.ActionReportResolve, moderation.TargetReport, reportID, map[string]interface{}{
+		"resolution": *resolution,
+		"comment":    *comment,
+	})
+
+	return a.out.result(report, fmt.Sprintf("Signalement %d clos (%s)", report.ID, report.Resolution))
+}
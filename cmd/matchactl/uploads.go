@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cduffaut/matcha/internal/audit"
+	"github.com/cduffaut/matcha/internal/moderation"
+)
+
+// purgeUploads supprime les fichiers téléversés qu'aucune photo ne référence
+// plus, par exemple après l'échec d'un enregistrement ou la suppression d'un compte
+func (a *app) purgeUploads(args []string) error {
+	flags := flag.NewFlagSet("uploads purge", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "lister les fichiers sans les supprimer")
+	dir := flags.String("dir", "web/static/uploads", "répertoire des fichiers téléversés")
+	_, err := parseArgs("uploads purge", flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	var actor moderation.Actor
+	if !*dryRun {
+		if actor, err = a.actor(); err != nil {
+			return err
+		}
+	}
+
+	paths, err := a.profiles.ListPhotoPaths()
+	if err != nil {
+		return err
+	}
+	// Les photos sont servies sous /uploads/, relativement au répertoire des fichiers
+	referenced := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		referenced[strings.TrimPrefix(path, "/uploads/")] = true
+	}
+
+	orphans := []string{}
+	err = filepath.WalkDir(*dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(*dir, path)
+		if err != nil {
+			return err
+		}
+		if !referenced[filepath.ToSlash(rel)] {
+			orphans = append(orphans, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("erreur lors du parcours de %s: %w", *dir, err)
+	}
+
+	if !*dryRun {
+		for _, path := range orphans {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("erreur lors de la suppression de %s: %w", path, err)
+			}
+		}
+		a.record(actor, audit.ActionUploadsPurge, "", 0, map[string]interface{}{"dir": *dir, "files": len(orphans)})
+	}
+
+	if a.out.json {
+		return a.out.value(map[string]interface{}{"dry_run": *dryRun, "files": orphans})
+	}
+	for _, path := range orphans {
+		fmt.Fprintln(a.out.w, path)
+	}
+	verb := "supprimé(s)"
+	if *dryRun {
+		verb = "à supprimer"
+	}
+	_, err = fmt.Fprintf(a.out.w, "%d fichier(s) orphelin(s) %s\n", len(orphans), verb)
+	return err
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/audit"
+	"github.com/cduffaut/matcha/internal/auth"
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/cduffaut/matcha/internal/moderation"
+	"github.com/cduffaut/matcha/internal/user"
+)
+
+// parseArgs lit les options d'une commande et exige exactement want arguments
+func parseArgs(name string, flags *flag.FlagSet, args []string, want int) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() != want {
+		return nil, fmt.Errorf("%s attend %d argument(s), %d fourni(s)", name, want, flags.NArg())
+	}
+	return flags.Args(), nil
+}
+
+// findUsers affiche les comptes correspondant aux noms d'utilisateur ou emails donnés
+func (a *app) findUsers(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("users find attend au moins un nom d'utilisateur ou email")
+	}
+
+	found := []*models.User{}
+	for _, identifier := range args {
+		u, err := a.lookupUser(identifier)
+		if err != nil {
+			return err
+		}
+		found = append(found, u)
+	}
+
+	rows := make([][]string, 0, len(found))
+	for _, u := range found {
+		rows = append(rows, []string{
+			strconv.Itoa(u.ID), u.Username, u.Email, u.FirstName + " " + u.LastName,
+			strconv.FormatBool(u.IsVerified), u.Role, u.EffectiveStatus(time.Now()),
+			formatTime(u.SuspendedUntil), formatTime(&u.CreatedAt),
+		})
+	}
+
+	return a.out.table(found,
+		[]string{"ID", "UTILISATEUR", "EMAIL", "NOM", "VÉRIFIÉ", "RÔLE", "ÉTAT", "SUSPENDU JUSQU'AU", "INSCRIT LE"},
+		rows)
+}
+
+// setVerified marque l'email d'un compte comme vérifié ou non
+func (a *app) setVerified(args []string, verified bool) error {
+	if len(args) != 1 {
+		return fmt.Errorf("un seul nom d'utilisateur ou email attendu")
+	}
+	actor, err := a.actor()
+	if err != nil {
+		return err
+	}
+	u, err := a.lookupUser(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := a.users.UpdateVerificationStatus(u.ID, verified); err != nil {
+		return fmt.Errorf("erreur lors de la mise à jour de la vérification: %w", err)
+	}
+	a.record(actor, audit.ActionVerification, audit.TargetUser, u.ID, map[string]interface{}{"is_verified": verified})
+
+	message := fmt.Sprintf("%s : email marqué comme vérifié", u.Username)
+	if !verified {
+		message = fmt.Sprintf("%s : email marqué comme non vérifié", u.Username)
+	}
+	return a.out.result(map[string]interface{}{"user_id": u.ID, "is_verified": verified}, message)
+}
+
+// resetPassword envoie à l'utilisateur l'email de réinitialisation du mot de passe
+func (a *app) resetPassword(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("un seul nom d'utilisateur ou email attendu")
+	}
+	actor, err := a.actor()
+	if err != nil {
+		return err
+	}
+	u, err := a.lookupUser(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := a.auth.ForgotPassword(auth.ForgotPasswordRequest{Email: u.Email}); err != nil {
+		return fmt.Errorf("erreur lors de l'envoi de l'email de réinitialisation: %w", err)
+	}
+	a.record(actor, audit.ActionPasswordResetRequest, audit.TargetUser, u.ID, map[string]interface{}{"email": u.Email})
+
+	return a.out.result(map[string]interface{}{"user_id": u.ID, "email": u.Email},
+		fmt.Sprintf("Email de réinitialisation envoyé à %s", u.Email))
+}
+
+// sanction suspend, bannit ou rétablit un compte au nom de l'administrateur -as
+func (a *app) sanction(action string, args []string) error {
+	flags := flag.NewFlagSet("users "+action, flag.ContinueOnError)
+	reason := flags.String("reason", "", "motif communiqué à l'utilisateur")
+	hours := flags.Int("hours", 24, "durée de la suspension en heures")
+	args, err := parseArgs("users "+action, flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	actor, err := a.actor()
+	if err != nil {
+		return err
+	}
+	u, err := a.lookupUser(args[0])
+	if err != nil {
+		return err
+	}
+
+	sanction := moderation.Sanction{Action: action, Reason: *reason}
+	if action == moderation.SanctionSuspend {
+		sanction.Duration = time.Duration(*hours) * time.Hour
+	}
+	if err := a.moderation.ApplySanction(actor, u.ID, sanction); err != nil {
+		return err
+	}
+	a.record(actor, moderation.ActionAccountStatus, moderation.TargetUser, u.ID, map[string]interface{}{
+		"sanction":       action,
+		"reason":         sanction.Reason,
+		"duration_hours": int(sanction.Duration / time.Hour),
+	})
+
+	return a.out.result(map[string]interface{}{"user_id": u.ID, "sanction": action},
+		fmt.Sprintf("%s : sanction %s appliquée", u.Username, action))
+}
+
+// recomputeFame recalcule immédiatement le fame rating de tous les profils
+func (a *app) recomputeFame() error {
+	updated, err := user.NewFameJob(a.profiles, a.cfg.Fame).Recompute()
+	if err != nil {
+		return err
+	}
+
+	return a.out.result(map[string]interface{}{"updated": updated},
+		fmt.Sprintf("Fame rating recalculé : %d profil(s) mis à jour", updated))
+}
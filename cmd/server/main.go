@@ -1,18 +1,27 @@
 package main
 
 import (
+	"crypto/rand"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
+	"github.com/cduffaut/matcha/internal/audit"
 	"github.com/cduffaut/matcha/internal/auth"
 	"github.com/cduffaut/matcha/internal/chat"
 	"github.com/cduffaut/matcha/internal/config"
 	"github.com/cduffaut/matcha/internal/database"
 	"github.com/cduffaut/matcha/internal/email"
 	"github.com/cduffaut/matcha/internal/middleware"
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/cduffaut/matcha/internal/moderation"
 	"github.com/cduffaut/matcha/internal/notifications"
+	"github.com/cduffaut/matcha/internal/outbox"
+	"github.com/cduffaut/matcha/internal/push"
+	"github.com/cduffaut/matcha/internal/ratelimit"
+	"github.com/cduffaut/matcha/internal/security"
 	"github.com/cduffaut/matcha/internal/session"
 	"github.com/cduffaut/matcha/internal/user"
 	"goji.io"
@@ -54,44 +63,136 @@ func main() {
 	baseURL := fmt.Sprintf("http://localhost:%s", cfg.Server.Port)
 	authService := auth.NewService(userRepo, emailService, baseURL)
 	sessionManager := session.NewManager("matcha_session")
+	sessionManager.SetAccountLookup(userRepo.GetByID)
+
+	chatHub := &chat.Hub{
+		Clients:    make(map[int]*chat.Client),
+		Register:   make(chan *chat.Client),
+		Unregister: make(chan *chat.Client),
+		Broadcast:  make(chan []byte),
+	}
 
 	// init le sys de notifs
 	notificationRepo := notifications.NewPostgresNotificationRepository(db)
-	notificationService := notifications.NewService(notificationRepo)
+	notificationBroker := notifications.NewBroker()
+
+	// Web Push : clés VAPID depuis la config, ou générées si absentes
+	vapidKeys, err := loadVAPIDKeys(cfg.Push)
+	if err != nil {
+		log.Fatalf("Erreur lors du chargement des clés VAPID: %v", err)
+	}
+	pushRepo := push.NewPostgresRepository(db)
+	pushService := push.NewService(pushRepo, vapidKeys, cfg.Push.VAPIDSubject, func(userID int) bool {
+		return chatHub.IsConnected(userID) || notificationBroker.HasSubscribers(userID)
+	})
+	pushService.Start()
+	pushHandlers := push.NewHandlers(pushService)
+
+	notificationService := notifications.NewService(notificationRepo, notificationBroker, pushService)
 	notificationHandlers := notifications.NewHandlers(notificationService)
 
+	// outbox : effets de bord enregistrés dans la transaction métier, livrés en arrière-plan
+	outboxRepo := outbox.NewPostgresRepository(db)
+	outboxDispatcher := outbox.NewDispatcher(outboxRepo)
+
 	// service de profil
-	profileService := user.NewProfileService(profileRepo, userRepo, "web/static/uploads", notificationService)
+	profileService := user.NewProfileService(profileRepo, userRepo, "web/static/uploads", notificationService, outboxDispatcher)
 	onlineStatusMiddleware := middleware.NewOnlineStatusMiddleware(profileService)
 
-	chatHub := &chat.Hub{
-		Clients:    make(map[int]*chat.Client),
-		Register:   make(chan *chat.Client),
-		Unregister: make(chan *chat.Client),
-		Broadcast:  make(chan []byte),
-	}
+	// journal d'audit chaîné : connexions, comptes, signalements et modération
+	auditService := audit.NewService(audit.NewPostgresRepository(db))
+	security.SetSuspiciousActivityRecorder(auditService)
+	auditHandlers := audit.NewHandlers(auditService)
 
 	// init les handlers
-	authHandlers := auth.NewHandlers(authService, sessionManager, profileService)
-	profileHandlers := user.NewProfileHandlers(profileService, notificationService, chatHub)
-	browsingService := user.NewBrowsingService(userRepo, profileRepo)
+	authHandlers := auth.NewHandlers(authService, sessionManager, profileService, auditService)
+	profileHandlers := user.NewProfileHandlers(profileService, notificationService, chatHub, auditService)
+	tagIndex := user.NewTagIndex()
+	tagStatsJob := user.NewTagStatsJob(user.NewPostgresTagStatsRepository(db), tagIndex, cfg.Scoring.TagStatsEvery)
+	recommendationRepo := user.NewPostgresRecommendationRepository(db)
+	recommenderJob := user.NewRecommenderJob(recommendationRepo, cfg.Scoring.NeighborsK, cfg.Scoring.NeighborsEvery)
+	browsingService := user.NewBrowsingService(userRepo, profileRepo, user.NewWeightedScorer(cfg.Scoring, tagIndex), tagIndex, recommendationRepo, cfg.Scoring.ZonesKm, cfg.Browsing.PassCooldown, user.NewBrowseSessions(cfg.Browsing.SessionTTL))
 	browsingHandlers := user.NewBrowsingHandlers(browsingService)
+	savedSearchService := user.NewSavedSearchService(user.NewPostgresSavedSearchRepository(db), browsingService, userRepo, notificationService, emailService, baseURL, cfg.Browsing.SavedSearchEvery)
+	savedSearchHandlers := user.NewSavedSearchHandlers(savedSearchService)
 
 	// Démarrer le nettoyage périodique
 	onlineStatusMiddleware.StartCleanupRoutine()
 
+	// Fréquences des tags (IDF) pour pondérer les intérêts communs
+	tagStatsJob.Start()
+	// Voisins issus de l'historique des likes (filtrage collaboratif)
+	recommenderJob.Start()
+	// Fame rating recalculé pour tous les profils (décroissance, plafonds, percentiles)
+	fameJob := user.NewFameJob(profileRepo, cfg.Fame)
+	fameJob.Start()
+	// Alertes des recherches sauvegardées sur les nouveaux profils
+	savedSearchService.Start()
+
+	// modération : file des signalements, sanctions, rôles et journal des actions
+	moderationService := moderation.NewService(moderation.NewPostgresRepository(db), sessionManager, emailService, notificationService, baseURL)
+	moderationHandlers := moderation.NewHandlers(moderationService, auditService)
+	// Scores de risque : profils suspects masqués et soumis à l'examen des modérateurs
+	riskService := moderation.NewRiskService(moderation.NewPostgresRiskRepository(db), cfg.Risk)
+	riskService.Start()
+	riskHandlers := moderation.NewRiskHandlers(riskService, auditService)
+	// Contestations des suspensions et bannissements
+	appealService := moderation.NewAppealService(moderation.NewPostgresAppealRepository(db), sessionManager, emailService, baseURL)
+	appealHandlers := moderation.NewAppealHandlers(appealService, auditService)
+
 	// init le sys de chat
 	chatRepo := chat.NewPostgresMessageRepository(db)
-	chatService := chat.NewService(chatRepo, notificationService)
+	chatService := chat.NewService(chatRepo, notificationService, userRepo)
 	chatHandlers := chat.NewHandlers(chatService, chatHub)
 
 	go chatHub.Run()
+
+	// handlers de l'outbox
+	outboxDispatcher.Handle(outbox.EventNotification, notifications.OutboxHandler(notificationService))
+	outboxDispatcher.Handle(outbox.EventWebSocketPush, profileHandlers.HandleOutboxPush)
+	outboxDispatcher.Handle(outbox.EventFameRecompute, fameJob.HandleFameRecompute)
+	outboxDispatcher.Start()
+
+	// IP des clients : les en-têtes de proxy ne sont crus que depuis les proxies configurés
+	if err := security.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("Erreur de configuration des proxies de confiance: %v", err)
+	}
+
 	// init les middlewares
 	authMiddleware := middleware.NewAuthMiddleware(sessionManager)
+	csrfSecret, err := loadCSRFSecret(cfg.Server)
+	if err != nil {
+		log.Fatalf("Erreur lors de la génération du secret CSRF: %v", err)
+	}
+	csrfMiddleware := middleware.NewCSRFMiddleware(security.NewCSRFTokens(csrfSecret), sessionManager.CookieName)
+	// Les rapports CSP sont envoyés par le navigateur, sans jeton CSRF
+	csrfMiddleware.Exempt(security.CSPReportPath)
+	securityHeadersMiddleware := middleware.NewSecurityHeadersMiddleware(cfg.Server.CSPReportOnly)
+
+	// limitation de débit des routes coûteuses : seaux en mémoire, ou dans
+	// PostgreSQL pour être partagés entre plusieurs instances
+	rateLimitStore := ratelimit.NewMemoryStore()
+	if cfg.Server.RateLimitBackend == config.RateLimitPostgres {
+		rateLimitStore = ratelimit.NewPostgresStore(db)
+	}
+	rateLimiter := ratelimit.NewLimiter(rateLimitStore)
+	rateLimiter.Start()
+	rateLimitMiddleware := middleware.NewRateLimitMiddleware(rateLimiter)
+
+	// politiques par route : nombre de requêtes d'affilée, rendues en totalité sur la période
+	limitRegister := rateLimitMiddleware.Limit(ratelimit.Policy{Name: "register", Limit: 5, Period: time.Hour, By: ratelimit.ByIP})
+	limitSearch := rateLimitMiddleware.Limit(ratelimit.Policy{Name: "search", Limit: 30, Period: time.Minute, By: ratelimit.ByUser})
+	limitPhotoUpload := rateLimitMiddleware.Limit(ratelimit.Policy{Name: "photo_upload", Limit: 20, Period: time.Hour, By: ratelimit.ByUser})
+	limitReport := rateLimitMiddleware.Limit(ratelimit.Policy{Name: "report", Limit: 10, Period: time.Hour, By: ratelimit.ByUser})
+	limitLike := rateLimitMiddleware.Limit(ratelimit.Policy{Name: "like", Limit: 60, Period: 10 * time.Minute, By: ratelimit.ByUser})
 
 	// creation multiplexeur goji
 	mux := goji.NewMux()
-	mux.Use(middleware.CSRFMiddleware)
+	mux.Use(securityHeadersMiddleware.Apply)
+	mux.Use(csrfMiddleware.Protect)
+
+	// rapports de violation de la Content-Security-Policy
+	mux.HandleFunc(pat.Post(security.CSPReportPath), security.CSPReportHandler)
 
 	// route fichiers statiques
 	fileServer := http.FileServer(http.Dir("web/static"))
@@ -105,7 +206,7 @@ func main() {
 	mux.HandleFunc(pat.Get("/login"), authHandlers.LoginPageHandler)
 
 	// API d'auth
-	mux.HandleFunc(pat.Post("/api/register"), authHandlers.RegisterHandler)
+	mux.Handle(pat.Post("/api/register"), limitRegister(http.HandlerFunc(authHandlers.RegisterHandler)))
 	mux.HandleFunc(pat.Get("/verify-email"), authHandlers.VerifyEmailHandler)
 	mux.HandleFunc(pat.Post("/api/login"), authHandlers.LoginHandler)
 	mux.HandleFunc(pat.Get("/logout"), authHandlers.LogoutHandler)
@@ -136,10 +237,13 @@ func main() {
 	protectedMux.HandleFunc(pat.Get("/profile/:userID"), profileHandlers.ViewUserProfilePageHandler)
 	protectedMux.HandleFunc(pat.Get("/api/profile"), profileHandlers.GetProfileHandler)
 	protectedMux.HandleFunc(pat.Put("/api/profile"), profileHandlers.UpdateProfileHandler)
+	protectedMux.HandleFunc(pat.Get("/api/profile/fame-history"), profileHandlers.GetFameHistoryHandler)
+	protectedMux.HandleFunc(pat.Get("/api/profile/discovery"), profileHandlers.GetDiscoveryPreferencesHandler)
+	protectedMux.HandleFunc(pat.Put("/api/profile/discovery"), profileHandlers.UpdateDiscoveryPreferencesHandler)
 	protectedMux.HandleFunc(pat.Get("/api/profile/:userID"), profileHandlers.GetUserProfileHandler)
 
 	protectedMux.HandleFunc(pat.Get("/api/profile/:userID/status"), profileHandlers.GetUserStatusHandler)
-	protectedMux.HandleFunc(pat.Post("/api/profile/:userID/report"), profileHandlers.ReportUserHandler)
+	protectedMux.Handle(pat.Post("/api/profile/:userID/report"), limitReport(http.HandlerFunc(profileHandlers.ReportUserHandler)))
 
 	// routes pour tags
 	protectedMux.HandleFunc(pat.Get("/api/profile/tags"), profileHandlers.GetTagsHandler)
@@ -149,26 +253,41 @@ func main() {
 
 	// routes pour photos
 	protectedMux.HandleFunc(pat.Get("/api/profile/photos"), profileHandlers.GetPhotosHandler)
-	protectedMux.HandleFunc(pat.Post("/api/profile/photos"), profileHandlers.UploadPhotoHandler)
+	protectedMux.Handle(pat.Post("/api/profile/photos"), limitPhotoUpload(http.HandlerFunc(profileHandlers.UploadPhotoHandler)))
 	protectedMux.HandleFunc(pat.Delete("/api/profile/photos/:photoID"), profileHandlers.DeletePhotoHandler)
 	protectedMux.HandleFunc(pat.Put("/api/profile/photos/:photoID/set-profile"), profileHandlers.SetProfilePhotoHandler)
 
-	// routes pour likes
-	protectedMux.HandleFunc(pat.Post("/api/profile/:userID/like"), profileHandlers.LikeUserHandler)
+	// routes pour likes et passes
+	protectedMux.Handle(pat.Post("/api/profile/:userID/like"), limitLike(http.HandlerFunc(profileHandlers.LikeUserHandler)))
 	protectedMux.HandleFunc(pat.Delete("/api/profile/:userID/like"), profileHandlers.UnlikeUserHandler)
+	protectedMux.HandleFunc(pat.Post("/api/profile/pass/undo"), profileHandlers.UndoLastPassHandler)
+	protectedMux.HandleFunc(pat.Post("/api/profile/:userID/pass"), profileHandlers.PassUserHandler)
+	protectedMux.HandleFunc(pat.Delete("/api/profile/:userID/pass"), profileHandlers.UnpassUserHandler)
 
 	// routes pour navigation
 	protectedMux.HandleFunc(pat.Get("/browse"), browsingHandlers.BrowsePageHandler)
-	protectedMux.HandleFunc(pat.Get("/api/suggestions"), browsingHandlers.GetSuggestionsHandler)
-	protectedMux.HandleFunc(pat.Get("/api/search"), browsingHandlers.SearchProfilesHandler)
+	protectedMux.Handle(pat.Get("/api/suggestions"), limitSearch(http.HandlerFunc(browsingHandlers.GetSuggestionsHandler)))
+	protectedMux.Handle(pat.Get("/api/search"), limitSearch(http.HandlerFunc(browsingHandlers.SearchProfilesHandler)))
+
+	// routes pour les recherches sauvegardées
+	protectedMux.HandleFunc(pat.Get("/api/searches"), savedSearchHandlers.ListHandler)
+	protectedMux.HandleFunc(pat.Post("/api/searches"), savedSearchHandlers.CreateHandler)
+	protectedMux.HandleFunc(pat.Put("/api/searches/:searchID"), savedSearchHandlers.UpdateHandler)
+	protectedMux.HandleFunc(pat.Delete("/api/searches/:searchID"), savedSearchHandlers.DeleteHandler)
 
 	// routes pour notifications
 	protectedMux.HandleFunc(pat.Get("/api/notifications"), notificationHandlers.GetNotificationsHandler)
 	protectedMux.HandleFunc(pat.Get("/api/notifications/unread-count"), notificationHandlers.GetUnreadCountHandler)
+	protectedMux.HandleFunc(pat.Get("/api/notifications/stream"), notificationHandlers.StreamHandler)
 	protectedMux.HandleFunc(pat.Put("/api/notifications/:notificationID/read"), notificationHandlers.MarkAsReadHandler)
 	protectedMux.HandleFunc(pat.Post("/api/notifications/mark-all-read"), notificationHandlers.MarkAllAsReadHandler)
 	protectedMux.HandleFunc(pat.Get("/notifications"), notificationHandlers.NotificationsPageHandler)
 
+	// routes pour les notifications Web Push
+	protectedMux.HandleFunc(pat.Get("/api/push/public-key"), pushHandlers.PublicKeyHandler)
+	protectedMux.HandleFunc(pat.Post("/api/push/subscribe"), pushHandlers.SubscribeHandler)
+	protectedMux.HandleFunc(pat.Delete("/api/push/subscribe"), pushHandlers.UnsubscribeHandler)
+
 	// routes pour le chat
 	protectedMux.HandleFunc(pat.Get("/chat"), chatHandlers.ChatPageHandler)
 	protectedMux.HandleFunc(pat.Get("/ws"), chatHandlers.WebSocketHandler)
@@ -185,9 +304,37 @@ func main() {
 
 	// routes pour online status et signalement
 	protectedMux.HandleFunc(pat.Get("/api/profile/:userID/status"), profileHandlers.GetUserOnlineStatusHandler)
-	protectedMux.HandleFunc(pat.Post("/api/profile/:userID/report"), profileHandlers.ReportUserHandler)
+	protectedMux.Handle(pat.Post("/api/profile/:userID/report"), limitReport(http.HandlerFunc(profileHandlers.ReportUserHandler)))
 	protectedMux.HandleFunc(pat.Get("/api/geolocation"), profileHandlers.IPGeolocationHandler)
 
+	// routes d'administration : modérateurs, et administrateurs pour les rôles, les journaux et les comptes à risque
+	adminMux := goji.SubMux()
+	adminMux.Use(authMiddleware.RequireRole(models.RoleModerator))
+	requireAdmin := authMiddleware.RequireRole(models.RoleAdmin)
+	adminMux.HandleFunc(pat.Get("/reports"), moderationHandlers.ListReportsHandler)
+	adminMux.HandleFunc(pat.Get("/reports/:reportID"), moderationHandlers.GetReportHandler)
+	adminMux.HandleFunc(pat.Post("/reports/:reportID/claim"), moderationHandlers.ClaimReportHandler)
+	adminMux.HandleFunc(pat.Post("/reports/:reportID/release"), moderationHandlers.ReleaseReportHandler)
+	adminMux.HandleFunc(pat.Post("/reports/:reportID/resolve"), moderationHandlers.ResolveReportHandler)
+	adminMux.HandleFunc(pat.Post("/users/:userID/sanction"), moderationHandlers.SanctionUserHandler)
+	adminMux.HandleFunc(pat.Get("/appeals"), appealHandlers.ListAppealsHandler)
+	adminMux.HandleFunc(pat.Get("/appeals/:appealID"), appealHandlers.GetAppealHandler)
+	adminMux.HandleFunc(pat.Post("/appeals/:appealID/review"), appealHandlers.ReviewAppealHandler)
+	adminMux.HandleFunc(pat.Post("/appeals/:appealID/decide"), appealHandlers.DecideAppealHandler)
+	adminMux.Handle(pat.Get("/moderation-log"), requireAdmin(http.HandlerFunc(moderationHandlers.ListLogHandler)))
+	adminMux.Handle(pat.Put("/users/:userID/role"), requireAdmin(http.HandlerFunc(moderationHandlers.UpdateRoleHandler)))
+	adminMux.Handle(pat.Get("/risk"), requireAdmin(http.HandlerFunc(riskHandlers.ListRiskHandler)))
+	adminMux.Handle(pat.Get("/risk/:userID"), requireAdmin(http.HandlerFunc(riskHandlers.GetRiskHandler)))
+	adminMux.Handle(pat.Post("/risk/:userID/review"), requireAdmin(http.HandlerFunc(riskHandlers.ReviewRiskHandler)))
+	adminMux.Handle(pat.Get("/audit"), requireAdmin(http.HandlerFunc(auditHandlers.ListEventsHandler)))
+	adminMux.Handle(pat.Get("/audit/verify"), requireAdmin(http.HandlerFunc(auditHandlers.VerifyHandler)))
+	protectedMux.Handle(pat.New("/api/admin/*"), adminMux)
+
+	// contestation : seules routes ouvertes aux sessions restreintes (compte suspendu ou banni)
+	mux.Handle(pat.Get("/appeal"), authMiddleware.AllowRestricted(http.HandlerFunc(appealHandlers.AppealPageHandler)))
+	mux.Handle(pat.Get("/api/appeals"), authMiddleware.AllowRestricted(http.HandlerFunc(appealHandlers.GetMyAppealsHandler)))
+	mux.Handle(pat.Post("/api/appeals"), authMiddleware.AllowRestricted(http.HandlerFunc(appealHandlers.SubmitAppealHandler)))
+
 	// rep vide pour fav icon pour eviter erreur
 	mux.HandleFunc(pat.Get("/favicon.ico"), func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "image/x-icon")
@@ -203,6 +350,38 @@ func main() {
 	log.Fatal(http.ListenAndServe(serverAddr, mux))
 }
 
+// loadVAPIDKeys charge les clés VAPID configurées. En leur absence, une paire est
+// générée pour la durée du processus : les abonnements existants ne survivront pas
+// au redémarrage, il faut donc reporter les clés affichées dans .env
+func loadVAPIDKeys(cfg config.PushConfig) (*push.VAPIDKeys, error) {
+	if cfg.VAPIDPrivateKey != "" {
+		return push.ParseVAPIDKeys(cfg.VAPIDPublicKey, cfg.VAPIDPrivateKey)
+	}
+
+	keys, err := push.GenerateVAPIDKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Clés VAPID absentes, paire temporaire générée. Ajoutez dans .env :\nVAPID_PUBLIC_KEY=%s\nVAPID_PRIVATE_KEY=%s\n",
+		keys.PublicKey(), keys.PrivateKey())
+	return keys, nil
+}
+
+// loadCSRFSecret retourne le secret des jetons CSRF de la configuration, ou un
+// secret aléatoire : les sessions étant en mémoire, il n'a pas à survivre au redémarrage
+func loadCSRFSecret(cfg config.ServerConfig) ([]byte, error) {
+	if cfg.CSRFSecret != "" {
+		return []byte(cfg.CSRFSecret), nil
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
 // homeHandler gère la homepage
 func homeHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
@@ -212,7 +391,7 @@ func homeHandler(w http.ResponseWriter, r *http.Request) {
         <head>
             <title>Matcha</title>
             <meta name="viewport" content="width=device-width, initial-scale=1">
-            <style>
+            <style%[1]s>
                 body {
                     font-family: Arial, sans-serif;
                     margin: 0;
@@ -299,8 +478,9 @@ func homeHandler(w http.ResponseWriter, r *http.Request) {
                 <p>&copy; 2025 Matcha - Tous droits réservés</p>
                 <p>Trouvez l'amour, partagez vos passions</p>
             </footer>
+            <script src="/static/js/csrf.js"></script>
             <script src="/static/js/global-error-handler.js"></script>
         </body>
         </html>
-    `)
+    `, security.NonceAttr(r.Context()))
 }
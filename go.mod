@@ -3,13 +3,10 @@ module github.com/cduffaut/matcha
 go 1.23.2
 
 require (
+	github.com/gorilla/websocket v1.5.3
 	github.com/joho/godotenv v1.5.1
 	github.com/lib/pq v1.10.9
+	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
 	goji.io v2.0.2+incompatible
 	golang.org/x/crypto v0.37.0
 )
-
-require (
-	github.com/gorilla/websocket v1.5.3 // indirect
-	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd // indirect
-)
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Handlers gère les requêtes HTTP de consultation du journal d'audit
+type Handlers struct {
+	service *Service
+}
+
+// NewHandlers crée de nouveaux handlers d'audit
+func NewHandlers(service *Service) *Handlers {
+	return &Handlers{
+		service: service,
+	}
+}
+
+// writeJSON écrit une réponse JSON avec le statut donné
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// queryInt lit un paramètre entier positif, 0 s'il est absent ou invalide
+func queryInt(r *http.Request, name string) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
+
+// queryTime lit une date RFC 3339 ou AAAA-MM-JJ ; zéro si le paramètre est absent
+func queryTime(r *http.Request, name string) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errors.New("date invalide pour " + name + " (RFC 3339 ou AAAA-MM-JJ)")
+}
+
+// ListEventsHandler recherche dans le journal d'audit (filtres : actor_id,
+// action, target_type, target_id, ip, since, until, limit, offset)
+func (h *Handlers) ListEventsHandler(w http.ResponseWriter, r *http.Request) {
+	since, err := queryTime(r, "since")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	until, err := queryTime(r, "until")
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	events, err := h.service.List(Filter{
+		ActorID:    queryInt(r, "actor_id"),
+		Action:     r.URL.Query().Get("action"),
+		TargetType: r.URL.Query().Get("target_type"),
+		TargetID:   queryInt(r, "target_id"),
+		IP:         r.URL.Query().Get("ip"),
+		Since:      since,
+		Until:      until,
+		Limit:      queryInt(r, "limit"),
+		Offset:     queryInt(r, "offset"),
+	})
+	if errors.Is(err, ErrInvalidFilter) {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erreur lors de la lecture du journal d'audit"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"events": events,
+	})
+}
+
+// VerifyHandler contrôle l'intégrité de la chaîne d'événements
+func (h *Handlers) VerifyHandler(w http.ResponseWriter, r *http.Request) {
+	result, err := h.service.Verify()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "Erreur lors de la vérification du journal d'audit"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,137 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Actions enregistrées par les handlers d'authentification et de compte, et
+// par matchactl ; les décisions de modération reprennent les actions du
+// journal de modération
+const (
+	ActionLogin                = "auth.login"
+	ActionLoginFailed          = "auth.login_failed"
+	ActionLoginRestricted      = "auth.login_restricted" // Compte suspendu ou banni
+	ActionPasswordResetRequest = "auth.password_reset_request"
+	ActionPasswordReset        = "auth.password_reset"
+	ActionEmailChange          = "user.email_change"
+	ActionVerification         = "user.verification"
+	ActionBlock                = "user.block"
+	ActionUnblock              = "user.unblock"
+	ActionReport               = "user.report"
+	ActionSuspiciousInput      = "security.suspicious_input"
+	ActionUploadsPurge         = "admin.uploads_purge"
+	ActionUserExport           = "admin.user_export"
+)
+
+// TargetUser désigne un compte comme cible d'un événement ; les décisions de
+// modération reprennent les cibles du journal de modération
+const TargetUser = "user"
+
+// GenesisHash précède le premier événement de la chaîne
+var GenesisHash = strings.Repeat("0", 64)
+
+// maxPageSize borne le nombre d'événements retournés par requête
+const maxPageSize = 200
+
+// ErrInvalidFilter est retournée pour un filtre de recherche invalide
+var ErrInvalidFilter = errors.New("filtre d'audit invalide")
+
+// Event est un événement du journal d'audit
+type Event struct {
+	ID         int64                  `json:"id"`
+	ActorID    int                    `json:"actor_id,omitempty"` // 0 : visiteur anonyme ou action automatique
+	Action     string                 `json:"action"`
+	TargetType string                 `json:"target_type,omitempty"`
+	TargetID   int                    `json:"target_id,omitempty"`
+	IP         string                 `json:"ip,omitempty"`
+	UserAgent  string                 `json:"user_agent,omitempty"`
+	Details    map[string]interface{} `json:"details"`
+	CreatedAt  time.Time              `json:"created_at"`
+	PrevHash   string                 `json:"prev_hash"`
+	Hash       string                 `json:"hash"`
+}
+
+// Filter restreint la recherche d'événements ; les champs nuls sont ignorés
+type Filter struct {
+	ActorID    int
+	Action     string // Action exacte, ou préfixe terminé par « . » (ex. « auth. »)
+	TargetType string
+	TargetID   int
+	IP         string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
+}
+
+// Verification est le résultat du contrôle d'intégrité de la chaîne
+type Verification struct {
+	Valid    bool   `json:"valid"`
+	Checked  int    `json:"checked"`
+	BrokenAt int64  `json:"broken_at,omitempty"` // Premier événement dont l'empreinte ne correspond pas
+	Reason   string `json:"reason,omitempty"`
+	LastHash string `json:"last_hash,omitempty"`
+	Failures int64  `json:"append_failures"` // Événements perdus par cette instance depuis son démarrage
+}
+
+// Repository interface pour le journal d'audit, en ajout seul
+type Repository interface {
+	// Append chaîne l'événement au dernier enregistré et renseigne ID, CreatedAt, PrevHash et Hash
+	Append(event *Event) error
+	List(filter Filter) ([]*Event, error)
+	// Chain retourne les événements suivant afterID, par identifiant croissant
+	Chain(afterID int64, limit int) ([]*Event, error)
+}
+
+// ComputeHash calcule l'empreinte d'un événement à partir de celle du précédent.
+// Les détails sont normalisés pour que l'empreinte relue depuis la base soit
+// identique à celle calculée à l'écriture.
+func ComputeHash(prevHash string, event *Event) (string, error) {
+	details, err := canonicalDetails(event.Details)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal([]interface{}{
+		prevHash,
+		event.ActorID,
+		event.Action,
+		event.TargetType,
+		event.TargetID,
+		event.IP,
+		event.UserAgent,
+		json.RawMessage(details),
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", fmt.Errorf("erreur lors de la sérialisation de l'événement: %w", err)
+	}
+
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalDetails sérialise les détails sous une forme stable : clés triées,
+// nombres ramenés en flottants comme après un aller-retour par JSONB
+func canonicalDetails(details map[string]interface{}) ([]byte, error) {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la sérialisation des détails: %w", err)
+	}
+
+	var normalized interface{}
+	if err := json.Unmarshal(raw, &normalized); err != nil {
+		return nil, fmt.Errorf("erreur lors de la normalisation des détails: %w", err)
+	}
+	return json.Marshal(normalized)
+}
@@ -0,0 +1,158 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/database"
+)
+
+// chainLockKey identifie le verrou consultatif qui sérialise les écritures du journal
+const chainLockKey = 0x61756469 // "audi"
+
+// PostgresRepository implémentation PostgreSQL du journal d'audit
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository crée un nouveau repository du journal d'audit
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// Append chaîne l'événement au dernier enregistré ; les écritures concurrentes
+// sont sérialisées pour que chaque événement référence bien son prédécesseur
+func (r *PostgresRepository) Append(event *Event) error {
+	return database.WithTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1)", chainLockKey); err != nil {
+			return fmt.Errorf("erreur lors du verrouillage du journal d'audit: %w", err)
+		}
+
+		prevHash := GenesisHash
+		err := tx.QueryRow("SELECT hash FROM audit_events ORDER BY id DESC LIMIT 1").Scan(&prevHash)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("erreur lors de la lecture du dernier événement d'audit: %w", err)
+		}
+
+		// Précision de PostgreSQL, pour que l'empreinte relue soit identique
+		event.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+		event.PrevHash = prevHash
+		event.Hash, err = ComputeHash(prevHash, event)
+		if err != nil {
+			return err
+		}
+		details, err := canonicalDetails(event.Details)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO audit_events (actor_id, action, target_type, target_id, ip, user_agent,
+			                          details, created_at, prev_hash, hash)
+			VALUES (NULLIF($1, 0), $2, NULLIF($3, ''), NULLIF($4, 0), NULLIF($5, ''), NULLIF($6, ''),
+			        $7, $8, $9, $10)
+			RETURNING id
+		`
+		err = tx.QueryRow(query, event.ActorID, event.Action, event.TargetType, event.TargetID,
+			event.IP, event.UserAgent, string(details), event.CreatedAt, event.PrevHash, event.Hash).
+			Scan(&event.ID)
+		if err != nil {
+			return fmt.Errorf("erreur lors de l'enregistrement de l'événement d'audit: %w", err)
+		}
+
+		return nil
+	})
+}
+
+const eventSelect = `
+	SELECT id, COALESCE(actor_id, 0), action, COALESCE(target_type, ''), COALESCE(target_id, 0),
+	       COALESCE(ip, ''), COALESCE(user_agent, ''), details, created_at, prev_hash, hash
+	FROM audit_events
+`
+
+func scanEvent(scanner interface{ Scan(...interface{}) error }) (*Event, error) {
+	event := &Event{}
+	var details []byte
+
+	err := scanner.Scan(
+		&event.ID, &event.ActorID, &event.Action, &event.TargetType, &event.TargetID,
+		&event.IP, &event.UserAgent, &details, &event.CreatedAt, &event.PrevHash, &event.Hash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(details, &event.Details); err != nil {
+		return nil, fmt.Errorf("détails illisibles: %w", err)
+	}
+	return event, nil
+}
+
+// List récupère les événements correspondant au filtre, les plus récents d'abord
+func (r *PostgresRepository) List(filter Filter) ([]*Event, error) {
+	var conditions []string
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.ActorID > 0 {
+		conditions = append(conditions, "actor_id = "+addArg(filter.ActorID))
+	}
+	if strings.HasSuffix(filter.Action, ".") {
+		conditions = append(conditions, "starts_with(action, "+addArg(filter.Action)+")")
+	} else if filter.Action != "" {
+		conditions = append(conditions, "action = "+addArg(filter.Action))
+	}
+	if filter.TargetType != "" {
+		conditions = append(conditions, "target_type = "+addArg(filter.TargetType))
+	}
+	if filter.TargetID > 0 {
+		conditions = append(conditions, "target_id = "+addArg(filter.TargetID))
+	}
+	if filter.IP != "" {
+		conditions = append(conditions, "ip = "+addArg(filter.IP))
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= "+addArg(filter.Since))
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < "+addArg(filter.Until))
+	}
+
+	query := eventSelect
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT " + addArg(filter.Limit) + " OFFSET " + addArg(filter.Offset)
+
+	return r.query(query, args...)
+}
+
+// Chain retourne les événements suivant afterID, par identifiant croissant
+func (r *PostgresRepository) Chain(afterID int64, limit int) ([]*Event, error) {
+	return r.query(eventSelect+" WHERE id > $1 ORDER BY id LIMIT $2", afterID, limit)
+}
+
+func (r *PostgresRepository) query(query string, args ...interface{}) ([]*Event, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des événements d'audit: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un événement d'audit: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
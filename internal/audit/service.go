@@ -0,0 +1,184 @@
+package audit
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/cduffaut/matcha/internal/security"
+)
+
+// Bornes des champs libres enregistrés
+const (
+	maxUserAgentLength = 512
+	maxInputLength     = 200
+	maxIPLength        = 64 // audit_events.ip VARCHAR(64) : une valeur plus longue ferait échouer l'insertion
+)
+
+// verifyPageSize est le nombre d'événements relus à la fois lors de la vérification
+const verifyPageSize = 500
+
+// Service enregistre et consulte le journal d'audit
+type Service struct {
+	repo     Repository
+	failures atomic.Int64 // Événements perdus depuis le démarrage
+}
+
+// NewService crée un nouveau service d'audit
+func NewService(repo Repository) *Service {
+	return &Service{
+		repo: repo,
+	}
+}
+
+// Record enregistre un événement. Un échec d'écriture n'interrompt pas l'action
+// auditée : il est journalisé, compté dans Failures et retourné
+func (s *Service) Record(event *Event) error {
+	sanitizeEvent(event)
+	if err := s.repo.Append(event); err != nil {
+		s.failures.Add(1)
+		log.Printf("Erreur enregistrement de l'événement d'audit %s: %v", event.Action, err)
+		return err
+	}
+	return nil
+}
+
+// Failures retourne le nombre d'événements qui n'ont pas pu être enregistrés
+// par cette instance depuis son démarrage
+func (s *Service) Failures() int64 {
+	return s.failures.Load()
+}
+
+// RecordRequest complète l'événement avec l'IP et l'user agent de la requête
+func (s *Service) RecordRequest(r *http.Request, event *Event) error {
+	event.IP = truncate(security.ClientIP(r), maxIPLength)
+	event.UserAgent = truncate(r.UserAgent(), maxUserAgentLength)
+	return s.Record(event)
+}
+
+// RecordSuspicious enregistre une tentative d'injection détectée par le package security
+func (s *Service) RecordSuspicious(r *http.Request, userID int, input, endpoint string) {
+	s.RecordRequest(r, &Event{
+		ActorID: userID,
+		Action:  ActionSuspiciousInput,
+		Details: map[string]interface{}{
+			"endpoint": endpoint,
+			"input":    truncate(input, maxInputLength),
+		},
+	})
+}
+
+// List recherche des événements, les plus récents d'abord
+func (s *Service) List(filter Filter) ([]*Event, error) {
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return nil, fmt.Errorf("%w : since doit précéder until", ErrInvalidFilter)
+	}
+
+	if filter.Limit <= 0 || filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.List(filter)
+}
+
+// Verify relit toute la chaîne et s'arrête au premier événement dont le
+// chaînage ou l'empreinte ne correspond pas
+func (s *Service) Verify() (*Verification, error) {
+	result := &Verification{Valid: true, Failures: s.failures.Load()}
+	prevHash := GenesisHash
+	var afterID int64
+
+	for {
+		events, err := s.repo.Chain(afterID, verifyPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			hash, err := ComputeHash(event.PrevHash, event)
+			if err != nil {
+				return nil, err
+			}
+
+			switch {
+			case event.PrevHash != prevHash:
+				result.Valid, result.BrokenAt = false, event.ID
+				result.Reason = "l'événement ne référence pas le précédent (suppression ou insertion)"
+			case hash != event.Hash:
+				result.Valid, result.BrokenAt = false, event.ID
+				result.Reason = "l'empreinte ne correspond pas au contenu (modification)"
+			}
+			if !result.Valid {
+				return result, nil
+			}
+
+			result.Checked++
+			prevHash = event.Hash
+			afterID = event.ID
+		}
+
+		if len(events) < verifyPageSize {
+			if result.Checked > 0 {
+				result.LastHash = prevHash
+			}
+			return result, nil
+		}
+	}
+}
+
+// sanitizeEvent rend les champs libres acceptables par PostgreSQL avant le calcul
+// de l'empreinte : un user agent ou une saisie aux octets invalides ferait
+// échouer l'insertion, et disparaître l'événement du journal
+func sanitizeEvent(event *Event) {
+	event.IP = sanitizeText(event.IP)
+	event.UserAgent = sanitizeText(event.UserAgent)
+	for key, value := range event.Details {
+		event.Details[key] = sanitizeValue(value)
+	}
+}
+
+func sanitizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return sanitizeText(v)
+	case []string:
+		sanitized := make([]string, len(v))
+		for i, item := range v {
+			sanitized[i] = sanitizeText(item)
+		}
+		return sanitized
+	case []interface{}:
+		sanitized := make([]interface{}, len(v))
+		for i, item := range v {
+			sanitized[i] = sanitizeValue(item)
+		}
+		return sanitized
+	case map[string]interface{}:
+		sanitized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			sanitized[sanitizeText(key)] = sanitizeValue(item)
+		}
+		return sanitized
+	default:
+		return value
+	}
+}
+
+// sanitizeText remplace les octets UTF-8 invalides et retire les caractères
+// NUL, refusés par les colonnes TEXT et JSONB
+func sanitizeText(value string) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(value, "\uFFFD"), "\x00", "")
+}
+
+// truncate borne une chaîne à max caractères
+func truncate(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// strictRepository refuse, comme PostgreSQL, l'UTF-8 invalide et les caractères NUL
+type strictRepository struct {
+	events []*Event
+	fail   bool
+}
+
+func (r *strictRepository) Append(event *Event) error {
+	if r.fail {
+		return errors.New("base indisponible")
+	}
+
+	texts := []string{event.IP, event.UserAgent}
+	for key, value := range event.Details {
+		texts = append(texts, key)
+		if text, ok := value.(string); ok {
+			texts = append(texts, text)
+		}
+	}
+	for _, text := range texts {
+		if !utf8.ValidString(text) || strings.ContainsRune(text, 0) {
+			return errors.New("invalid byte sequence for encoding UTF8")
+		}
+	}
+
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *strictRepository) List(filter Filter) ([]*Event, error) { return r.events, nil }
+
+func (r *strictRepository) Chain(afterID int64, limit int) ([]*Event, error) { return nil, nil }
+
+func TestRecordRequestSanitizesInvalidText(t *testing.T) {
+	repo := &strictRepository{}
+	service := NewService(repo)
+
+	r := httptest.NewRequest("POST", "/login", nil)
+	r.Header.Set("User-Agent", "curl\xff\xfe")
+
+	err := service.RecordRequest(r, &Event{
+		Action:  ActionLoginFailed,
+		Details: map[string]interface{}{"username": "ad\x00min\x80"},
+	})
+	if err != nil {
+		t.Fatalf("RecordRequest: %v", err)
+	}
+
+	event := repo.events[0]
+	if event.UserAgent != "curl�" {
+		t.Errorf("UserAgent = %q, attendu %q", event.UserAgent, "curl�")
+	}
+	if got := event.Details["username"]; got != "admin�" {
+		t.Errorf("username = %q, attendu %q", got, "admin�")
+	}
+}
+
+func TestRecordCountsFailures(t *testing.T) {
+	service := NewService(&strictRepository{fail: true})
+
+	for i := 0; i < 2; i++ {
+		if err := service.Record(&Event{Action: ActionLogin}); err == nil {
+			t.Fatal("Record devrait retourner l'échec d'écriture")
+		}
+	}
+
+	if got := service.Failures(); got != 2 {
+		t.Errorf("Failures = %d, attendu 2", got)
+	}
+	result, err := service.Verify()
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if result.Failures != 2 {
+		t.Errorf("Verification.Failures = %d, attendu 2", result.Failures)
+	}
+}
@@ -2,10 +2,12 @@ package auth
 
 import (
     "encoding/json"
+    "errors"
     "fmt"
     "net/http"
     "strings"
 
+    "github.com/cduffaut/matcha/internal/audit"
     "github.com/cduffaut/matcha/internal/security"
     "github.com/cduffaut/matcha/internal/session"
     "github.com/cduffaut/matcha/internal/user"
@@ -17,14 +19,16 @@ type Handlers struct {
     service        *Service
     sessionManager *session.Manager
     profileService *user.ProfileService
+    audit          *audit.Service
 }
 
 // cree des news gestionnaires pour l'auth
-func NewHandlers(service *Service, sessionManager *session.Manager, profileService *user.ProfileService) *Handlers {
+func NewHandlers(service *Service, sessionManager *session.Manager, profileService *user.ProfileService, auditService *audit.Service) *Handlers {
     return &Handlers{
         service:        service,
         sessionManager: sessionManager,
         profileService: profileService,
+        audit:          auditService,
     }
 }
 
@@ -78,7 +82,7 @@ func (h *Handlers) RegisterHandler(w http.ResponseWriter, r *http.Request) {
         {req.LastName, "lastname"},
     } {
         if err := security.ValidateUserInput(field.value, field.name); err != nil {
-            security.LogSuspiciousActivity(0, field.value, "/api/register")
+            security.LogSuspiciousActivity(r, 0, field.value, "/api/register")
             w.WriteHeader(http.StatusBadRequest)
             json.NewEncoder(w).Encode(map[string]string{
                 "error": "Données invalides détectées",
@@ -181,7 +185,7 @@ func (h *Handlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
 
     // verifier les injections SQL
     if err := security.ValidateUserInput(req.Username, "username"); err != nil {
-        security.LogSuspiciousActivity(0, req.Username, "/api/login")
+        security.LogSuspiciousActivity(r, 0, req.Username, "/api/login")
         w.Header().Set("Content-Type", "application/json")
         w.WriteHeader(http.StatusBadRequest)
         json.NewEncoder(w).Encode(map[string]string{
@@ -192,7 +196,36 @@ func (h *Handlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
 
     // connecter le user
     user, err := h.service.Login(req)
+    var locked *AccountLockedError
+    if errors.As(err, &locked) {
+        h.audit.RecordRequest(r, &audit.Event{
+            ActorID:    locked.User.ID,
+            Action:     audit.ActionLoginRestricted,
+            TargetType: audit.TargetUser,
+            TargetID:   locked.User.ID,
+            Details:    map[string]interface{}{"account_status": locked.Status},
+        })
+
+        // session restreinte : le user peut seulement contester la decision
+        response := map[string]interface{}{
+            "error":          locked.Error(),
+            "account_status": locked.Status,
+            "reason":         locked.Reason,
+        }
+        if _, err := h.sessionManager.CreateRestrictedSession(w, locked.User); err == nil {
+            response["appeal_url"] = "/appeal"
+        }
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusForbidden)
+        json.NewEncoder(w).Encode(response)
+        return
+    }
     if err != nil {
+        h.audit.RecordRequest(r, &audit.Event{
+            Action:  audit.ActionLoginFailed,
+            Details: map[string]interface{}{"username": req.Username},
+        })
+
         // retourner JSON positive
         w.Header().Set("Content-Type", "application/json")
         w.WriteHeader(http.StatusUnauthorized) // 401 au lieu de 500
@@ -213,6 +246,13 @@ func (h *Handlers) LoginHandler(w http.ResponseWriter, r *http.Request) {
         return
     }
 
+    h.audit.RecordRequest(r, &audit.Event{
+        ActorID:    user.ID,
+        Action:     audit.ActionLogin,
+        TargetType: audit.TargetUser,
+        TargetID:   user.ID,
+    })
+
     if h.profileService != nil {
         go func() {
             // M à J du statut en ligne et la dernière connexion
@@ -297,7 +337,7 @@ func (h *Handlers) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request)
     }
 
     if err := security.ValidateUserInput(req.Email, "email"); err != nil {
-        security.LogSuspiciousActivity(0, req.Email, "/api/forgot-password")
+        security.LogSuspiciousActivity(r, 0, req.Email, "/api/forgot-password")
         w.WriteHeader(http.StatusBadRequest)
         json.NewEncoder(w).Encode(map[string]string{
             "error": "Données invalides détectées",
@@ -305,6 +345,12 @@ func (h *Handlers) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request)
         return
     }
 
+    // trace la demande, que l'email existe ou non
+    h.audit.RecordRequest(r, &audit.Event{
+        Action:  audit.ActionPasswordResetRequest,
+        Details: map[string]interface{}{"email": req.Email},
+    })
+
     // env l'email de reinitialisation
     err := h.service.ForgotPassword(req)
     if err != nil {
@@ -366,7 +412,7 @@ func (h *Handlers) ResetPasswordHandler(w http.ResponseWriter, r *http.Request)
     }
 
     // reinitialiser le mdp
-    err := h.service.ResetPassword(req)
+    userID, err := h.service.ResetPassword(req)
     if err != nil {
         w.WriteHeader(http.StatusBadRequest)
         json.NewEncoder(w).Encode(map[string]string{
@@ -375,6 +421,13 @@ func (h *Handlers) ResetPasswordHandler(w http.ResponseWriter, r *http.Request)
         return
     }
 
+    h.audit.RecordRequest(r, &audit.Event{
+        ActorID:    userID,
+        Action:     audit.ActionPasswordReset,
+        TargetType: audit.TargetUser,
+        TargetID:   userID,
+    })
+
     // rep avec succes en JSON
     w.WriteHeader(http.StatusOK)
     json.NewEncoder(w).Encode(map[string]string{
@@ -392,6 +445,7 @@ func (h *Handlers) RegisterPageHandler(w http.ResponseWriter, r *http.Request) {
         <head>
             <title>Inscription - Matcha</title>
             <meta name="viewport" content="width=device-width, initial-scale=1">
+            %s
             <link rel="stylesheet" href="/static/css/auth.css">
         </head>
         <body>
@@ -426,11 +480,12 @@ func (h *Handlers) RegisterPageHandler(w http.ResponseWriter, r *http.Request) {
                 </form>
                 <p>Déjà inscrit ? <a href="/login">Connexion</a></p>
             </div>
+            <script src="/static/js/csrf.js"></script>
             <script src="/static/js/global-error-handler.js"></script>
             <script src="/static/js/auth.js"></script>
         </body>
         </html>
-    `)
+    `, security.CSRFMetaTag(r.Context()))
 }
 
 // affiche la page de connexion
@@ -442,6 +497,7 @@ func (h *Handlers) LoginPageHandler(w http.ResponseWriter, r *http.Request) {
         <head>
             <title>Connexion - Matcha</title>
             <meta name="viewport" content="width=device-width, initial-scale=1">
+            %s
             <link rel="stylesheet" href="/static/css/auth.css">
         </head>
         <body>
@@ -461,11 +517,12 @@ func (h *Handlers) LoginPageHandler(w http.ResponseWriter, r *http.Request) {
                 <p><a href="/forgot-password">Mot de passe oublié ?</a></p>
                 <p>Pas encore inscrit ? <a href="/register">Inscription</a></p>
             </div>
+            <script src="/static/js/csrf.js"></script>
             <script src="/static/js/global-error-handler.js"></script>
             <script src="/static/js/auth.js"></script>
         </body>
         </html>
-    `)
+    `, security.CSRFMetaTag(r.Context()))
 }
 
 // affiche la page de recuperation de mdp
@@ -477,6 +534,7 @@ func (h *Handlers) ForgotPasswordPageHandler(w http.ResponseWriter, r *http.Requ
         <head>
             <title>Mot de passe oublié - Matcha</title>
             <meta name="viewport" content="width=device-width, initial-scale=1">
+            %s
             <link rel="stylesheet" href="/static/css/auth.css">
         </head>
         <body>
@@ -492,11 +550,12 @@ func (h *Handlers) ForgotPasswordPageHandler(w http.ResponseWriter, r *http.Requ
                 </form>
                 <p><a href="/login">← Retour à la connexion</a></p>
             </div>
+            <script src="/static/js/csrf.js"></script>
             <script src="/static/js/global-error-handler.js"></script>
             <script src="/static/js/auth.js"></script>
         </body>
         </html>
-    `)
+    `, security.CSRFMetaTag(r.Context()))
 }
 
 // affiche la page de reinitialisation
@@ -514,6 +573,7 @@ func (h *Handlers) ResetPasswordPageHandler(w http.ResponseWriter, r *http.Reque
 <head>
     <title>Réinitialiser mot de passe - Matcha</title>
     <meta name="viewport" content="width=device-width, initial-scale=1">
+    %s
     <link rel="stylesheet" href="/static/css/auth.css">
 </head>
 <body>
@@ -533,7 +593,8 @@ func (h *Handlers) ResetPasswordPageHandler(w http.ResponseWriter, r *http.Reque
             <button type="submit">Réinitialiser</button>
         </form>
     </div>
-    <script>
+    <script src="/static/js/csrf.js"></script>
+    <script%s>
         document.getElementById('reset-password-form').addEventListener('submit', async function(e) {
             e.preventDefault();
             
@@ -570,7 +631,7 @@ func (h *Handlers) ResetPasswordPageHandler(w http.ResponseWriter, r *http.Reque
     </script>
 </body>
 </html>
-    `, token)
+    `, security.CSRFMetaTag(r.Context()), token, security.NonceAttr(r.Context()))
 }
 
 // gere la m à j des infos user (nom, prenom, email)
@@ -614,7 +675,7 @@ func (h *Handlers) UpdateUserInfoHandler(w http.ResponseWriter, r *http.Request)
         {req.Email, "email"},
     } {
         if err := security.ValidateUserInput(field.value, field.name); err != nil {
-            security.LogSuspiciousActivity(userSession.UserID, field.value, "/api/user/update")
+            security.LogSuspiciousActivity(r, userSession.UserID, field.value, "/api/user/update")
             w.Header().Set("Content-Type", "application/json")
             w.WriteHeader(http.StatusBadRequest)
             json.NewEncoder(w).Encode(map[string]string{
@@ -625,7 +686,7 @@ func (h *Handlers) UpdateUserInfoHandler(w http.ResponseWriter, r *http.Request)
     }
 
     // m à j les informations
-    err := h.service.UpdateUserInfo(userSession.UserID, req)
+    previousEmail, err := h.service.UpdateUserInfo(userSession.UserID, req)
     if err != nil {
         w.Header().Set("Content-Type", "application/json")
         w.WriteHeader(http.StatusBadRequest)
@@ -635,6 +696,16 @@ func (h *Handlers) UpdateUserInfoHandler(w http.ResponseWriter, r *http.Request)
         return
     }
 
+    if previousEmail != req.Email {
+        h.audit.RecordRequest(r, &audit.Event{
+            ActorID:    userSession.UserID,
+            Action:     audit.ActionEmailChange,
+            TargetType: audit.TargetUser,
+            TargetID:   userSession.UserID,
+            Details:    map[string]interface{}{"previous_email": previousEmail, "email": req.Email},
+        })
+    }
+
     // rep succes
     w.Header().Set("Content-Type", "application/json")
     json.NewEncoder(w).Encode(map[string]string{
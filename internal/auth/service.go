@@ -30,6 +30,24 @@ func NewService(userRepo user.Repository, emailService *email.Service, baseURL s
 	}
 }
 
+// AccountLockedError est retournée par Login pour un compte suspendu ou banni
+type AccountLockedError struct {
+	Status string
+	Until  *time.Time
+	Reason string
+	User   *models.User // Pour ouvrir une session restreinte à la contestation
+}
+
+func (e *AccountLockedError) Error() string {
+	if e.Status == models.AccountBanned {
+		return "votre compte a été banni"
+	}
+	if e.Until != nil {
+		return fmt.Sprintf("votre compte est suspendu jusqu'au %s", e.Until.Format("02/01/2006 15:04"))
+	}
+	return "votre compte est suspendu"
+}
+
 // data pour l'inscription
 type RegisterRequest struct {
 	Username  string `json:"username"`
@@ -152,6 +170,16 @@ func (s *Service) Login(req LoginRequest) (*models.User, error) {
 		return nil, fmt.Errorf("nom d'utilisateur ou mot de passe incorrect")
 	}
 
+	// l'etat du compte n'est revele qu'apres un mdp correct
+	if user.IsLocked(time.Now()) {
+		return nil, &AccountLockedError{
+			Status: user.EffectiveStatus(time.Now()),
+			Until:  user.SuspendedUntil,
+			Reason: user.StatusReason,
+			User:   user,
+		}
+	}
+
 	return user, nil
 }
 
@@ -188,25 +216,25 @@ func (s *Service) ForgotPassword(req ForgotPasswordRequest) error {
 	return nil
 }
 
-// reinit le mdp d'un user
-func (s *Service) ResetPassword(req ResetPasswordRequest) error {
+// reinit le mdp d'un user et retourne son ID
+func (s *Service) ResetPassword(req ResetPasswordRequest) (int, error) {
 	user, err := s.userRepo.GetByResetToken(req.Token)
 	if err != nil {
-		return fmt.Errorf("token de réinitialisation invalide ou expiré: %w", err)
+		return 0, fmt.Errorf("token de réinitialisation invalide ou expiré: %w", err)
 	}
 
 	// hash du nouveau mdp
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return fmt.Errorf("erreur lors du hachage du mot de passe: %w", err)
+		return 0, fmt.Errorf("erreur lors du hachage du mot de passe: %w", err)
 	}
 
 	// m à j le mdp
 	if err := s.userRepo.UpdatePassword(user.ID, string(hashedPassword)); err != nil {
-		return fmt.Errorf("erreur lors de la mise à jour du mot de passe: %w", err)
+		return 0, fmt.Errorf("erreur lors de la mise à jour du mot de passe: %w", err)
 	}
 
-	return nil
+	return user.ID, nil
 }
 
 // gen un token aleatoire de la taille donnee
@@ -218,35 +246,40 @@ func generateRandomToken(length int) (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
-// m à j les infos de base d'un user
-func (s *Service) UpdateUserInfo(userID int, req UpdateUserInfoRequest) error {
+// m à j les infos de base d'un user et retourne son email precedent
+func (s *Service) UpdateUserInfo(userID int, req UpdateUserInfoRequest) (string, error) {
 	if err := validation.ValidateName(req.FirstName, "prénom"); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := validation.ValidateName(req.LastName, "nom"); err != nil {
-		return err
+		return "", err
 	}
 
 	if err := validation.ValidateEmail(req.Email); err != nil {
-		return err
+		return "", err
+	}
+
+	current, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return "", fmt.Errorf("erreur lors de la récupération de l'utilisateur: %w", err)
 	}
 
 	// verif si le mail n'est pas deja utilise par un autre user
 	emailExists, err := s.userRepo.CheckEmailExists(req.Email, userID)
 	if err != nil {
-		return fmt.Errorf("erreur lors de la vérification de l'email: %w", err)
+		return "", fmt.Errorf("erreur lors de la vérification de l'email: %w", err)
 	}
 
 	if emailExists {
-		return fmt.Errorf("cette adresse email est déjà utilisée")
+		return "", fmt.Errorf("cette adresse email est déjà utilisée")
 	}
 
 	// m à j les infos
 	err = s.userRepo.UpdateUserInfo(userID, req.FirstName, req.LastName, req.Email)
 	if err != nil {
-		return fmt.Errorf("erreur lors de la mise à jour des informations: %w", err)
+		return "", fmt.Errorf("erreur lors de la mise à jour des informations: %w", err)
 	}
 
-	return nil
+	return current.Email, nil
 }
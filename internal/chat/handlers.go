@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/cduffaut/matcha/internal/security"
 	"github.com/cduffaut/matcha/internal/session"
 	"github.com/gorilla/websocket"
 	"goji.io/pat"
@@ -256,11 +257,12 @@ func (h *Handlers) ChatPageHandler(w http.ResponseWriter, r *http.Request) {
 
 	// ✅ PASSER L'ID UTILISATEUR
 	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
-	w.Write([]byte(generateChatHTML(conversations, userSession.UserID)))
+	w.Write([]byte(generateChatHTML(conversations, userSession.UserID, security.NonceAttr(r.Context()))))
 }
 
-// generateChatHTML génère le HTML pour la page de chat
-func generateChatHTML(conversations []*Conversation, userID int) string {
+// generateChatHTML génère le HTML pour la page de chat ; nonceAttr est posé
+// sur les balises inline autorisées par la CSP
+func generateChatHTML(conversations []*Conversation, userID int, nonceAttr string) string {
 	html := `<!DOCTYPE html>
 <html lang="fr">
 <head>
@@ -311,16 +313,17 @@ func generateChatHTML(conversations []*Conversation, userID int) string {
 		</div>
     </div>
     
-    <script>
+    <script` + nonceAttr + `>
         window.currentUserId = ` + strconv.Itoa(userID) + `;
     </script>
 
-	<style>
+	<style` + nonceAttr + `>
 	input[type="text"], input[type="email"], input[type="password"], textarea {
 		font-size: 16px !important;
 	}
 	</style>
     
+	<script src="/static/js/csrf.js"></script>
 	<script src="/static/js/global-error-handler.js"></script>
     <script src="/static/js/chat.js"></script>
 	<script src="/static/js/notifications_unified.js"></script>
@@ -356,6 +359,9 @@ func (h *Handlers) broadcastMessage(message *Message) {
 
 	// ✅ DIFFUSER VERS LES DEUX PARTICIPANTS
 	participants := []int{message.SenderID, message.RecipientID}
+	if message.Hidden {
+		participants = participants[:1]
+	}
 	for _, userID := range participants {
 		if client, ok := h.hub.Clients[userID]; ok {
 			select {
@@ -2,6 +2,8 @@ package chat
 
 import (
 	"time"
+
+	"github.com/cduffaut/matcha/internal/models"
 )
 
 // Message représente un message entre deux utilisateurs
@@ -12,6 +14,7 @@ type Message struct {
 	Content     string    `json:"content" db:"content"`
 	IsRead      bool      `json:"is_read" db:"is_read"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	Hidden      bool      `json:"-" db:"is_hidden"` // Expéditeur shadow-banni : invisible du destinataire
 
 	// Informations supplémentaires pour l'affichage
 	SenderUsername string `json:"sender_username,omitempty" db:"-"`
@@ -52,6 +55,11 @@ type MessageRepository interface {
 	CanChat(userID1, userID2 int) (bool, error)
 }
 
+// UserLookup donne accès à l'état du compte des expéditeurs
+type UserLookup interface {
+	GetByID(id int) (*models.User, error)
+}
+
 // MessageService interface pour la logique métier des messages
 type MessageService interface {
 	// Envoyer un message
@@ -119,6 +127,12 @@ type Hub struct {
 	Broadcast chan []byte
 }
 
+// IsConnected indique si l'utilisateur a une connexion WebSocket ouverte
+func (h *Hub) IsConnected(userID int) bool {
+	_, ok := h.Clients[userID]
+	return ok
+}
+
 // Run démarre le hub WebSocket
 func (h *Hub) Run() {
 	for {
@@ -18,8 +18,8 @@ func NewPostgresMessageRepository(db *sql.DB) MessageRepository {
 
 func (r *PostgresMessageRepository) CreateMessage(message *Message) error {
 	query := `
-		INSERT INTO messages (sender_id, recipient_id, content, is_read, created_at)
-		VALUES ($1, $2, $3, $4, NOW() AT TIME ZONE 'UTC')
+		INSERT INTO messages (sender_id, recipient_id, content, is_read, is_hidden, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW() AT TIME ZONE 'UTC')
 		RETURNING id, created_at
 	`
 
@@ -29,6 +29,7 @@ func (r *PostgresMessageRepository) CreateMessage(message *Message) error {
 		message.RecipientID,
 		message.Content,
 		message.IsRead,
+		message.Hidden,
 	).Scan(&message.ID, &message.CreatedAt)
 
 	if err != nil {
@@ -46,8 +47,9 @@ func (r *PostgresMessageRepository) GetMessages(userID1, userID2 int, limit, off
 			   u.username, CONCAT(u.first_name, ' ', u.last_name) as sender_name
 		FROM messages m
 		JOIN users u ON m.sender_id = u.id
-		WHERE (m.sender_id = $1 AND m.recipient_id = $2) 
-		   OR (m.sender_id = $2 AND m.recipient_id = $1)
+		WHERE ((m.sender_id = $1 AND m.recipient_id = $2) 
+		   OR (m.sender_id = $2 AND m.recipient_id = $1))
+		AND (NOT m.is_hidden OR m.sender_id = $1)
 		ORDER BY m.created_at ASC
 		LIMIT $3 OFFSET $4
 	`
@@ -142,7 +144,8 @@ func (r *PostgresMessageRepository) GetConversations(userID int) ([]*Conversatio
 		messageQuery := `
 			SELECT id, content, sender_id, created_at
 			FROM messages 
-			WHERE (sender_id = $1 AND recipient_id = $2) OR (sender_id = $2 AND recipient_id = $1)
+			WHERE ((sender_id = $1 AND recipient_id = $2) OR (sender_id = $2 AND recipient_id = $1))
+			AND (NOT is_hidden OR sender_id = $1)
 			ORDER BY created_at DESC
 			LIMIT 1
 		`
@@ -170,7 +173,7 @@ func (r *PostgresMessageRepository) GetConversations(userID int) ([]*Conversatio
 		unreadQuery := `
 			SELECT COUNT(*) 
 			FROM messages 
-			WHERE sender_id = $1 AND recipient_id = $2 AND is_read = FALSE
+			WHERE sender_id = $1 AND recipient_id = $2 AND is_read = FALSE AND NOT is_hidden
 		`
 
 		err = r.db.QueryRow(unreadQuery, matchedUserID, userID).Scan(&conv.UnreadCount)
@@ -224,7 +227,7 @@ func (r *PostgresMessageRepository) GetUnreadMessageCount(userID int) (int, erro
 	query := `
 		SELECT COUNT(*) 
 		FROM messages 
-		WHERE recipient_id = $1 AND is_read = FALSE
+		WHERE recipient_id = $1 AND is_read = FALSE AND NOT is_hidden
 	`
 
 	var count int
@@ -241,7 +244,7 @@ func (r *PostgresMessageRepository) GetUnreadCountForConversation(userID, otherU
 	query := `
 		SELECT COUNT(*) 
 		FROM messages 
-		WHERE sender_id = $1 AND recipient_id = $2 AND is_read = FALSE
+		WHERE sender_id = $1 AND recipient_id = $2 AND is_read = FALSE AND NOT is_hidden
 	`
 
 	var count int
@@ -3,7 +3,9 @@ package chat
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/cduffaut/matcha/internal/models"
 	"github.com/cduffaut/matcha/internal/notifications"
 )
 
@@ -11,18 +13,29 @@ import (
 type Service struct {
 	messageRepo         MessageRepository
 	notificationService notifications.NotificationService
+	users               UserLookup
 }
 
 // NewService crée un nouveau service de chat
-func NewService(messageRepo MessageRepository, notificationService notifications.NotificationService) MessageService {
+func NewService(messageRepo MessageRepository, notificationService notifications.NotificationService, users UserLookup) MessageService {
 	return &Service{
 		messageRepo:         messageRepo,
 		notificationService: notificationService,
+		users:               users,
 	}
 }
 
 // SendMessage envoie un message
 func (s *Service) SendMessage(senderID, recipientID int, content string) (*Message, error) {
+	// Un compte suspendu ou banni ne peut plus écrire, même avec une connexion encore ouverte
+	sender, err := s.users.GetByID(senderID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération de l'expéditeur: %w", err)
+	}
+	if sender.IsLocked(time.Now()) {
+		return nil, fmt.Errorf("votre compte est suspendu, vous ne pouvez pas envoyer de message")
+	}
+
 	// Vérifier que les utilisateurs peuvent discuter
 	canChat, err := s.messageRepo.CanChat(senderID, recipientID)
 	if err != nil {
@@ -49,6 +62,7 @@ func (s *Service) SendMessage(senderID, recipientID int, content string) (*Messa
 		RecipientID: recipientID,
 		Content:     content,
 		IsRead:      false,
+		Hidden:      sender.EffectiveStatus(time.Now()) == models.AccountShadowBanned,
 	}
 
 	// Sauvegarder le message
@@ -56,6 +70,11 @@ func (s *Service) SendMessage(senderID, recipientID int, content string) (*Messa
 		return nil, fmt.Errorf("erreur lors de la création du message: %w", err)
 	}
 
+	// Message d'un compte shadow-banni : l'expéditeur le voit comme envoyé, le destinataire n'en sait rien
+	if message.Hidden {
+		return message, nil
+	}
+
 	// Créer une notification pour le destinataire
 	messagePreview := content
 	if len(messagePreview) > 50 {
@@ -1,7 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"net"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -10,13 +15,32 @@ import (
 type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
+	Push     PushConfig
+	Scoring  ScoringConfig
+	Browsing BrowsingConfig
+	Fame     FameConfig
+	Risk     RiskConfig
 }
 
 // ServerConfig contient la configuration du serveur web
 type ServerConfig struct {
-	Port string
+	Port          string
+	CSRFSecret    string // Secret de signature des jetons CSRF ; généré au démarrage s'il est vide
+	CSPReportOnly bool   // Politique CSP signalée sans être appliquée, le temps de corriger les violations
+	// Stockage des limites de débit : RateLimitMemory, ou RateLimitPostgres pour
+	// les partager entre plusieurs instances
+	RateLimitBackend string
+	// Reverse proxies (IP ou CIDR) autorisés à transmettre l'IP du client par
+	// X-Forwarded-For ; vide, l'adresse de connexion fait foi
+	TrustedProxies []string
 }
 
+// Stockages possibles des limites de débit
+const (
+	RateLimitMemory   = "memory"
+	RateLimitPostgres = "postgres"
+)
+
 // DatabaseConfig contient la configuration de la base de données
 type DatabaseConfig struct {
 	Host     string
@@ -26,6 +50,55 @@ type DatabaseConfig struct {
 	Name     string
 }
 
+// PushConfig contient les clés VAPID utilisées pour les notifications Web Push
+type PushConfig struct {
+	VAPIDPublicKey  string
+	VAPIDPrivateKey string
+	VAPIDSubject    string
+}
+
+// ScoringConfig contient les paramètres du classement des suggestions
+type ScoringConfig struct {
+	DistanceWeight float64       // Poids de la proximité
+	TagsWeight     float64       // Poids des intérêts communs
+	FameWeight     float64       // Poids du fame rating
+	BehaviorWeight float64       // Poids de la proximité avec les profils déjà likés (filtrage collaboratif)
+	MaxDistanceKm  float64       // Distance à partir de laquelle la proximité vaut 0
+	MaxCommonTags  int           // Nombre de tags communs donnant le score maximal (sans pondération IDF)
+	MaxFameRating  int           // Fame rating donnant le score maximal
+	ZonesKm        []float64     // Limites croissantes des zones géographiques prioritaires
+	TagStatsEvery  time.Duration // Période de recalcul des fréquences de tags (IDF)
+	NeighborsK     int           // Nombre de voisins conservés par profil pour le filtrage collaboratif
+	NeighborsEvery time.Duration // Période de recalcul des voisins
+}
+
+// BrowsingConfig contient les paramètres de navigation entre profils
+type BrowsingConfig struct {
+	PassCooldown     time.Duration // Délai avant qu'un profil écarté soit reproposé (0 = jamais)
+	SavedSearchEvery time.Duration // Période d'exécution des recherches sauvegardées
+	SessionTTL       time.Duration // Durée de conservation d'un classement paginé sans activité
+}
+
+// FameConfig contient les paramètres du recalcul périodique du fame rating
+type FameConfig struct {
+	HalfLife     time.Duration // Demi-vie du poids d'une visite, d'un like ou d'un match
+	ActorCap     float64       // Contribution maximale d'un même utilisateur au fame d'un autre
+	RefreshEvery time.Duration // Période du recalcul
+}
+
+// RiskConfig contient les seuils de détection des comptes à risque
+type RiskConfig struct {
+	Window              time.Duration // Fenêtre d'analyse des événements récents
+	RefreshEvery        time.Duration // Période du recalcul des scores
+	HideScore           float64       // Score à partir duquel le profil est masqué des suggestions
+	Reporters           int           // Signaleurs distincts sur la fenêtre
+	LikesPerHour        int           // Likes envoyés sur une heure glissante
+	DuplicateMessages   int           // Destinataires distincts d'un même message
+	NewAccountAge       time.Duration // Âge en deçà duquel un compte est considéré comme récent
+	NewAccountActions   int           // Likes et messages d'un compte récent depuis son inscription
+	SharedPhotoAccounts int           // Autres comptes possédant une photo identique
+}
+
 // Load charge la configuration depuis les variables d'environnement
 func Load() (*Config, error) {
 	// Charger les variables d'environnement depuis .env si présent
@@ -37,6 +110,42 @@ func Load() (*Config, error) {
 		serverPort = "8080"
 	}
 
+	// Politique CSP appliquée par défaut ; en mode rapport, les violations sont
+	// seulement envoyées à /csp-report
+	cspReportOnly := false
+	if value := os.Getenv("CSP_REPORT_ONLY"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("valeur invalide pour CSP_REPORT_ONLY: %q", value)
+		}
+		cspReportOnly = parsed
+	}
+
+	// Stockage des limites de débit, en mémoire par défaut
+	rateLimitBackend := os.Getenv("RATE_LIMIT_BACKEND")
+	switch rateLimitBackend {
+	case "":
+		rateLimitBackend = RateLimitMemory
+	case RateLimitMemory, RateLimitPostgres:
+	default:
+		return nil, fmt.Errorf("valeur invalide pour RATE_LIMIT_BACKEND: %q", rateLimitBackend)
+	}
+
+	// Reverse proxies de confiance, séparés par des virgules
+	var trustedProxies []string
+	if value := os.Getenv("TRUSTED_PROXIES"); value != "" {
+		for _, proxy := range strings.Split(value, ",") {
+			proxy = strings.TrimSpace(proxy)
+			if proxy == "" {
+				continue
+			}
+			if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+				return nil, fmt.Errorf("valeur invalide pour TRUSTED_PROXIES: %q", proxy)
+			}
+			trustedProxies = append(trustedProxies, proxy)
+		}
+	}
+
 	// Configuration de la base de données
 	dbHost := os.Getenv("DB_HOST")
 	if dbHost == "" {
@@ -60,9 +169,67 @@ func Load() (*Config, error) {
 		dbName = "matcha"
 	}
 
+	// Configuration Web Push (clés VAPID encodées en base64url)
+	vapidSubject := os.Getenv("VAPID_SUBJECT")
+	if vapidSubject == "" {
+		vapidSubject = "mailto:" + os.Getenv("FROM_EMAIL")
+	}
+
+	// Configuration du classement des suggestions
+	scoring, err := loadScoringConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Délai de réapparition des profils écartés, en jours
+	var passCooldown time.Duration
+	if value := os.Getenv("PASS_COOLDOWN_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			return nil, fmt.Errorf("valeur invalide pour PASS_COOLDOWN_DAYS: %q", value)
+		}
+		passCooldown = time.Duration(days) * 24 * time.Hour
+	}
+
+	// Période d'exécution des recherches sauvegardées, en minutes
+	savedSearchEvery := time.Hour
+	if value := os.Getenv("SAVED_SEARCH_INTERVAL_MINUTES"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			return nil, fmt.Errorf("valeur invalide pour SAVED_SEARCH_INTERVAL_MINUTES: %q", value)
+		}
+		savedSearchEvery = time.Duration(minutes) * time.Minute
+	}
+
+	// Durée de vie des classements paginés (curseurs), en minutes
+	sessionTTL := 30 * time.Minute
+	if value := os.Getenv("BROWSE_SESSION_TTL_MINUTES"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			return nil, fmt.Errorf("valeur invalide pour BROWSE_SESSION_TTL_MINUTES: %q", value)
+		}
+		sessionTTL = time.Duration(minutes) * time.Minute
+	}
+
+	// Recalcul du fame rating
+	fame, err := loadFameConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	// Détection des comptes à risque
+	risk, err := loadRiskConfig()
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
 		Server: ServerConfig{
-			Port: serverPort,
+			Port:             serverPort,
+			CSRFSecret:       os.Getenv("CSRF_SECRET"),
+			CSPReportOnly:    cspReportOnly,
+			RateLimitBackend: rateLimitBackend,
+			TrustedProxies:   trustedProxies,
 		},
 		Database: DatabaseConfig{
 			Host:     dbHost,
@@ -71,7 +238,210 @@ func Load() (*Config, error) {
 			Password: dbPassword,
 			Name:     dbName,
 		},
+		Push: PushConfig{
+			VAPIDPublicKey:  os.Getenv("VAPID_PUBLIC_KEY"),
+			VAPIDPrivateKey: os.Getenv("VAPID_PRIVATE_KEY"),
+			VAPIDSubject:    vapidSubject,
+		},
+		Scoring: scoring,
+		Browsing: BrowsingConfig{
+			PassCooldown:     passCooldown,
+			SavedSearchEvery: savedSearchEvery,
+			SessionTTL:       sessionTTL,
+		},
+		Fame: fame,
+		Risk: risk,
 	}
 
 	return config, nil
 }
+
+// loadScoringConfig charge les poids et zones de classement, avec les valeurs historiques par défaut
+func loadScoringConfig() (ScoringConfig, error) {
+	cfg := ScoringConfig{
+		DistanceWeight: 0.45,
+		TagsWeight:     0.27,
+		FameWeight:     0.18,
+		BehaviorWeight: 0.1,
+		MaxDistanceKm:  100,
+		MaxCommonTags:  5,
+		MaxFameRating:  100,
+		ZonesKm:        []float64{180, 250, 350, 500},
+		TagStatsEvery:  time.Hour,
+		NeighborsK:     20,
+		NeighborsEvery: time.Hour,
+	}
+
+	floats := map[string]*float64{
+		"SCORE_WEIGHT_DISTANCE": &cfg.DistanceWeight,
+		"SCORE_WEIGHT_TAGS":     &cfg.TagsWeight,
+		"SCORE_WEIGHT_FAME":     &cfg.FameWeight,
+		"SCORE_WEIGHT_BEHAVIOR": &cfg.BehaviorWeight,
+		"SCORE_MAX_DISTANCE_KM": &cfg.MaxDistanceKm,
+	}
+	for key, target := range floats {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 {
+			return cfg, fmt.Errorf("valeur invalide pour %s: %q", key, value)
+		}
+		*target = parsed
+	}
+
+	ints := map[string]*int{
+		"SCORE_MAX_COMMON_TAGS": &cfg.MaxCommonTags,
+		"SCORE_MAX_FAME":        &cfg.MaxFameRating,
+		"RECOMMENDER_NEIGHBORS": &cfg.NeighborsK,
+	}
+	for key, target := range ints {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return cfg, fmt.Errorf("valeur invalide pour %s: %q", key, value)
+		}
+		*target = parsed
+	}
+
+	if value := os.Getenv("SUGGESTION_ZONES_KM"); value != "" {
+		var zones []float64
+		for _, part := range strings.Split(value, ",") {
+			zone, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil || zone <= 0 || (len(zones) > 0 && zone <= zones[len(zones)-1]) {
+				return cfg, fmt.Errorf("SUGGESTION_ZONES_KM doit lister des distances croissantes: %q", value)
+			}
+			zones = append(zones, zone)
+		}
+		cfg.ZonesKm = zones
+	}
+
+	periods := map[string]*time.Duration{
+		"TAG_STATS_REFRESH_MINUTES":   &cfg.TagStatsEvery,
+		"RECOMMENDER_REFRESH_MINUTES": &cfg.NeighborsEvery,
+	}
+	for key, target := range periods {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			return cfg, fmt.Errorf("valeur invalide pour %s: %q", key, value)
+		}
+		*target = time.Duration(minutes) * time.Minute
+	}
+
+	if cfg.MaxDistanceKm == 0 {
+		return cfg, fmt.Errorf("SCORE_MAX_DISTANCE_KM doit être strictement positif")
+	}
+
+	return cfg, nil
+}
+
+// loadFameConfig charge les paramètres du fame rating, avec leurs valeurs par défaut
+func loadFameConfig() (FameConfig, error) {
+	cfg := FameConfig{
+		HalfLife:     30 * 24 * time.Hour,
+		ActorCap:     5,
+		RefreshEvery: 15 * time.Minute,
+	}
+
+	if value := os.Getenv("FAME_HALF_LIFE_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days <= 0 {
+			return cfg, fmt.Errorf("valeur invalide pour FAME_HALF_LIFE_DAYS: %q", value)
+		}
+		cfg.HalfLife = time.Duration(days) * 24 * time.Hour
+	}
+
+	if value := os.Getenv("FAME_ACTOR_CAP"); value != "" {
+		actorCap, err := strconv.ParseFloat(value, 64)
+		if err != nil || actorCap <= 0 {
+			return cfg, fmt.Errorf("valeur invalide pour FAME_ACTOR_CAP: %q", value)
+		}
+		cfg.ActorCap = actorCap
+	}
+
+	if value := os.Getenv("FAME_REFRESH_MINUTES"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			return cfg, fmt.Errorf("valeur invalide pour FAME_REFRESH_MINUTES: %q", value)
+		}
+		cfg.RefreshEvery = time.Duration(minutes) * time.Minute
+	}
+
+	return cfg, nil
+}
+
+// loadRiskConfig charge les seuils de détection des comptes à risque, avec leurs valeurs par défaut
+func loadRiskConfig() (RiskConfig, error) {
+	cfg := RiskConfig{
+		Window:              24 * time.Hour,
+		RefreshEvery:        15 * time.Minute,
+		HideScore:           60,
+		Reporters:           3,
+		LikesPerHour:        100,
+		DuplicateMessages:   10,
+		NewAccountAge:       48 * time.Hour,
+		NewAccountActions:   150,
+		SharedPhotoAccounts: 1,
+	}
+
+	if value := os.Getenv("RISK_HIDE_SCORE"); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil || score <= 0 || score > 100 {
+			return cfg, fmt.Errorf("valeur invalide pour RISK_HIDE_SCORE: %q", value)
+		}
+		cfg.HideScore = score
+	}
+
+	ints := map[string]*int{
+		"RISK_REPORTERS":             &cfg.Reporters,
+		"RISK_LIKES_PER_HOUR":        &cfg.LikesPerHour,
+		"RISK_DUPLICATE_MESSAGES":    &cfg.DuplicateMessages,
+		"RISK_NEW_ACCOUNT_ACTIONS":   &cfg.NewAccountActions,
+		"RISK_SHARED_PHOTO_ACCOUNTS": &cfg.SharedPhotoAccounts,
+	}
+	for key, target := range ints {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return cfg, fmt.Errorf("valeur invalide pour %s: %q", key, value)
+		}
+		*target = parsed
+	}
+
+	hours := map[string]*time.Duration{
+		"RISK_WINDOW_HOURS":      &cfg.Window,
+		"RISK_NEW_ACCOUNT_HOURS": &cfg.NewAccountAge,
+	}
+	for key, target := range hours {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return cfg, fmt.Errorf("valeur invalide pour %s: %q", key, value)
+		}
+		*target = time.Duration(parsed) * time.Hour
+	}
+
+	if value := os.Getenv("RISK_REFRESH_MINUTES"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes <= 0 {
+			return cfg, fmt.Errorf("valeur invalide pour RISK_REFRESH_MINUTES: %q", value)
+		}
+		cfg.RefreshEvery = time.Duration(minutes) * time.Minute
+	}
+
+	return cfg, nil
+}
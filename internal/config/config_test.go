@@ -0,0 +1,38 @@
+package config
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLoadScoringConfigDefaultWeights(t *testing.T) {
+	for _, key := range []string{"SCORE_WEIGHT_DISTANCE", "SCORE_WEIGHT_TAGS", "SCORE_WEIGHT_FAME", "SCORE_WEIGHT_BEHAVIOR"} {
+		t.Setenv(key, "")
+	}
+
+	cfg, err := loadScoringConfig()
+	if err != nil {
+		t.Fatalf("loadScoringConfig: %v", err)
+	}
+
+	sum := cfg.DistanceWeight + cfg.TagsWeight + cfg.FameWeight + cfg.BehaviorWeight
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("somme des poids = %v, attendu 1", sum)
+	}
+
+	// Sans la composante comportementale, les poids redonnent 0,5 / 0,3 / 0,2
+	base := cfg.DistanceWeight + cfg.TagsWeight + cfg.FameWeight
+	tests := []struct {
+		name      string
+		got, want float64
+	}{
+		{"distance", cfg.DistanceWeight / base, 0.5},
+		{"tags", cfg.TagsWeight / base, 0.3},
+		{"fame", cfg.FameWeight / base, 0.2},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > 1e-9 {
+			t.Errorf("poids %s renormalisé = %v, attendu %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -21,7 +21,25 @@ func RunMigrations(db *sql.DB) error {
 		"internal/database/migrations/create_notifications_table.sql",
 		"internal/database/migrations/create_messages_table.sql",
 		"internal/database/migrations/create_reports_table.sql",
+		"internal/database/migrations/create_push_subscriptions_table.sql",
+		"internal/database/migrations/create_outbox_table.sql",
+		"internal/database/migrations/add_candidate_search_indexes.sql",
+		"internal/database/migrations/add_earthdistance_index.sql",
+		"internal/database/migrations/create_passes_table.sql",
+		"internal/database/migrations/create_discovery_preferences_table.sql",
+		"internal/database/migrations/create_tag_similarity_tables.sql",
+		"internal/database/migrations/create_user_neighbors_table.sql",
+		"internal/database/migrations/create_fame_history_table.sql",
+		"internal/database/migrations/create_saved_searches_table.sql",
 		"internal/database/migrations/add_500_seed.sql",
+		"internal/database/migrations/add_interested_in_to_profiles.sql",
+		"internal/database/migrations/create_moderation_tables.sql",
+		"internal/database/migrations/add_account_status_to_users.sql",
+		"internal/database/migrations/create_user_risk_table.sql",
+		"internal/database/migrations/add_report_categories.sql",
+		"internal/database/migrations/create_appeals_table.sql",
+		"internal/database/migrations/create_audit_events_table.sql",
+		"internal/database/migrations/create_rate_limit_buckets_table.sql",
 	}
 
 	for _, file := range migrationFiles {
@@ -0,0 +1,13 @@
+-- États de compte fixés par la modération
+ALTER TABLE users ADD COLUMN IF NOT EXISTS account_status VARCHAR(20) NOT NULL DEFAULT 'active';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_reason TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_account_status_check;
+ALTER TABLE users ADD CONSTRAINT users_account_status_check
+    CHECK (account_status IN ('active', 'warned', 'suspended', 'banned', 'shadow_banned'));
+
+CREATE INDEX IF NOT EXISTS idx_users_account_status ON users(account_status) WHERE account_status <> 'active';
+
+-- Messages d'un compte shadow-banni : visibles de leur seul expéditeur
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS is_hidden BOOLEAN NOT NULL DEFAULT FALSE;
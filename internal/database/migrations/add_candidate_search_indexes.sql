@@ -0,0 +1,4 @@
+-- Index utilisés par la présélection des candidats (suggestions et recherche) ;
+-- la boîte englobante s'appuie sur idx_user_profiles_location, déjà existant
+CREATE INDEX IF NOT EXISTS idx_user_profiles_birth_date ON user_profiles(birth_date);
+CREATE INDEX IF NOT EXISTS idx_user_photos_profile ON user_photos(user_id) WHERE is_profile = TRUE;
@@ -0,0 +1,20 @@
+-- Index spatial pour les recherches par rayon (extensions cube + earthdistance).
+-- Si les extensions ne peuvent pas être installées, la recherche se rabat sur
+-- une boîte englobante utilisant idx_user_profiles_location.
+DO $$
+BEGIN
+    CREATE EXTENSION IF NOT EXISTS cube;
+    CREATE EXTENSION IF NOT EXISTS earthdistance;
+EXCEPTION WHEN OTHERS THEN
+    RAISE NOTICE 'earthdistance indisponible: %', SQLERRM;
+END
+$$;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'earthdistance') THEN
+        CREATE INDEX IF NOT EXISTS idx_user_profiles_earth
+            ON user_profiles USING gist (ll_to_earth(latitude, longitude));
+    END IF;
+END
+$$;
@@ -0,0 +1,44 @@
+-- Orientation exprimée comme l'ensemble des genres qui intéressent l'utilisateur.
+-- sexual_preferences est conservé comme libellé indicatif.
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS interested_in TEXT[] NOT NULL DEFAULT '{}';
+
+-- Traduction des préférences existantes (y compris les profils importés par le seed)
+UPDATE user_profiles
+SET interested_in = CASE
+    WHEN sexual_preferences = 'bisexual' THEN ARRAY['male', 'female']
+    WHEN sexual_preferences = 'homosexual' AND gender IN ('male', 'female') THEN ARRAY[gender::TEXT]
+    WHEN sexual_preferences = 'heterosexual' AND gender = 'male' THEN ARRAY['female']
+    WHEN sexual_preferences = 'heterosexual' AND gender = 'female' THEN ARRAY['male']
+    ELSE '{}'::TEXT[]
+END
+WHERE cardinality(interested_in) = 0
+AND COALESCE(sexual_preferences, '') <> '';
+
+-- Les genres des préférences de découverte doublonnaient interested_in : ils y
+-- sont repliés (intersection, ou reprise si l'orientation n'était pas traduite)
+-- avant la suppression de la colonne
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'user_discovery_preferences' AND column_name = 'genders'
+    ) THEN
+        UPDATE user_profiles p
+        SET interested_in = CASE
+                WHEN cardinality(p.interested_in) = 0 THEN dp.genders
+                ELSE ARRAY(SELECT unnest(p.interested_in) INTERSECT SELECT unnest(dp.genders))
+            END,
+            updated_at = CURRENT_TIMESTAMP
+        FROM user_discovery_preferences dp
+        WHERE dp.user_id = p.user_id
+        AND cardinality(dp.genders) > 0
+        AND (
+            cardinality(p.interested_in) = 0
+            OR (p.interested_in && dp.genders AND NOT p.interested_in <@ dp.genders)
+        );
+
+        ALTER TABLE user_discovery_preferences DROP COLUMN genders;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_user_profiles_interested_in ON user_profiles USING GIN (interested_in);
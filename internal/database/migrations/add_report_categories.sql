@@ -0,0 +1,15 @@
+-- Catégories de signalement ; les signalements antérieurs sont classés « other »
+ALTER TABLE user_reports ADD COLUMN IF NOT EXISTS category VARCHAR(30) NOT NULL DEFAULT 'other';
+ALTER TABLE user_reports DROP CONSTRAINT IF EXISTS user_reports_category_check;
+ALTER TABLE user_reports ADD CONSTRAINT user_reports_category_check
+    CHECK (category IN ('spam', 'harassment', 'fake_profile', 'underage', 'inappropriate_photos', 'other'));
+
+-- Pièces jointes : messages échangés avec la personne signalée et photos de son profil
+ALTER TABLE user_reports ADD COLUMN IF NOT EXISTS evidence_message_ids INTEGER[] NOT NULL DEFAULT '{}';
+ALTER TABLE user_reports ADD COLUMN IF NOT EXISTS evidence_photo_ids INTEGER[] NOT NULL DEFAULT '{}';
+
+-- Plusieurs signalements d'une même personne au fil du temps, mais un seul en
+-- cours par catégorie
+ALTER TABLE user_reports DROP CONSTRAINT IF EXISTS user_reports_reporter_id_reported_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_reports_pending_category
+    ON user_reports(reporter_id, reported_id, category) WHERE status <> 'resolved';
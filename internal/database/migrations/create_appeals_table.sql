@@ -0,0 +1,21 @@
+-- Contestations des suspensions et bannissements : ouverte, en cours d'examen,
+-- puis acceptée (compte rétabli) ou rejetée
+CREATE TABLE IF NOT EXISTS appeals (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    account_status VARCHAR(20) NOT NULL, -- État du compte contesté
+    status_reason TEXT,
+    message TEXT NOT NULL CHECK (length(trim(message)) > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'under_review', 'accepted', 'rejected')),
+    reviewer_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    decision_comment TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    decided_at TIMESTAMP
+);
+
+-- Une seule contestation en cours par utilisateur
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appeals_pending_user
+    ON appeals(user_id) WHERE status IN ('open', 'under_review');
+CREATE INDEX IF NOT EXISTS idx_appeals_status ON appeals(status, created_at);
@@ -0,0 +1,39 @@
+-- Journal d'audit des événements sensibles (connexions, comptes, modération).
+-- Chaque événement porte l'empreinte du précédent : toute modification ou
+-- suppression d'une ligne rompt la chaîne. Les identifiants ne référencent pas
+-- users afin que le journal survive à la suppression d'un compte.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INTEGER,          -- NULL : visiteur anonyme ou action automatique
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32),
+    target_id INTEGER,
+    ip VARCHAR(64),
+    user_agent TEXT,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor ON audit_events(actor_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events(action, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events(target_type, target_id, id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events(created_at);
+
+-- Le journal est en ajout seul
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events est en ajout seul (% refusé)', TG_OP;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_no_update ON audit_events;
+CREATE TRIGGER audit_events_no_update
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+DROP TRIGGER IF EXISTS audit_events_no_truncate ON audit_events;
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
@@ -0,0 +1,10 @@
+-- Préférences de découverte : tranche d'âge et distance maximale (0 = sans limite).
+-- Les genres recherchés sont ceux du profil (user_profiles.interested_in).
+CREATE TABLE IF NOT EXISTS user_discovery_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    min_age INTEGER NOT NULL DEFAULT 0,
+    max_age INTEGER NOT NULL DEFAULT 0,
+    max_distance_km DOUBLE PRECISION NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (min_age = 0 OR max_age = 0 OR min_age <= max_age)
+);
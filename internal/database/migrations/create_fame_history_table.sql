@@ -0,0 +1,10 @@
+-- Historique quotidien du fame rating, pour les courbes d'évolution
+CREATE TABLE IF NOT EXISTS fame_history (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    recorded_on DATE NOT NULL DEFAULT CURRENT_DATE,
+    fame_rating INTEGER NOT NULL,
+    raw_score DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (user_id, recorded_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fame_history_recorded_on ON fame_history(recorded_on);
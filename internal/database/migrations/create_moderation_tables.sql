@@ -0,0 +1,33 @@
+-- Rôles : user, moderator, admin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user';
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'moderator', 'admin'));
+
+-- Cycle de vie d'un signalement : ouvert, pris en charge par un modérateur, résolu
+ALTER TABLE user_reports ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'open';
+ALTER TABLE user_reports ADD COLUMN IF NOT EXISTS claimed_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE user_reports ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP;
+ALTER TABLE user_reports ADD COLUMN IF NOT EXISTS resolved_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE user_reports ADD COLUMN IF NOT EXISTS resolution VARCHAR(30);
+ALTER TABLE user_reports DROP CONSTRAINT IF EXISTS user_reports_status_check;
+ALTER TABLE user_reports ADD CONSTRAINT user_reports_status_check CHECK (status IN ('open', 'claimed', 'resolved'));
+
+-- Signalements traités avant l'introduction des statuts
+UPDATE user_reports SET status = 'resolved', resolution = 'dismissed'
+WHERE is_processed = TRUE AND status = 'open';
+
+CREATE INDEX IF NOT EXISTS idx_user_reports_status ON user_reports(status, created_at);
+
+-- Journal des actions des modérateurs
+CREATE TABLE IF NOT EXISTS moderation_log (
+    id SERIAL PRIMARY KEY,
+    moderator_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(30) NOT NULL,
+    target_id INTEGER NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_log_moderator ON moderation_log(moderator_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_moderation_log_target ON moderation_log(target_type, target_id);
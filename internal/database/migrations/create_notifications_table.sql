@@ -3,7 +3,7 @@ CREATE TABLE IF NOT EXISTS notifications (
     id SERIAL PRIMARY KEY,
     user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     from_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    type VARCHAR(20) NOT NULL CHECK (type IN ('like', 'visit', 'message', 'match', 'unlike', 'profile_view')),
+    type VARCHAR(20) NOT NULL,
     message TEXT NOT NULL,
     is_read BOOLEAN DEFAULT FALSE,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
@@ -17,4 +17,11 @@ CREATE INDEX IF NOT EXISTS idx_notifications_user_unread ON notifications(user_i
 
 -- Supprimer la contrainte si elle existe et la recréer
 ALTER TABLE notifications DROP CONSTRAINT IF EXISTS chk_no_self_notification;
-ALTER TABLE notifications ADD CONSTRAINT chk_no_self_notification CHECK (user_id != from_id);
\ No newline at end of file
+ALTER TABLE notifications ADD CONSTRAINT chk_no_self_notification CHECK (user_id != from_id);
+
+-- Types de notification : cette contrainte n'est définie qu'ici, avec la liste
+-- complète, pour que chaque redémarrage la recrée à l'identique
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_type_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_type_check
+    CHECK (type IN ('like', 'visit', 'message', 'match', 'unlike', 'profile_view', 'search_match',
+                    'report_resolved'));
@@ -0,0 +1,26 @@
+-- Migration pour créer la table outbox : les effets de bord (notifications,
+-- pushs WebSocket, recalcul du fame rating) sont écrits dans la même transaction
+-- que la modification métier, puis livrés au moins une fois par le dispatcher
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type VARCHAR(50) NOT NULL,
+    dedup_key VARCHAR(255) NOT NULL UNIQUE,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    available_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    processed_at TIMESTAMP,
+    failed_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(available_at)
+    WHERE processed_at IS NULL AND failed_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_processed_at ON outbox_events(processed_at)
+    WHERE processed_at IS NOT NULL;
+
+-- Clé de déduplication des notifications : une livraison répétée d'un même
+-- événement outbox ne crée pas de doublon
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS dedup_key VARCHAR(255);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_dedup_key ON notifications(dedup_key);
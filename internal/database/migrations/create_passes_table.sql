@@ -0,0 +1,11 @@
+-- Table des profils écartés ("pass") depuis les suggestions
+CREATE TABLE IF NOT EXISTS user_passes (
+    id SERIAL PRIMARY KEY,
+    passer_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    passed_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (passer_id, passed_id),
+    CHECK (passer_id != passed_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_passes_passer_created ON user_passes(passer_id, created_at DESC);
@@ -0,0 +1,12 @@
+-- Seaux de jetons de la limitation de débit, partagés entre les instances du serveur
+-- (RATE_LIMIT_BACKEND=postgres). Table non journalisée : ces compteurs éphémères
+-- peuvent être perdus après un arrêt brutal sans autre effet qu'une remise à zéro.
+-- expires_at est l'instant où le seau sera de nouveau plein, et donc supprimable.
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limit_buckets (
+    bucket_key TEXT PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_limit_buckets_expires_at ON rate_limit_buckets(expires_at);
@@ -0,0 +1,20 @@
+-- Recherches sauvegardées : critères de SearchProfiles nommés, avec alertes
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    email_alerts BOOLEAN NOT NULL DEFAULT FALSE,
+    last_run_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
+
+-- Profils déjà remontés par chaque recherche : seuls les nouveaux déclenchent une alerte
+CREATE TABLE IF NOT EXISTS saved_search_hits (
+    search_id INTEGER NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (search_id, user_id)
+);
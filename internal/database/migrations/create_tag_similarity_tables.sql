@@ -0,0 +1,38 @@
+-- Synonymes et alias de tags, sous forme normalisée (minuscules, sans accents ni #)
+CREATE TABLE IF NOT EXISTS tag_aliases (
+    alias VARCHAR(50) PRIMARY KEY,
+    canonical VARCHAR(50) NOT NULL,
+    CHECK (alias <> canonical)
+);
+
+INSERT INTO tag_aliases (alias, canonical) VALUES
+    ('foot', 'football'),
+    ('soccer', 'football'),
+    ('rando', 'randonnee'),
+    ('hiking', 'randonnee'),
+    ('cine', 'cinema'),
+    ('movies', 'cinema'),
+    ('films', 'cinema'),
+    ('music', 'musique'),
+    ('zik', 'musique'),
+    ('livres', 'lecture'),
+    ('reading', 'lecture'),
+    ('jeuxvideo', 'gaming'),
+    ('videogames', 'gaming'),
+    ('velo', 'cyclisme'),
+    ('muscu', 'musculation'),
+    ('gym', 'musculation'),
+    ('photographie', 'photo'),
+    ('voyages', 'voyage'),
+    ('travel', 'voyage'),
+    ('cuisiner', 'cuisine'),
+    ('cooking', 'cuisine')
+ON CONFLICT (alias) DO NOTHING;
+
+-- Fréquence des tags canoniques et IDF associé, recalculés périodiquement
+CREATE TABLE IF NOT EXISTS tag_stats (
+    canonical VARCHAR(50) PRIMARY KEY,
+    user_count INTEGER NOT NULL,
+    idf DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,11 @@
+-- Voisins de chaque profil pour le filtrage collaboratif : les personnes qui
+-- ont liké user_id ont aussi liké neighbor_id (top-K par profil, recalculé périodiquement)
+CREATE TABLE IF NOT EXISTS user_neighbors (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    neighbor_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    similarity DOUBLE PRECISION NOT NULL,
+    rank INTEGER NOT NULL,
+    computed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, neighbor_id),
+    CHECK (user_id != neighbor_id)
+);
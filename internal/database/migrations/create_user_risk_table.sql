@@ -0,0 +1,20 @@
+-- Empreinte des photos, pour repérer une même image publiée par plusieurs comptes
+ALTER TABLE user_photos ADD COLUMN IF NOT EXISTS content_hash VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_user_photos_content_hash ON user_photos(content_hash) WHERE content_hash IS NOT NULL;
+
+-- Score de risque de chaque compte présentant au moins un signal, avec son explication.
+-- hidden masque le profil des suggestions jusqu'à l'examen d'un modérateur ; après
+-- examen, le profil n'est de nouveau masqué que si son score dépasse celui examiné.
+CREATE TABLE IF NOT EXISTS user_risk (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    score REAL NOT NULL DEFAULT 0,
+    factors JSONB NOT NULL DEFAULT '[]',
+    signals JSONB NOT NULL DEFAULT '{}',
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    computed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at TIMESTAMP,
+    reviewed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_score REAL
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_risk_hidden ON user_risk(score DESC) WHERE hidden;
@@ -0,0 +1,33 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// DBTX regroupe les méthodes communes à *sql.DB et *sql.Tx, pour les requêtes
+// utilisables dans ou hors d'une transaction
+type DBTX interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// WithTx exécute fn dans une transaction, validée si fn réussit et annulée sinon
+func WithTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'ouverture de la transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("erreur lors de la validation de la transaction: %w", err)
+	}
+
+	return nil
+}
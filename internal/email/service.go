@@ -4,7 +4,9 @@ package email
 
 import (
 	"fmt"
+	"html"
 	"net/smtp"
+	"time"
 )
 
 // Service gère l'envoi d'emails
@@ -64,6 +66,113 @@ func (s *Service) SendPasswordResetEmail(to, username, resetLink string) error {
 	return s.sendEmail(to, subject, body)
 }
 
+// SendSavedSearchAlert prévient qu'une recherche sauvegardée a de nouveaux résultats
+func (s *Service) SendSavedSearchAlert(to, username, searchName string, count int, searchLink string) error {
+	subject := "Nouveaux profils pour votre recherche Matcha"
+	body := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Bonjour %s,</h1>
+            <p>%d nouveau(x) profil(s) correspond(ent) à votre recherche « %s ».</p>
+            <p><a href="%s">Voir les profils</a></p>
+            <p>Vous pouvez désactiver ces alertes depuis vos recherches sauvegardées.</p>
+        </body>
+        </html>
+    `, html.EscapeString(username), count, html.EscapeString(searchName), searchLink)
+
+	return s.sendEmail(to, subject, body)
+}
+
+// SendModerationNotice informe un utilisateur d'une décision de modération sur son
+// compte ; une suspension ou un bannissement renvoie vers la page de contestation
+func (s *Service) SendModerationNotice(to, username, status, reason string, until *time.Time, appealLink string) error {
+	var subject, decision string
+	switch status {
+	case "warned":
+		subject = "Avertissement concernant votre compte Matcha"
+		decision = "Votre compte a fait l'objet d'un avertissement."
+	case "suspended":
+		subject = "Suspension de votre compte Matcha"
+		decision = "Votre compte est suspendu."
+		if until != nil {
+			decision = fmt.Sprintf("Votre compte est suspendu jusqu'au %s (UTC).", until.Format("02/01/2006 à 15:04"))
+		}
+	case "banned":
+		subject = "Bannissement de votre compte Matcha"
+		decision = "Votre compte a été banni définitivement."
+	case "active":
+		subject = "Votre compte Matcha est rétabli"
+		decision = "Les restrictions sur votre compte ont été levées."
+	default:
+		return fmt.Errorf("état de compte sans notification: %s", status)
+	}
+
+	motive := ""
+	if reason != "" {
+		motive = fmt.Sprintf("<p>Motif : %s</p>", html.EscapeString(reason))
+	}
+
+	appeal := ""
+	switch status {
+	case "suspended", "banned":
+		appeal = fmt.Sprintf(`<p>Si vous pensez qu'il s'agit d'une erreur, vous pouvez contester cette décision en vous connectant : <a href="%s">contester la décision</a>.</p>`, appealLink)
+	case "warned":
+		appeal = "<p>Si vous pensez qu'il s'agit d'une erreur, vous pouvez nous le signaler en répondant à cet email.</p>"
+	}
+
+	body := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Bonjour %s,</h1>
+            <p>%s</p>
+            %s
+            %s
+        </body>
+        </html>
+    `, html.EscapeString(username), decision, motive, appeal)
+
+	return s.sendEmail(to, subject, body)
+}
+
+// SendAppealUpdate informe un utilisateur de l'avancement de sa contestation
+func (s *Service) SendAppealUpdate(to, username, status, comment, appealLink string) error {
+	var subject, update string
+	switch status {
+	case "open":
+		subject = "Votre contestation a bien été reçue"
+		update = "Votre contestation a été enregistrée. Un modérateur l'examinera prochainement."
+	case "under_review":
+		subject = "Votre contestation est en cours d'examen"
+		update = "Un modérateur examine actuellement votre contestation."
+	case "accepted":
+		subject = "Votre contestation a été acceptée"
+		update = "Votre contestation a été acceptée : votre compte est rétabli et vous pouvez de nouveau vous connecter."
+	case "rejected":
+		subject = "Votre contestation a été rejetée"
+		update = "Après examen, la décision concernant votre compte est maintenue."
+	default:
+		return fmt.Errorf("statut de contestation sans notification: %s", status)
+	}
+
+	note := ""
+	if comment != "" {
+		note = fmt.Sprintf("<p>Commentaire du modérateur : %s</p>", html.EscapeString(comment))
+	}
+
+	body := fmt.Sprintf(`
+        <html>
+        <body>
+            <h1>Bonjour %s,</h1>
+            <p>%s</p>
+            %s
+            <p><a href="%s">Suivre ma contestation</a></p>
+        </body>
+        </html>
+    `, html.EscapeString(username), update, note, appealLink)
+
+	return s.sendEmail(to, subject, body)
+}
+
 // sendEmail envoie un email - VERSION DÉVELOPPEMENT
 func (s *Service) sendEmail(to, subject, body string) error {
 	// EN DÉVELOPPEMENT: Afficher dans la console ET essayer d'envoyer si configuré
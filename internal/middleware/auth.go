@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/cduffaut/matcha/internal/models"
 	"github.com/cduffaut/matcha/internal/session"
 )
 
@@ -19,8 +20,19 @@ func NewAuthMiddleware(sessionManager *session.Manager) *AuthMiddleware {
 	}
 }
 
-// RequireAuth est un middleware qui vérifie si l'utilisateur est authentifié
+// RequireAuth est un middleware qui vérifie si l'utilisateur est authentifié ;
+// une session restreinte (compte suspendu ou banni) n'y donne pas accès
 func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
+	return m.authenticate(next, false)
+}
+
+// AllowRestricted est un middleware qui accepte aussi les sessions restreintes,
+// pour les seules routes de contestation
+func (m *AuthMiddleware) AllowRestricted(next http.Handler) http.Handler {
+	return m.authenticate(next, true)
+}
+
+func (m *AuthMiddleware) authenticate(next http.Handler, allowRestricted bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		// Vérifier la session
@@ -41,6 +53,18 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 			}
 		}
 
+		// Compte suspendu ou banni : seule la contestation reste accessible
+		if userSession.Restricted && !allowRestricted {
+			if isAPIRequest(r) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error": "Account restricted", "appeal_url": "/appeal"}`))
+				return
+			}
+			http.Redirect(w, r, "/appeal", http.StatusFound)
+			return
+		}
+
 		// CORRECTION : Stocker les informations de session dans le contexte
 		ctx := r.Context()
 		ctx = session.WithSession(ctx, userSession)
@@ -50,6 +74,28 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 	})
 }
 
+// RequireRole est un middleware qui vérifie que l'utilisateur authentifié a au
+// moins le rôle demandé (admin inclut moderator, qui inclut user)
+func (m *AuthMiddleware) RequireRole(role string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return m.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userSession, ok := session.FromContext(r.Context())
+			if !ok || !models.HasRole(userSession.Role, role) {
+				if isAPIRequest(r) {
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(http.StatusForbidden)
+					w.Write([]byte(`{"error": "Insufficient permissions"}`))
+					return
+				}
+				http.Error(w, "Accès refusé", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
 // isAPIRequest détermine si c'est une requête API
 func isAPIRequest(r *http.Request) bool {
 	// Vérifier le chemin
@@ -2,47 +2,136 @@ package middleware
 
 import (
 	"net/http"
+	"net/url"
 	"strings"
+
+	"github.com/cduffaut/matcha/internal/security"
 )
 
-// CSRFMiddleware ajoute une protection CSRF basique
-func CSRFMiddleware(next http.Handler) http.Handler {
+// CSRFMiddleware protège les requêtes modifiantes par un jeton lié à la session
+// (double soumission signée) et par une vérification exacte de l'origine
+type CSRFMiddleware struct {
+	tokens        *security.CSRFTokens
+	sessionCookie string
+	exempt        map[string]bool
+}
+
+// NewCSRFMiddleware crée le middleware CSRF ; sessionCookie est le cookie de
+// session auquel les jetons sont liés
+func NewCSRFMiddleware(tokens *security.CSRFTokens, sessionCookie string) *CSRFMiddleware {
+	return &CSRFMiddleware{
+		tokens:        tokens,
+		sessionCookie: sessionCookie,
+		exempt:        make(map[string]bool),
+	}
+}
+
+// Exempt dispense une route de la vérification, pour les requêtes émises par le
+// navigateur lui-même (rapports CSP) qui ne peuvent pas porter de jeton
+func (m *CSRFMiddleware) Exempt(path string) {
+	m.exempt[path] = true
+}
+
+// Protect émet le jeton CSRF de la session (cookie et contexte) et le vérifie
+// sur chaque requête POST, PUT, DELETE ou PATCH
+func (m *CSRFMiddleware) Protect(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Appliquer CSRF seulement aux requêtes modifiantes
-		if isModifyingRequest(r) {
-			// Vérifier l'en-tête Referer pour une protection basique
-			referer := r.Header.Get("Referer")
-			host := r.Header.Get("Host")
-
-			// Pour les requêtes AJAX/API, vérifier l'origine
-			if isAPIRequest(r) {
-				origin := r.Header.Get("Origin")
-				if origin != "" && !strings.Contains(origin, host) {
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(http.StatusForbidden)
-					w.Write([]byte(`{"error": "CSRF protection: Invalid origin"}`))
-					return
-				}
-			}
+		if m.exempt[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-			// Pour les formulaires, vérifier le referer
-			if referer != "" && !strings.Contains(referer, host) {
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusForbidden)
-				w.Write([]byte(`{"error": "CSRF protection: Invalid referer"}`))
+		sessionID := ""
+		if cookie, err := r.Cookie(m.sessionCookie); err == nil {
+			sessionID = cookie.Value
+		}
+
+		// Jeton absent ou émis pour une autre session (connexion, déconnexion) : en émettre un nouveau
+		token := ""
+		if cookie, err := r.Cookie(security.CSRFCookieName); err == nil && m.tokens.Valid(cookie.Value, sessionID) {
+			token = cookie.Value
+		}
+		issued := token
+		if issued == "" {
+			var err error
+			if issued, err = m.tokens.Issue(sessionID); err != nil {
+				http.Error(w, "Erreur interne", http.StatusInternalServerError)
 				return
 			}
+			http.SetCookie(w, &http.Cookie{
+				Name:     security.CSRFCookieName,
+				Value:    issued,
+				Path:     "/",
+				SameSite: http.SameSiteStrictMode,
+				Secure:   r.TLS != nil,
+			})
 		}
 
-		// Ajouter des en-têtes de sécurité
-		w.Header().Set("X-Content-Type-Options", "nosniff")
-		w.Header().Set("X-Frame-Options", "DENY")
-		w.Header().Set("X-XSS-Protection", "1; mode=block")
+		if isModifyingRequest(r) {
+			if !sameOrigin(r) {
+				rejectCSRF(w, "origin", "CSRF protection: Invalid origin")
+				return
+			}
+			// Le jeton soumis doit être celui du cookie, et ce cookie valide pour la session
+			if token == "" || !security.SameCSRFToken(submittedToken(r), token) {
+				rejectCSRF(w, "token", "CSRF protection: Invalid token")
+				return
+			}
+		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(security.WithCSRFToken(r.Context(), issued)))
 	})
 }
 
+// submittedToken lit le jeton de l'en-tête, ou du champ d'un formulaire classique
+func submittedToken(r *http.Request) string {
+	if token := r.Header.Get(security.CSRFHeader); token != "" {
+		return token
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		return r.PostFormValue(security.CSRFFormField)
+	}
+	return ""
+}
+
+// sameOrigin compare exactement (schéma, hôte et port) l'Origin, ou à défaut le
+// Referer, à l'origine du serveur ; sans aucun des deux, seul le jeton fait foi
+func sameOrigin(r *http.Request) bool {
+	source := r.Header.Get("Origin")
+	if source == "" {
+		source = r.Header.Get("Referer")
+	}
+	if source == "" {
+		return true
+	}
+
+	parsed, err := url.Parse(source)
+	if err != nil {
+		return false
+	}
+	return parsed.Scheme == requestScheme(r) && parsed.Host == r.Host
+}
+
+// requestScheme retourne le schéma vu par le client, derrière un proxy le cas échéant
+func requestScheme(r *http.Request) string {
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		return strings.ToLower(strings.TrimSpace(strings.Split(proto, ",")[0]))
+	}
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// rejectCSRF refuse la requête ; X-CSRF-Failure permet au client de réessayer
+// une fois avec le jeton qui vient d'être émis
+func rejectCSRF(w http.ResponseWriter, reason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-CSRF-Failure", reason)
+	w.WriteHeader(http.StatusForbidden)
+	w.Write([]byte(`{"error": "` + message + `"}`))
+}
+
 // isModifyingRequest vérifie si c'est une requête qui modifie des données
 func isModifyingRequest(r *http.Request) bool {
 	return r.Method == "POST" || r.Method == "PUT" || r.Method == "DELETE" || r.Method == "PATCH"
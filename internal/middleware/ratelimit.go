@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/ratelimit"
+	"github.com/cduffaut/matcha/internal/security"
+	"github.com/cduffaut/matcha/internal/session"
+)
+
+// RateLimitMiddleware limite le débit des routes coûteuses, selon une politique par route
+type RateLimitMiddleware struct {
+	limiter *ratelimit.Limiter
+}
+
+// NewRateLimitMiddleware crée un nouveau middleware de limitation de débit
+func NewRateLimitMiddleware(limiter *ratelimit.Limiter) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		limiter: limiter,
+	}
+}
+
+// Limit applique la politique aux routes qu'il enveloppe et annonce l'état du
+// seau par les en-têtes RateLimit-* ; une politique par utilisateur doit
+// envelopper une route authentifiée. En cas d'erreur du stockage, la requête
+// est laissée passer plutôt que de rendre le service indisponible.
+func (m *RateLimitMiddleware) Limit(policy ratelimit.Policy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := m.limiter.Take(policy, rateLimitKey(r, policy.By))
+			if err != nil {
+				fmt.Printf("Erreur limitation de débit %s: %v\n", policy.Name, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			header := w.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			header.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+			header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, seconds(policy.Period)))
+
+			if !result.Allowed {
+				retryAfter := seconds(result.RetryAfter)
+				header.Set("Retry-After", strconv.Itoa(retryAfter))
+				header.Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprintf(w, `{"error": "Trop de requêtes, réessayez dans %d secondes"}`, retryAfter)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// rateLimitKey identifie le client : l'utilisateur connecté ou, à défaut, son IP.
+// security.ClientIP ne lit X-Forwarded-For que derrière un proxy de confiance :
+// un client ne peut pas changer de seau en forgeant l'en-tête.
+func rateLimitKey(r *http.Request, by ratelimit.KeyBy) string {
+	if by == ratelimit.ByUser {
+		if userSession, ok := session.FromContext(r.Context()); ok {
+			return "user:" + strconv.Itoa(userSession.UserID)
+		}
+	}
+	return "ip:" + security.ClientIP(r)
+}
+
+// seconds arrondit une durée à la seconde supérieure
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
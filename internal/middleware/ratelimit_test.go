@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/ratelimit"
+	"github.com/cduffaut/matcha/internal/security"
+	"github.com/cduffaut/matcha/internal/session"
+)
+
+func TestRateLimitKey(t *testing.T) {
+	if err := security.SetTrustedProxies([]string{"10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { security.SetTrustedProxies(nil) })
+
+	tests := []struct {
+		name       string
+		by         ratelimit.KeyBy
+		remoteAddr string
+		forwarded  string
+		userID     int
+		want       string
+	}{
+		{"IP de connexion", ratelimit.ByIP, "203.0.113.7:5000", "", 0, "ip:203.0.113.7"},
+		{"X-Forwarded-For forgé ignoré", ratelimit.ByIP, "203.0.113.7:5000", "198.51.100.1", 0, "ip:203.0.113.7"},
+		{"X-Forwarded-For du proxy de confiance", ratelimit.ByIP, "10.0.0.1:5000", "198.51.100.1", 0, "ip:198.51.100.1"},
+		{"entrée forgée derrière le proxy ignorée", ratelimit.ByIP, "10.0.0.1:5000", "1.2.3.4, 198.51.100.1", 0, "ip:198.51.100.1"},
+		{"utilisateur connecté", ratelimit.ByUser, "203.0.113.7:5000", "", 42, "user:42"},
+		{"par IP même connecté", ratelimit.ByIP, "203.0.113.7:5000", "", 42, "ip:203.0.113.7"},
+		{"par utilisateur sans session", ratelimit.ByUser, "203.0.113.7:5000", "198.51.100.1", 0, "ip:203.0.113.7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/register", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.userID > 0 {
+				r = r.WithContext(session.WithSession(r.Context(), &session.Session{UserID: tt.userID}))
+			}
+
+			if got := rateLimitKey(r, tt.by); got != tt.want {
+				t.Errorf("rateLimitKey = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	policy := ratelimit.Policy{Name: "register", Limit: 2, Period: time.Hour, By: ratelimit.ByIP}
+	handler := NewRateLimitMiddleware(ratelimit.NewLimiter(ratelimit.NewMemoryStore())).Limit(policy)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) }),
+	)
+
+	statuses := make([]int, 0, 3)
+	for _, forwarded := range []string{"198.51.100.1", "198.51.100.2", "198.51.100.3"} {
+		r := httptest.NewRequest(http.MethodPost, "/register", nil)
+		r.RemoteAddr = "203.0.113.7:5000"
+		r.Header.Set("X-Forwarded-For", forwarded)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		statuses = append(statuses, w.Code)
+	}
+
+	if statuses[0] != http.StatusNoContent || statuses[1] != http.StatusNoContent || statuses[2] != http.StatusTooManyRequests {
+		t.Errorf("statuts = %v, attendu la 3e requête refusée malgré des X-Forwarded-For différents", statuses)
+	}
+}
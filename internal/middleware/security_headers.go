@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/cduffaut/matcha/internal/security"
+)
+
+// SecurityHeadersMiddleware ajoute les en-têtes de sécurité à toutes les réponses,
+// dont une Content-Security-Policy stricte fondée sur un nonce par requête
+type SecurityHeadersMiddleware struct {
+	reportOnly bool
+}
+
+// NewSecurityHeadersMiddleware crée le middleware d'en-têtes de sécurité ; en mode
+// reportOnly, les violations de la politique sont signalées sans être bloquées
+func NewSecurityHeadersMiddleware(reportOnly bool) *SecurityHeadersMiddleware {
+	return &SecurityHeadersMiddleware{
+		reportOnly: reportOnly,
+	}
+}
+
+// cspReportGroup est le groupe de la Reporting API qui désigne /csp-report
+const cspReportGroup = "csp-endpoint"
+
+// Apply génère le nonce de la requête, l'ajoute au contexte et émet les en-têtes
+func (m *SecurityHeadersMiddleware) Apply(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce, err := security.NewCSPNonce()
+		if err != nil {
+			http.Error(w, "Erreur interne", http.StatusInternalServerError)
+			return
+		}
+
+		header := w.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+		// Filtre XSS des anciens navigateurs désactivé : la CSP le remplace
+		header.Set("X-XSS-Protection", "0")
+
+		header.Set("Reporting-Endpoints", fmt.Sprintf(`%s="%s"`, cspReportGroup, security.CSPReportPath))
+		if m.reportOnly {
+			header.Set("Content-Security-Policy-Report-Only", contentSecurityPolicy(nonce))
+		} else {
+			header.Set("Content-Security-Policy", contentSecurityPolicy(nonce))
+		}
+
+		next.ServeHTTP(w, r.WithContext(security.WithCSPNonce(r.Context(), nonce)))
+	})
+}
+
+// contentSecurityPolicy construit la politique : fichiers de scripts et de
+// styles du site ('self'), blocs inline portant le nonce, aucun gestionnaire
+// inline ; seuls les attributs style restent tolérés. connect-src autorise les services de géolocalisation
+// par IP utilisés par la page de profil.
+func contentSecurityPolicy(nonce string) string {
+	directives := []string{
+		"default-src 'self'",
+		fmt.Sprintf("script-src 'self' 'nonce-%s'", nonce),
+		fmt.Sprintf("style-src 'self' 'nonce-%s'", nonce),
+		"style-src-attr 'unsafe-inline'",
+		"img-src 'self' data: blob:",
+		"connect-src 'self' https://ipapi.co https://freegeoip.app https://api.ipgeolocation.io",
+		"worker-src 'self'",
+		"object-src 'none'",
+		"base-uri 'none'",
+		"form-action 'self'",
+		"frame-ancestors 'none'",
+		"report-uri " + security.CSPReportPath,
+		"report-to " + cspReportGroup,
+	}
+	return strings.Join(directives, "; ")
+}
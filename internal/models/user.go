@@ -11,9 +11,85 @@ type User struct {
 	LastName          string     `json:"last_name"`
 	Password          string     `json:"-"` // Ne jamais exposer le mot de passe
 	IsVerified        bool       `json:"is_verified"`
+	Role              string     `json:"role"`
+	AccountStatus     string     `json:"account_status"`
+	SuspendedUntil    *time.Time `json:"suspended_until,omitempty"`
+	StatusReason      string     `json:"-"`
 	VerificationToken *string    `json:"-"`
 	ResetToken        *string    `json:"-"`
 	ResetTokenExpiry  *time.Time `json:"-"`
 	CreatedAt         time.Time  `json:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at"`
 }
+
+// Rôles des utilisateurs, du moins au plus privilégié
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var roleLevels = map[string]int{
+	RoleUser:      0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+// IsValidRole indique si le rôle existe
+func IsValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// HasRole indique si role donne au moins les droits de required ; un rôle inconnu n'en donne aucun
+func HasRole(role, required string) bool {
+	level, ok := roleLevels[role]
+	requiredLevel, known := roleLevels[required]
+	return ok && known && level >= requiredLevel
+}
+
+// États d'un compte, fixés par la modération
+const (
+	AccountActive       = "active"
+	AccountWarned       = "warned"        // Averti : aucune restriction
+	AccountSuspended    = "suspended"     // Connexion impossible jusqu'à SuspendedUntil
+	AccountBanned       = "banned"        // Connexion impossible
+	AccountShadowBanned = "shadow_banned" // Invisible des autres, sans le savoir
+)
+
+// EffectiveStatus retourne l'état du compte à l'instant donné : une suspension
+// échue équivaut à un compte actif
+func (u *User) EffectiveStatus(now time.Time) string {
+	if u.AccountStatus == "" {
+		return AccountActive
+	}
+	if u.AccountStatus == AccountSuspended && u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil) {
+		return AccountActive
+	}
+	return u.AccountStatus
+}
+
+// IsLocked indique si le compte est suspendu ou banni à l'instant donné
+func (u *User) IsLocked(now time.Time) bool {
+	status := u.EffectiveStatus(now)
+	return status == AccountSuspended || status == AccountBanned
+}
+
+// Catégories de signalement ; other regroupe aussi les signalements antérieurs aux catégories
+const (
+	ReportSpam                = "spam"
+	ReportHarassment          = "harassment"
+	ReportFakeProfile         = "fake_profile"
+	ReportUnderage            = "underage"
+	ReportInappropriatePhotos = "inappropriate_photos"
+	ReportOther               = "other"
+)
+
+// IsValidReportCategory indique si la catégorie de signalement existe
+func IsValidReportCategory(category string) bool {
+	switch category {
+	case ReportSpam, ReportHarassment, ReportFakeProfile, ReportUnderage, ReportInappropriatePhotos, ReportOther:
+		return true
+	}
+	return false
+}
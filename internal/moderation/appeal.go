@@ -0,0 +1,95 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/models"
+)
+
+// Statuts d'une contestation
+const (
+	AppealOpen        = "open"
+	AppealUnderReview = "under_review"
+	AppealAccepted    = "accepted"
+	AppealRejected    = "rejected"
+)
+
+// Décisions d'un modérateur sur une contestation
+const (
+	AppealDecisionAccept = "accept" // Le compte est rétabli
+	AppealDecisionReject = "reject" // La sanction est maintenue
+)
+
+// Actions sur les contestations enregistrées dans le journal de modération
+const (
+	ActionAppealReview = "appeal.review"
+	ActionAppealDecide = "appeal.decide"
+)
+
+// TargetAppeal désigne une contestation dans le journal de modération
+const TargetAppeal = "appeal"
+
+// MaxAppealLength borne la longueur du message d'une contestation
+const MaxAppealLength = 2000
+
+var (
+	// ErrAppealNotFound est retournée pour une contestation inexistante
+	ErrAppealNotFound = errors.New("contestation introuvable")
+	// ErrNotAppealable est retournée lorsque le compte n'est ni suspendu ni banni
+	ErrNotAppealable = errors.New("votre compte ne fait l'objet d'aucune suspension ni d'aucun bannissement")
+	// ErrAppealPending est retournée lorsqu'une contestation de l'utilisateur est déjà en cours
+	ErrAppealPending = errors.New("une contestation est déjà en cours d'examen")
+	// ErrAppealMessage est retournée pour un message de contestation vide ou trop long
+	ErrAppealMessage = fmt.Errorf("le message doit contenir entre 1 et %d caractères", MaxAppealLength)
+	// ErrAppealDecided est retournée pour une action sur une contestation déjà tranchée
+	ErrAppealDecided = errors.New("contestation déjà tranchée")
+	// ErrAppealNotUnderReview est retournée lorsqu'une contestation doit d'abord être prise en examen
+	ErrAppealNotUnderReview = errors.New("la contestation doit d'abord être prise en examen")
+	// ErrAppealReviewer est retournée lorsqu'un autre modérateur examine la contestation
+	ErrAppealReviewer = errors.New("contestation examinée par un autre modérateur")
+	// ErrInvalidAppealDecision est retournée pour une décision inconnue
+	ErrInvalidAppealDecision = errors.New("décision de contestation invalide")
+	// ErrInvalidAppealStatus est retournée pour un filtre de statut inconnu
+	ErrInvalidAppealStatus = errors.New("statut de contestation invalide")
+)
+
+// Appeal représente la contestation d'une suspension ou d'un bannissement
+type Appeal struct {
+	ID            int         `json:"id"`
+	User          UserSummary `json:"user"`
+	Email         string      `json:"-"`
+	AccountStatus string      `json:"account_status"` // État du compte au dépôt
+	StatusReason  string      `json:"status_reason,omitempty"`
+	Message       string      `json:"message"`
+	Status        string      `json:"status"`
+	ReviewerID    *int        `json:"reviewer_id,omitempty"`
+	Comment       string      `json:"comment,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	DecidedAt     *time.Time  `json:"decided_at,omitempty"`
+}
+
+// AppealFilter restreint la liste des contestations ; les champs nuls sont ignorés
+type AppealFilter struct {
+	Status string
+	UserID int
+	Limit  int
+	Offset int
+}
+
+// AppealRepository interface pour les contestations. Chaque transition est
+// journalisée dans la même transaction.
+type AppealRepository interface {
+	// GetAccount récupère l'état du compte d'un utilisateur
+	GetAccount(userID int) (*models.User, error)
+	CreateAppeal(appeal *Appeal) error
+	ListAppeals(filter AppealFilter) ([]*Appeal, error)
+	GetAppeal(appealID int) (*Appeal, error)
+	// StartReview attribue une contestation ouverte au modérateur
+	StartReview(appealID, moderatorID int, entry *LogEntry) (bool, error)
+	// DecideAppeal tranche une contestation en cours d'examen par le modérateur
+	// (sauf force) et applique update (facultatif) dans la même transaction
+	DecideAppeal(appealID, moderatorID int, force bool, status, comment string, entry *LogEntry, update *AccountUpdate) (bool, error)
+}
@@ -0,0 +1,264 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/audit"
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/cduffaut/matcha/internal/validation"
+	"goji.io/pat"
+)
+
+// AppealHandlers gère les requêtes HTTP des contestations
+type AppealHandlers struct {
+	service *AppealService
+	audit   *audit.Service
+}
+
+// NewAppealHandlers crée de nouveaux handlers de contestation
+func NewAppealHandlers(service *AppealService, auditService *audit.Service) *AppealHandlers {
+	return &AppealHandlers{
+		service: service,
+		audit:   auditService,
+	}
+}
+
+// writeAppealError traduit les erreurs de contestation en statut HTTP
+func writeAppealError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrAppealNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrAppealPending), errors.Is(err, ErrAppealDecided),
+		errors.Is(err, ErrAppealNotUnderReview), errors.Is(err, ErrAppealReviewer):
+		writeJSON(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, ErrNotAppealable), errors.Is(err, ErrAppealMessage),
+		errors.Is(err, ErrInvalidAppealDecision), errors.Is(err, ErrInvalidAppealStatus):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	default:
+		writeError(w, err)
+	}
+}
+
+// appealID lit l'identifiant de contestation de l'URL
+func appealID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(pat.Param(r, "appealID"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ID de contestation invalide"})
+		return 0, false
+	}
+	return id, true
+}
+
+// AppealPageHandler affiche la page de contestation, seule page accessible à
+// un compte suspendu ou banni
+func (h *AppealHandlers) AppealPageHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := actorFromRequest(w, r); !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head>
+    <title>Contester une décision - Matcha</title>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="/static/css/auth.css">
+</head>
+<body>
+    <div class="container">
+        <h1>Contester une décision</h1>
+        <div id="account-status"></div>
+        <div id="appeals"></div>
+        <form id="appeal-form">
+            <div class="form-group">
+                <label for="appeal-message">Expliquez pourquoi cette décision devrait être revue</label>
+                <textarea id="appeal-message" name="message" rows="6" maxlength="2000" required></textarea>
+            </div>
+            <button type="submit">Envoyer ma contestation</button>
+        </form>
+        <div id="appeal-feedback"></div>
+        <p><a href="/logout">Déconnexion</a></p>
+    </div>
+    <script src="/static/js/csrf.js"></script>
+    <script src="/static/js/appeal.js"></script>
+</body>
+</html>`)
+}
+
+// GetMyAppealsHandler retourne l'état du compte et les contestations de l'utilisateur
+func (h *AppealHandlers) GetMyAppealsHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	account, appeals, err := h.service.Account(actor.UserID)
+	if err != nil {
+		writeAppealError(w, err)
+		return
+	}
+
+	locked := account.IsLocked(time.Now())
+	status := account.EffectiveStatus(time.Now())
+	response := map[string]interface{}{
+		"account_status": status,
+		"can_appeal":     locked,
+		"appeals":        appeals,
+	}
+	if locked {
+		response["reason"] = account.StatusReason
+		if status == models.AccountSuspended && account.SuspendedUntil != nil {
+			response["suspended_until"] = account.SuspendedUntil
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// SubmitAppealHandler dépose une contestation
+func (h *AppealHandlers) SubmitAppealHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<14)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Format de données invalide"})
+		return
+	}
+
+	appeal, err := h.service.Submit(actor.UserID, validation.SanitizeInput(req.Message))
+	if err != nil {
+		writeAppealError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Contestation enregistrée",
+		"appeal":  appeal,
+	})
+}
+
+// ListAppealsHandler liste les contestations (filtres : status, user_id, limit, offset)
+func (h *AppealHandlers) ListAppealsHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := actorFromRequest(w, r); !ok {
+		return
+	}
+
+	appeals, err := h.service.List(AppealFilter{
+		Status: r.URL.Query().Get("status"),
+		UserID: queryInt(r, "user_id"),
+		Limit:  queryInt(r, "limit"),
+		Offset: queryInt(r, "offset"),
+	})
+	if err != nil {
+		writeAppealError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"appeals": appeals,
+	})
+}
+
+// GetAppealHandler retourne une contestation
+func (h *AppealHandlers) GetAppealHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := actorFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := appealID(w, r)
+	if !ok {
+		return
+	}
+
+	appeal, err := h.service.Get(id)
+	if err != nil {
+		writeAppealError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"appeal": appeal,
+	})
+}
+
+// ReviewAppealHandler prend une contestation en examen
+func (h *AppealHandlers) ReviewAppealHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := appealID(w, r)
+	if !ok {
+		return
+	}
+
+	appeal, err := h.service.StartReview(actor, id)
+	if err != nil {
+		writeAppealError(w, err)
+		return
+	}
+
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    actor.UserID,
+		Action:     ActionAppealReview,
+		TargetType: TargetAppeal,
+		TargetID:   id,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Contestation prise en examen",
+		"appeal":  appeal,
+	})
+}
+
+// DecideAppealHandler tranche une contestation (decision : accept ou reject)
+func (h *AppealHandlers) DecideAppealHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := appealID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Decision string `json:"decision"`
+		Comment  string `json:"comment"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<14)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Format de données invalide"})
+		return
+	}
+
+	comment := validation.SanitizeInput(strings.TrimSpace(req.Comment))
+	appeal, err := h.service.Decide(actor, id, req.Decision, comment)
+	if err != nil {
+		writeAppealError(w, err)
+		return
+	}
+
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    actor.UserID,
+		Action:     ActionAppealDecide,
+		TargetType: TargetAppeal,
+		TargetID:   id,
+		Details:    map[string]interface{}{"decision": req.Decision, "comment": comment, "user_id": appeal.User.ID},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Contestation tranchée",
+		"appeal":  appeal,
+	})
+}
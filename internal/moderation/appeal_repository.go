@@ -0,0 +1,211 @@
+package moderation
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cduffaut/matcha/internal/database"
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/lib/pq"
+)
+
+// PostgresAppealRepository implémentation PostgreSQL du repository des contestations
+type PostgresAppealRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresAppealRepository crée un nouveau repository des contestations
+func NewPostgresAppealRepository(db *sql.DB) AppealRepository {
+	return &PostgresAppealRepository{db: db}
+}
+
+// GetAccount récupère l'état du compte d'un utilisateur
+func (r *PostgresAppealRepository) GetAccount(userID int) (*models.User, error) {
+	query := `
+		SELECT id, username, email, role, account_status, suspended_until, COALESCE(status_reason, '')
+		FROM users WHERE id = $1
+	`
+
+	account := &models.User{}
+	var until sql.NullTime
+	err := r.db.QueryRow(query, userID).Scan(
+		&account.ID, &account.Username, &account.Email, &account.Role,
+		&account.AccountStatus, &until, &account.StatusReason,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération du compte: %w", err)
+	}
+
+	if until.Valid {
+		account.SuspendedUntil = &until.Time
+	}
+	return account, nil
+}
+
+// CreateAppeal enregistre une contestation ; une seule peut être en cours par utilisateur
+func (r *PostgresAppealRepository) CreateAppeal(appeal *Appeal) error {
+	query := `
+		INSERT INTO appeals (user_id, account_status, status_reason, message)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		RETURNING id, status, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(query, appeal.User.ID, appeal.AccountStatus, appeal.StatusReason, appeal.Message).
+		Scan(&appeal.ID, &appeal.Status, &appeal.CreatedAt, &appeal.UpdatedAt)
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrAppealPending
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement de la contestation: %w", err)
+	}
+
+	return nil
+}
+
+const appealSelect = `
+	SELECT a.id, a.account_status, COALESCE(a.status_reason, ''), a.message, a.status, a.reviewer_id,
+	       COALESCE(a.decision_comment, ''), a.created_at, a.updated_at, a.decided_at,
+	       u.id, u.username, u.first_name, u.last_name, u.email
+	FROM appeals a
+	JOIN users u ON a.user_id = u.id
+`
+
+func scanAppeal(scanner interface{ Scan(...interface{}) error }) (*Appeal, error) {
+	appeal := &Appeal{}
+	var reviewerID sql.NullInt64
+	var decidedAt sql.NullTime
+
+	err := scanner.Scan(
+		&appeal.ID, &appeal.AccountStatus, &appeal.StatusReason, &appeal.Message, &appeal.Status, &reviewerID,
+		&appeal.Comment, &appeal.CreatedAt, &appeal.UpdatedAt, &decidedAt,
+		&appeal.User.ID, &appeal.User.Username, &appeal.User.FirstName, &appeal.User.LastName, &appeal.Email,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if reviewerID.Valid {
+		id := int(reviewerID.Int64)
+		appeal.ReviewerID = &id
+	}
+	if decidedAt.Valid {
+		appeal.DecidedAt = &decidedAt.Time
+	}
+
+	return appeal, nil
+}
+
+// ListAppeals récupère les contestations, les plus anciennes en cours d'abord
+func (r *PostgresAppealRepository) ListAppeals(filter AppealFilter) ([]*Appeal, error) {
+	var conditions []string
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "a.status = "+addArg(filter.Status))
+	}
+	if filter.UserID > 0 {
+		conditions = append(conditions, "a.user_id = "+addArg(filter.UserID))
+	}
+
+	query := appealSelect
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY a.status IN ('accepted', 'rejected'), a.created_at, a.id"
+	query += " LIMIT " + addArg(filter.Limit) + " OFFSET " + addArg(filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des contestations: %w", err)
+	}
+	defer rows.Close()
+
+	appeals := []*Appeal{}
+	for rows.Next() {
+		appeal, err := scanAppeal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'une contestation: %w", err)
+		}
+		appeals = append(appeals, appeal)
+	}
+
+	return appeals, rows.Err()
+}
+
+// GetAppeal récupère une contestation
+func (r *PostgresAppealRepository) GetAppeal(appealID int) (*Appeal, error) {
+	appeal, err := scanAppeal(r.db.QueryRow(appealSelect+" WHERE a.id = $1", appealID))
+	if err == sql.ErrNoRows {
+		return nil, ErrAppealNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération de la contestation: %w", err)
+	}
+	return appeal, nil
+}
+
+// StartReview attribue une contestation ouverte au modérateur
+func (r *PostgresAppealRepository) StartReview(appealID, moderatorID int, entry *LogEntry) (bool, error) {
+	query := `
+		UPDATE appeals
+		SET status = 'under_review', reviewer_id = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'open'
+	`
+	return r.updateAppeal(entry, nil, query, appealID, moderatorID)
+}
+
+// DecideAppeal tranche une contestation en cours d'examen
+func (r *PostgresAppealRepository) DecideAppeal(appealID, moderatorID int, force bool, status, comment string, entry *LogEntry, update *AccountUpdate) (bool, error) {
+	query := `
+		UPDATE appeals
+		SET status = $4, decision_comment = NULLIF($5, ''), reviewer_id = $2,
+		    decided_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'under_review'
+		AND (reviewer_id = $2 OR $3)
+	`
+
+	var then func(tx *sql.Tx) error
+	if update != nil {
+		then = func(tx *sql.Tx) error {
+			return applyAccountUpdate(tx, update, force)
+		}
+	}
+
+	return r.updateAppeal(entry, then, query, appealID, moderatorID, force, status, comment)
+}
+
+// updateAppeal applique une transition de statut et la journalise si elle a eu
+// lieu ; then (facultatif) s'exécute ensuite dans la même transaction
+func (r *PostgresAppealRepository) updateAppeal(entry *LogEntry, then func(tx *sql.Tx) error, query string, args ...interface{}) (bool, error) {
+	applied := false
+
+	err := database.WithTx(r.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			return fmt.Errorf("erreur lors de la mise à jour de la contestation: %w", err)
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return nil
+		}
+		applied = true
+
+		if err := insertLogEntry(tx, entry); err != nil {
+			return err
+		}
+		if then != nil {
+			return then(tx)
+		}
+		return nil
+	})
+
+	return applied, err
+}
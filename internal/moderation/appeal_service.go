@@ -0,0 +1,228 @@
+package moderation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cduffaut/matcha/internal/models"
+)
+
+// AppealSessions ferme les sessions restreintes d'un compte rétabli
+type AppealSessions interface {
+	RevokeRestricted(userID int)
+}
+
+// AppealMailer prévient l'utilisateur de chaque étape de sa contestation
+type AppealMailer interface {
+	SendAppealUpdate(to, username, status, comment, appealLink string) error
+}
+
+// AppealService gère les contestations des comptes suspendus ou bannis
+type AppealService struct {
+	repo     AppealRepository
+	sessions AppealSessions
+	mailer   AppealMailer
+	baseURL  string
+}
+
+// NewAppealService crée un nouveau service de contestation
+func NewAppealService(repo AppealRepository, sessions AppealSessions, mailer AppealMailer, baseURL string) *AppealService {
+	return &AppealService{
+		repo:     repo,
+		sessions: sessions,
+		mailer:   mailer,
+		baseURL:  baseURL,
+	}
+}
+
+// Account retourne l'état du compte d'un utilisateur et ses contestations
+func (s *AppealService) Account(userID int) (*models.User, []*Appeal, error) {
+	account, err := s.repo.GetAccount(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	appeals, err := s.repo.ListAppeals(AppealFilter{UserID: userID, Limit: maxPageSize})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// L'identité du modérateur n'est pas communiquée à l'utilisateur
+	for _, appeal := range appeals {
+		appeal.ReviewerID = nil
+	}
+	return account, appeals, nil
+}
+
+// Submit dépose la contestation d'un compte actuellement suspendu ou banni
+func (s *AppealService) Submit(userID int, message string) (*Appeal, error) {
+	message = strings.TrimSpace(message)
+	if message == "" || utf8.RuneCountInString(message) > MaxAppealLength {
+		return nil, ErrAppealMessage
+	}
+
+	account, err := s.repo.GetAccount(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !account.IsLocked(time.Now()) {
+		return nil, ErrNotAppealable
+	}
+
+	appeal := &Appeal{
+		User:          UserSummary{ID: account.ID, Username: account.Username},
+		Email:         account.Email,
+		AccountStatus: account.EffectiveStatus(time.Now()),
+		StatusReason:  account.StatusReason,
+		Message:       message,
+	}
+	if err := s.repo.CreateAppeal(appeal); err != nil {
+		return nil, err
+	}
+
+	s.notify(appeal)
+	return appeal, nil
+}
+
+// List récupère les contestations selon le filtre
+func (s *AppealService) List(filter AppealFilter) ([]*Appeal, error) {
+	switch filter.Status {
+	case "", AppealOpen, AppealUnderReview, AppealAccepted, AppealRejected:
+	default:
+		return nil, ErrInvalidAppealStatus
+	}
+
+	filter.Limit = pageSize(filter.Limit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListAppeals(filter)
+}
+
+// Get récupère une contestation
+func (s *AppealService) Get(appealID int) (*Appeal, error) {
+	return s.repo.GetAppeal(appealID)
+}
+
+// StartReview prend une contestation ouverte en examen
+func (s *AppealService) StartReview(actor Actor, appealID int) (*Appeal, error) {
+	entry := &LogEntry{
+		ModeratorID: actor.UserID,
+		Action:      ActionAppealReview,
+		TargetType:  TargetAppeal,
+		TargetID:    appealID,
+	}
+
+	applied, err := s.repo.StartReview(appealID, actor.UserID, entry)
+	appeal, err := s.afterTransition(actor, appealID, applied, err)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notify(appeal)
+	return appeal, nil
+}
+
+// Decide accepte ou rejette une contestation en cours d'examen ; l'acceptation
+// rétablit le compte dans la même transaction. Un administrateur peut trancher
+// une contestation examinée par un autre modérateur.
+func (s *AppealService) Decide(actor Actor, appealID int, decision, comment string) (*Appeal, error) {
+	status := AppealRejected
+	switch decision {
+	case AppealDecisionAccept:
+		status = AppealAccepted
+	case AppealDecisionReject:
+	default:
+		return nil, ErrInvalidAppealDecision
+	}
+	if utf8.RuneCountInString(comment) > MaxCommentLength {
+		return nil, ErrCommentTooLong
+	}
+
+	appeal, err := s.repo.GetAppeal(appealID)
+	if err != nil {
+		return nil, err
+	}
+	if appeal.User.ID == actor.UserID {
+		return nil, ErrProtectedAccount
+	}
+
+	entry := &LogEntry{
+		ModeratorID: actor.UserID,
+		Action:      ActionAppealDecide,
+		TargetType:  TargetAppeal,
+		TargetID:    appealID,
+		Details: map[string]interface{}{
+			"decision": decision,
+			"comment":  comment,
+		},
+	}
+
+	var update *AccountUpdate
+	if status == AppealAccepted {
+		update = &AccountUpdate{
+			UserID: appeal.User.ID,
+			Status: models.AccountActive,
+			Reason: "Contestation acceptée",
+			Entry: &LogEntry{
+				ModeratorID: actor.UserID,
+				Action:      ActionAccountStatus,
+				TargetType:  TargetUser,
+				TargetID:    appeal.User.ID,
+				Details: map[string]interface{}{
+					"sanction":  SanctionReinstate,
+					"appeal_id": appealID,
+				},
+			},
+		}
+	}
+
+	applied, err := s.repo.DecideAppeal(appealID, actor.UserID, isAdmin(actor), status, comment, entry, update)
+	appeal, err = s.afterTransition(actor, appealID, applied, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if update != nil {
+		s.sessions.RevokeRestricted(appeal.User.ID)
+	}
+	s.notify(appeal)
+	return appeal, nil
+}
+
+// afterTransition retourne la contestation à jour, ou la raison pour laquelle
+// la transition n'a pas pu s'appliquer
+func (s *AppealService) afterTransition(actor Actor, appealID int, applied bool, err error) (*Appeal, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	appeal, err := s.repo.GetAppeal(appealID)
+	if err != nil {
+		return nil, err
+	}
+	if applied {
+		return appeal, nil
+	}
+
+	switch {
+	case appeal.Status == AppealAccepted || appeal.Status == AppealRejected:
+		return nil, ErrAppealDecided
+	case appeal.Status == AppealOpen:
+		return nil, ErrAppealNotUnderReview
+	case appeal.ReviewerID != nil && *appeal.ReviewerID != actor.UserID:
+		return nil, ErrAppealReviewer
+	default:
+		return nil, fmt.Errorf("transition impossible pour la contestation %d (statut %s)", appealID, appeal.Status)
+	}
+}
+
+// notify prévient l'utilisateur du nouveau statut de sa contestation
+func (s *AppealService) notify(appeal *Appeal) {
+	link := s.baseURL + "/appeal"
+	if err := s.mailer.SendAppealUpdate(appeal.Email, appeal.User.Username, appeal.Status, appeal.Comment, link); err != nil {
+		fmt.Printf("Erreur envoi de l'email de contestation %d: %v\n", appeal.ID, err)
+	}
+}
@@ -0,0 +1,375 @@
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/audit"
+	"github.com/cduffaut/matcha/internal/session"
+	"github.com/cduffaut/matcha/internal/validation"
+	"goji.io/pat"
+)
+
+// Handlers gère les requêtes HTTP de l'API de modération
+type Handlers struct {
+	service *Service
+	audit   *audit.Service
+}
+
+// NewHandlers crée de nouveaux handlers de modération
+func NewHandlers(service *Service, auditService *audit.Service) *Handlers {
+	return &Handlers{
+		service: service,
+		audit:   auditService,
+	}
+}
+
+// actorFromRequest identifie le modérateur à l'origine de la requête
+func actorFromRequest(w http.ResponseWriter, r *http.Request) (Actor, bool) {
+	userSession, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return Actor{}, false
+	}
+	return Actor{UserID: userSession.UserID, Role: userSession.Role}, true
+}
+
+// queryInt lit un paramètre entier positif, 0 s'il est absent ou invalide
+func queryInt(r *http.Request, name string) int {
+	value, err := strconv.Atoi(r.URL.Query().Get(name))
+	if err != nil || value < 0 {
+		return 0
+	}
+	return value
+}
+
+// writeJSON écrit une réponse JSON avec le statut donné
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError traduit les erreurs de modération en statut HTTP
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	message := "Erreur lors de l'opération de modération"
+
+	switch {
+	case errors.Is(err, ErrReportNotFound), errors.Is(err, ErrRiskNotFound):
+		status, message = http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrReportResolved), errors.Is(err, ErrReportClaimed), errors.Is(err, ErrReportNotClaimed):
+		status, message = http.StatusConflict, err.Error()
+	case errors.Is(err, ErrInvalidStatus), errors.Is(err, ErrInvalidCategory), errors.Is(err, ErrInvalidResolution), errors.Is(err, ErrCommentTooLong),
+		errors.Is(err, ErrInvalidRole), errors.Is(err, ErrOwnRole),
+		errors.Is(err, ErrInvalidSanction), errors.Is(err, ErrSanctionRequiresUpheld),
+		errors.Is(err, ErrInvalidRiskDecision):
+		status, message = http.StatusBadRequest, err.Error()
+	case errors.Is(err, ErrProtectedAccount):
+		status, message = http.StatusForbidden, err.Error()
+	case errors.Is(err, ErrUserNotFound):
+		status, message = http.StatusNotFound, err.Error()
+	}
+
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// reportID lit l'identifiant de signalement de l'URL
+func reportID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(pat.Param(r, "reportID"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ID de signalement invalide"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ListReportsHandler liste les signalements (filtres : status, category, reporter_id,
+// reported_id, claimed_by — « me » pour les siens —, limit, offset)
+func (h *Handlers) ListReportsHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	filter := ReportFilter{
+		Status:     r.URL.Query().Get("status"),
+		Category:   r.URL.Query().Get("category"),
+		ReporterID: queryInt(r, "reporter_id"),
+		ReportedID: queryInt(r, "reported_id"),
+		ClaimedBy:  queryInt(r, "claimed_by"),
+		Limit:      queryInt(r, "limit"),
+		Offset:     queryInt(r, "offset"),
+	}
+	if r.URL.Query().Get("claimed_by") == "me" {
+		filter.ClaimedBy = actor.UserID
+	}
+
+	reports, err := h.service.ListReports(filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"reports": reports,
+	})
+}
+
+// GetReportHandler retourne un signalement
+func (h *Handlers) GetReportHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := actorFromRequest(w, r); !ok {
+		return
+	}
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetReport(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"report": report,
+	})
+}
+
+// ClaimReportHandler prend un signalement en charge
+func (h *Handlers) ClaimReportHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.ClaimReport(actor, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    actor.UserID,
+		Action:     ActionReportClaim,
+		TargetType: TargetReport,
+		TargetID:   id,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Signalement pris en charge",
+		"report":  report,
+	})
+}
+
+// ReleaseReportHandler rend un signalement à la file
+func (h *Handlers) ReleaseReportHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.ReleaseReport(actor, id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    actor.UserID,
+		Action:     ActionReportRelease,
+		TargetType: TargetReport,
+		TargetID:   id,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Signalement remis dans la file",
+		"report":  report,
+	})
+}
+
+// ResolveReportHandler clôt un signalement avec une issue et un commentaire
+func (h *Handlers) ResolveReportHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+	id, ok := reportID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Resolution string           `json:"resolution"`
+		Comment    string           `json:"comment"`
+		Sanction   *sanctionRequest `json:"sanction"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<14)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Format de données invalide"})
+		return
+	}
+
+	var sanction *Sanction
+	if req.Sanction != nil {
+		s := req.Sanction.toSanction()
+		sanction = &s
+	}
+
+	comment := validation.SanitizeInput(strings.TrimSpace(req.Comment))
+	report, err := h.service.ResolveReport(actor, id, req.Resolution, comment, sanction)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	details := map[string]interface{}{"resolution": req.Resolution, "comment": comment}
+	if sanction != nil {
+		details["sanction"] = sanction.Action
+	}
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    actor.UserID,
+		Action:     ActionReportResolve,
+		TargetType: TargetReport,
+		TargetID:   id,
+		Details:    details,
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Signalement résolu",
+		"report":  report,
+	})
+}
+
+// sanctionRequest est le corps d'une sanction ; duration_hours ne concerne que les suspensions
+type sanctionRequest struct {
+	Action        string `json:"action"`
+	Reason        string `json:"reason"`
+	DurationHours int    `json:"duration_hours"`
+}
+
+func (req *sanctionRequest) toSanction() Sanction {
+	return Sanction{
+		Action:   req.Action,
+		Reason:   validation.SanitizeInput(req.Reason),
+		Duration: time.Duration(req.DurationHours) * time.Hour,
+	}
+}
+
+// SanctionUserHandler avertit, suspend, bannit, shadow-bannit ou rétablit un compte
+func (h *Handlers) SanctionUserHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(pat.Param(r, "userID"))
+	if err != nil || userID <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ID utilisateur invalide"})
+		return
+	}
+
+	var req sanctionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<14)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Format de données invalide"})
+		return
+	}
+
+	sanction := req.toSanction()
+	if err := h.service.ApplySanction(actor, userID, sanction); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    actor.UserID,
+		Action:     ActionAccountStatus,
+		TargetType: TargetUser,
+		TargetID:   userID,
+		Details:    map[string]interface{}{"sanction": sanction.Action, "reason": sanction.Reason, "duration_hours": req.DurationHours},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Sanction appliquée",
+		"user_id": userID,
+		"action":  req.Action,
+	})
+}
+
+// UpdateRoleHandler modifie le rôle d'un utilisateur (administrateurs uniquement)
+func (h *Handlers) UpdateRoleHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	userID, err := strconv.Atoi(pat.Param(r, "userID"))
+	if err != nil || userID <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ID utilisateur invalide"})
+		return
+	}
+
+	var req struct {
+		Role string `json:"role"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1024)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Format de données invalide"})
+		return
+	}
+
+	if err := h.service.ChangeRole(actor, userID, req.Role); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    actor.UserID,
+		Action:     ActionRoleChange,
+		TargetType: TargetUser,
+		TargetID:   userID,
+		Details:    map[string]interface{}{"role": req.Role},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Rôle mis à jour",
+		"user_id": userID,
+		"role":    req.Role,
+	})
+}
+
+// ListLogHandler consulte le journal de modération (filtres : moderator_id,
+// action, target_type, target_id, limit, offset)
+func (h *Handlers) ListLogHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := actorFromRequest(w, r); !ok {
+		return
+	}
+
+	entries, err := h.service.ListLog(LogFilter{
+		ModeratorID: queryInt(r, "moderator_id"),
+		Action:      r.URL.Query().Get("action"),
+		TargetType:  r.URL.Query().Get("target_type"),
+		TargetID:    queryInt(r, "target_id"),
+		Limit:       queryInt(r, "limit"),
+		Offset:      queryInt(r, "offset"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"entries": entries,
+	})
+}
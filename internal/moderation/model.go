@@ -0,0 +1,221 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/models"
+)
+
+// Statuts d'un signalement
+const (
+	StatusOpen     = "open"
+	StatusClaimed  = "claimed"
+	StatusResolved = "resolved"
+)
+
+// Issues possibles d'un signalement
+const (
+	ResolutionDismissed = "dismissed" // Signalement infondé
+	ResolutionUpheld    = "upheld"    // Signalement fondé
+)
+
+// Actions enregistrées dans le journal de modération
+const (
+	ActionReportClaim   = "report.claim"
+	ActionReportRelease = "report.release"
+	ActionReportResolve = "report.resolve"
+	ActionRoleChange    = "user.role_change"
+	ActionAccountStatus = "user.account_status"
+)
+
+// Sanctions applicables à un compte
+const (
+	SanctionWarn      = "warn"
+	SanctionSuspend   = "suspend"
+	SanctionBan       = "ban"
+	SanctionShadowBan = "shadow_ban"
+	SanctionReinstate = "reinstate" // Lève la sanction en cours
+)
+
+// sanctionStatuses associe chaque sanction à l'état de compte qui en résulte
+var sanctionStatuses = map[string]string{
+	SanctionWarn:      models.AccountWarned,
+	SanctionSuspend:   models.AccountSuspended,
+	SanctionBan:       models.AccountBanned,
+	SanctionShadowBan: models.AccountShadowBanned,
+	SanctionReinstate: models.AccountActive,
+}
+
+// Bornes des sanctions
+const (
+	MaxSuspension     = 365 * 24 * time.Hour
+	MaxSanctionReason = 500
+)
+
+// Types de cibles des actions de modération
+const (
+	TargetReport = "report"
+	TargetUser   = "user"
+)
+
+var (
+	// ErrReportNotFound est retournée pour un signalement inexistant
+	ErrReportNotFound = errors.New("signalement introuvable")
+	// ErrUserNotFound est retournée pour un utilisateur inexistant
+	ErrUserNotFound = errors.New("utilisateur introuvable")
+	// ErrReportResolved est retournée pour une action sur un signalement déjà résolu
+	ErrReportResolved = errors.New("signalement déjà résolu")
+	// ErrReportClaimed est retournée lorsqu'un autre modérateur a pris le signalement en charge
+	ErrReportClaimed = errors.New("signalement pris en charge par un autre modérateur")
+	// ErrReportNotClaimed est retournée lorsqu'un signalement doit d'abord être pris en charge
+	ErrReportNotClaimed = errors.New("le signalement doit d'abord être pris en charge")
+	// ErrInvalidResolution est retournée pour une issue de signalement inconnue
+	ErrInvalidResolution = errors.New("issue de signalement invalide")
+	// ErrInvalidCategory est retournée pour un filtre de catégorie inconnu
+	ErrInvalidCategory = errors.New("catégorie de signalement invalide")
+	// ErrInvalidStatus est retournée pour un filtre de statut inconnu
+	ErrInvalidStatus = errors.New("statut de signalement invalide")
+	// ErrCommentTooLong est retournée pour un commentaire de résolution trop long
+	ErrCommentTooLong = fmt.Errorf("le commentaire doit contenir au maximum %d caractères", MaxCommentLength)
+	// ErrInvalidRole est retournée pour un rôle inconnu
+	ErrInvalidRole = errors.New("rôle invalide")
+	// ErrInvalidSanction est retournée pour une sanction inconnue ou mal paramétrée
+	ErrInvalidSanction = errors.New("sanction invalide")
+	// ErrSanctionRequiresUpheld est retournée pour une sanction jointe à un signalement rejeté
+	ErrSanctionRequiresUpheld = errors.New("une sanction suppose un signalement fondé")
+	// ErrProtectedAccount est retournée lorsqu'un modérateur vise son compte ou celui d'un membre de l'équipe
+	ErrProtectedAccount = errors.New("seul un administrateur peut sanctionner un membre de l'équipe de modération")
+	// ErrOwnRole est retournée lorsqu'un administrateur tente de modifier son propre rôle
+	ErrOwnRole = errors.New("vous ne pouvez pas modifier votre propre rôle")
+)
+
+// MaxCommentLength borne la longueur du commentaire de résolution
+const MaxCommentLength = 1000
+
+// Actor est le modérateur ou l'administrateur à l'origine d'une action
+type Actor struct {
+	UserID int
+	Role   string
+}
+
+// UserSummary identifie un utilisateur dans les réponses de modération
+type UserSummary struct {
+	ID        int    `json:"id"`
+	Username  string `json:"username"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// Report représente un signalement vu par les modérateurs
+type Report struct {
+	ID          int         `json:"id"`
+	Reporter    UserSummary `json:"reporter"`
+	Reported    UserSummary `json:"reported"`
+	Category    string      `json:"category"`
+	Reason      string      `json:"reason"`
+	Status      string      `json:"status"`
+	ClaimedBy   *int        `json:"claimed_by,omitempty"`
+	ClaimedAt   *time.Time  `json:"claimed_at,omitempty"`
+	ResolvedBy  *int        `json:"resolved_by,omitempty"`
+	Resolution  string      `json:"resolution,omitempty"`
+	Comment     string      `json:"comment,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	ProcessedAt *time.Time  `json:"processed_at,omitempty"`
+	MessageIDs  []int       `json:"message_ids"`
+	PhotoIDs    []int       `json:"photo_ids"`
+	// Contenu des pièces jointes, chargé avec le détail d'un signalement
+	Evidence *Evidence `json:"evidence,omitempty"`
+}
+
+// Evidence regroupe les pièces jointes d'un signalement encore disponibles ;
+// un message ou une photo supprimé depuis n'y figure plus
+type Evidence struct {
+	Messages []EvidenceMessage `json:"messages"`
+	Photos   []EvidencePhoto   `json:"photos"`
+}
+
+// EvidenceMessage est un message joint à un signalement
+type EvidenceMessage struct {
+	ID          int       `json:"id"`
+	SenderID    int       `json:"sender_id"`
+	RecipientID int       `json:"recipient_id"`
+	Content     string    `json:"content"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// EvidencePhoto est une photo jointe à un signalement
+type EvidencePhoto struct {
+	ID       int    `json:"id"`
+	FilePath string `json:"file_path"`
+}
+
+// ReportFilter restreint la liste des signalements ; les champs nuls sont ignorés
+type ReportFilter struct {
+	Status     string
+	Category   string
+	ReporterID int
+	ReportedID int
+	ClaimedBy  int
+	Limit      int
+	Offset     int
+}
+
+// LogEntry est une action de modération journalisée
+type LogEntry struct {
+	ID          int                    `json:"id"`
+	ModeratorID int                    `json:"moderator_id"`
+	Action      string                 `json:"action"`
+	TargetType  string                 `json:"target_type"`
+	TargetID    int                    `json:"target_id"`
+	Details     map[string]interface{} `json:"details"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+// LogFilter restreint la consultation du journal ; les champs nuls sont ignorés
+type LogFilter struct {
+	ModeratorID int
+	Action      string
+	TargetType  string
+	TargetID    int
+	Limit       int
+	Offset      int
+}
+
+// Sanction est une action sur un compte demandée par un modérateur
+type Sanction struct {
+	Action   string        `json:"action"`
+	Reason   string        `json:"reason"`
+	Duration time.Duration `json:"-"` // Durée d'une suspension
+}
+
+// AccountUpdate est le changement d'état d'un compte à appliquer et journaliser
+type AccountUpdate struct {
+	UserID int
+	Status string
+	Until  *time.Time
+	Reason string
+	Entry  *LogEntry
+	// Renseignés par le repository, pour prévenir l'utilisateur
+	Email    string
+	Username string
+}
+
+// Repository interface pour les signalements et le journal de modération.
+// Chaque modification est journalisée dans la même transaction que l'action.
+type Repository interface {
+	ListReports(filter ReportFilter) ([]*Report, error)
+	// GetReport récupère un signalement ; withEvidence charge le contenu des pièces jointes
+	GetReport(reportID int, withEvidence bool) (*Report, error)
+	// ClaimReport attribue le signalement au modérateur s'il n'est pas résolu
+	// et n'est pas pris en charge par quelqu'un d'autre (sauf force)
+	ClaimReport(reportID, moderatorID int, force bool, entry *LogEntry) (bool, error)
+	ReleaseReport(reportID, moderatorID int, force bool, entry *LogEntry) (bool, error)
+	// ResolveReport clôt le signalement et applique update (facultatif) dans la même transaction
+	ResolveReport(reportID, moderatorID int, force bool, resolution, comment string, entry *LogEntry, update *AccountUpdate) (bool, error)
+	// UpdateAccountStatus applique une sanction ; force autorise à viser un membre de l'équipe
+	UpdateAccountStatus(update *AccountUpdate, force bool) error
+	UpdateRole(userID int, role string, entry *LogEntry) error
+	ListLog(filter LogFilter) ([]*LogEntry, error)
+}
@@ -0,0 +1,405 @@
+package moderation
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cduffaut/matcha/internal/database"
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/lib/pq"
+)
+
+// PostgresRepository implémentation PostgreSQL du repository de modération
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository crée un nouveau repository de modération
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &PostgresRepository{db: db}
+}
+
+const reportSelect = `
+	SELECT ur.id, ur.category, ur.reason, ur.status, ur.claimed_by, ur.claimed_at, ur.resolved_by,
+	       COALESCE(ur.resolution, ''), COALESCE(ur.admin_comment, ''), ur.created_at, ur.processed_at,
+	       ur.evidence_message_ids, ur.evidence_photo_ids,
+	       u1.id, u1.username, u1.first_name, u1.last_name,
+	       u2.id, u2.username, u2.first_name, u2.last_name
+	FROM user_reports ur
+	JOIN users u1 ON ur.reporter_id = u1.id
+	JOIN users u2 ON ur.reported_id = u2.id
+`
+
+func scanReport(scanner interface{ Scan(...interface{}) error }) (*Report, error) {
+	report := &Report{}
+	var claimedBy, resolvedBy sql.NullInt64
+	var claimedAt, processedAt sql.NullTime
+
+	err := scanner.Scan(
+		&report.ID, &report.Category, &report.Reason, &report.Status, &claimedBy, &claimedAt, &resolvedBy,
+		&report.Resolution, &report.Comment, &report.CreatedAt, &processedAt,
+		pq.Array(&report.MessageIDs), pq.Array(&report.PhotoIDs),
+		&report.Reporter.ID, &report.Reporter.Username, &report.Reporter.FirstName, &report.Reporter.LastName,
+		&report.Reported.ID, &report.Reported.Username, &report.Reported.FirstName, &report.Reported.LastName,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claimedBy.Valid {
+		id := int(claimedBy.Int64)
+		report.ClaimedBy = &id
+	}
+	if claimedAt.Valid {
+		report.ClaimedAt = &claimedAt.Time
+	}
+	if resolvedBy.Valid {
+		id := int(resolvedBy.Int64)
+		report.ResolvedBy = &id
+	}
+	if processedAt.Valid {
+		report.ProcessedAt = &processedAt.Time
+	}
+
+	return report, nil
+}
+
+// ListReports récupère les signalements, les plus anciens non résolus d'abord
+func (r *PostgresRepository) ListReports(filter ReportFilter) ([]*Report, error) {
+	var conditions []string
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "ur.status = "+addArg(filter.Status))
+	}
+	if filter.Category != "" {
+		conditions = append(conditions, "ur.category = "+addArg(filter.Category))
+	}
+	if filter.ReporterID > 0 {
+		conditions = append(conditions, "ur.reporter_id = "+addArg(filter.ReporterID))
+	}
+	if filter.ReportedID > 0 {
+		conditions = append(conditions, "ur.reported_id = "+addArg(filter.ReportedID))
+	}
+	if filter.ClaimedBy > 0 {
+		conditions = append(conditions, "ur.claimed_by = "+addArg(filter.ClaimedBy))
+	}
+
+	query := reportSelect
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY ur.status = 'resolved', ur.created_at, ur.id"
+	query += " LIMIT " + addArg(filter.Limit) + " OFFSET " + addArg(filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des signalements: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []*Report{}
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un signalement: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, rows.Err()
+}
+
+// GetReport récupère un signalement et, sur demande, le contenu de ses pièces jointes
+func (r *PostgresRepository) GetReport(reportID int, withEvidence bool) (*Report, error) {
+	report, err := scanReport(r.db.QueryRow(reportSelect+" WHERE ur.id = $1", reportID))
+	if err == sql.ErrNoRows {
+		return nil, ErrReportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération du signalement: %w", err)
+	}
+
+	if withEvidence {
+		if report.Evidence, err = r.loadEvidence(report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// loadEvidence charge les messages et photos joints à un signalement
+func (r *PostgresRepository) loadEvidence(report *Report) (*Evidence, error) {
+	evidence := &Evidence{Messages: []EvidenceMessage{}, Photos: []EvidencePhoto{}}
+
+	if len(report.MessageIDs) > 0 {
+		query := `
+			SELECT id, sender_id, recipient_id, content, created_at
+			FROM messages WHERE id = ANY($1)
+			ORDER BY created_at, id
+		`
+		rows, err := r.db.Query(query, pq.Array(report.MessageIDs))
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la récupération des messages joints: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var message EvidenceMessage
+			if err := rows.Scan(&message.ID, &message.SenderID, &message.RecipientID, &message.Content, &message.CreatedAt); err != nil {
+				return nil, fmt.Errorf("erreur lors de la lecture d'un message joint: %w", err)
+			}
+			evidence.Messages = append(evidence.Messages, message)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(report.PhotoIDs) > 0 {
+		rows, err := r.db.Query("SELECT id, file_path FROM user_photos WHERE id = ANY($1) ORDER BY id", pq.Array(report.PhotoIDs))
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la récupération des photos jointes: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var photo EvidencePhoto
+			if err := rows.Scan(&photo.ID, &photo.FilePath); err != nil {
+				return nil, fmt.Errorf("erreur lors de la lecture d'une photo jointe: %w", err)
+			}
+			evidence.Photos = append(evidence.Photos, photo)
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	return evidence, nil
+}
+
+// ClaimReport attribue le signalement au modérateur
+func (r *PostgresRepository) ClaimReport(reportID, moderatorID int, force bool, entry *LogEntry) (bool, error) {
+	query := `
+		UPDATE user_reports
+		SET status = 'claimed', claimed_by = $2, claimed_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status <> 'resolved'
+		AND (claimed_by IS NULL OR claimed_by = $2 OR $3)
+	`
+	return r.updateReport(entry, nil, query, reportID, moderatorID, force)
+}
+
+// ReleaseReport remet le signalement dans la file des signalements ouverts
+func (r *PostgresRepository) ReleaseReport(reportID, moderatorID int, force bool, entry *LogEntry) (bool, error) {
+	query := `
+		UPDATE user_reports
+		SET status = 'open', claimed_by = NULL, claimed_at = NULL
+		WHERE id = $1 AND status = 'claimed'
+		AND (claimed_by = $2 OR $3)
+	`
+	return r.updateReport(entry, nil, query, reportID, moderatorID, force)
+}
+
+// ResolveReport clôt un signalement pris en charge par le modérateur
+func (r *PostgresRepository) ResolveReport(reportID, moderatorID int, force bool, resolution, comment string, entry *LogEntry, update *AccountUpdate) (bool, error) {
+	query := `
+		UPDATE user_reports
+		SET status = 'resolved', resolved_by = $2, resolution = $4, admin_comment = $5,
+		    is_processed = TRUE, processed_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'claimed'
+		AND (claimed_by = $2 OR $3)
+	`
+
+	var then func(tx *sql.Tx) error
+	if update != nil {
+		then = func(tx *sql.Tx) error {
+			return applyAccountUpdate(tx, update, force)
+		}
+	}
+
+	return r.updateReport(entry, then, query, reportID, moderatorID, force, resolution, comment)
+}
+
+// updateReport applique une transition de statut et la journalise si elle a eu
+// lieu ; then (facultatif) s'exécute ensuite dans la même transaction
+func (r *PostgresRepository) updateReport(entry *LogEntry, then func(tx *sql.Tx) error, query string, args ...interface{}) (bool, error) {
+	applied := false
+
+	err := database.WithTx(r.db, func(tx *sql.Tx) error {
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			return fmt.Errorf("erreur lors de la mise à jour du signalement: %w", err)
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return nil
+		}
+		applied = true
+
+		if err := insertLogEntry(tx, entry); err != nil {
+			return err
+		}
+		if then != nil {
+			return then(tx)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// UpdateAccountStatus applique une sanction à un compte
+func (r *PostgresRepository) UpdateAccountStatus(update *AccountUpdate, force bool) error {
+	return database.WithTx(r.db, func(tx *sql.Tx) error {
+		return applyAccountUpdate(tx, update, force)
+	})
+}
+
+// applyAccountUpdate modifie l'état du compte et le journalise dans la transaction
+// fournie ; sans force, seuls les comptes de rôle user peuvent être visés
+func applyAccountUpdate(tx *sql.Tx, update *AccountUpdate, force bool) error {
+	var role, previous string
+	err := tx.QueryRow(
+		"SELECT role, account_status, email, username FROM users WHERE id = $1 FOR UPDATE", update.UserID,
+	).Scan(&role, &previous, &update.Email, &update.Username)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de la récupération du compte: %w", err)
+	}
+
+	if role != models.RoleUser && !force {
+		return ErrProtectedAccount
+	}
+
+	query := `
+		UPDATE users
+		SET account_status = $2, suspended_until = $3, status_reason = NULLIF($4, ''),
+		    status_changed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`
+	if _, err := tx.Exec(query, update.UserID, update.Status, update.Until, update.Reason); err != nil {
+		return fmt.Errorf("erreur lors de la mise à jour de l'état du compte: %w", err)
+	}
+
+	entry := update.Entry
+	if entry.Details == nil {
+		entry.Details = map[string]interface{}{}
+	}
+	entry.Details["previous_status"] = previous
+	entry.Details["status"] = update.Status
+	entry.Details["reason"] = update.Reason
+	if update.Until != nil {
+		entry.Details["until"] = update.Until
+	}
+
+	return insertLogEntry(tx, entry)
+}
+
+// UpdateRole modifie le rôle d'un utilisateur
+func (r *PostgresRepository) UpdateRole(userID int, role string, entry *LogEntry) error {
+	return database.WithTx(r.db, func(tx *sql.Tx) error {
+		var previous string
+		err := tx.QueryRow("SELECT role FROM users WHERE id = $1 FOR UPDATE", userID).Scan(&previous)
+		if err == sql.ErrNoRows {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("erreur lors de la récupération du rôle: %w", err)
+		}
+
+		if _, err := tx.Exec("UPDATE users SET role = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1", userID, role); err != nil {
+			return fmt.Errorf("erreur lors de la mise à jour du rôle: %w", err)
+		}
+
+		if entry.Details == nil {
+			entry.Details = map[string]interface{}{}
+		}
+		entry.Details["previous_role"] = previous
+
+		return insertLogEntry(tx, entry)
+	})
+}
+
+// insertLogEntry journalise une action de modération dans la transaction fournie ;
+// un ModeratorID nul désigne une action automatique
+func insertLogEntry(tx *sql.Tx, entry *LogEntry) error {
+	if entry.Details == nil {
+		entry.Details = map[string]interface{}{}
+	}
+	details, err := json.Marshal(entry.Details)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'encodage du détail de l'action: %w", err)
+	}
+
+	query := `
+		INSERT INTO moderation_log (moderator_id, action, target_type, target_id, details)
+		VALUES (NULLIF($1, 0), $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err = tx.QueryRow(query, entry.ModeratorID, entry.Action, entry.TargetType, entry.TargetID, details).
+		Scan(&entry.ID, &entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la journalisation de l'action: %w", err)
+	}
+
+	return nil
+}
+
+// ListLog récupère les actions de modération, les plus récentes d'abord
+func (r *PostgresRepository) ListLog(filter LogFilter) ([]*LogEntry, error) {
+	var conditions []string
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.ModeratorID > 0 {
+		conditions = append(conditions, "moderator_id = "+addArg(filter.ModeratorID))
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = "+addArg(filter.Action))
+	}
+	if filter.TargetType != "" {
+		conditions = append(conditions, "target_type = "+addArg(filter.TargetType))
+	}
+	if filter.TargetID > 0 {
+		conditions = append(conditions, "target_id = "+addArg(filter.TargetID))
+	}
+
+	query := "SELECT id, COALESCE(moderator_id, 0), action, target_type, target_id, details, created_at FROM moderation_log"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT " + addArg(filter.Limit) + " OFFSET " + addArg(filter.Offset)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération du journal de modération: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*LogEntry{}
+	for rows.Next() {
+		entry := &LogEntry{}
+		var details []byte
+		if err := rows.Scan(&entry.ID, &entry.ModeratorID, &entry.Action, &entry.TargetType, &entry.TargetID, &details, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture du journal de modération: %w", err)
+		}
+		if err := json.Unmarshal(details, &entry.Details); err != nil {
+			return nil, fmt.Errorf("détail illisible pour l'action %d: %w", entry.ID, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
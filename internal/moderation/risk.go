@@ -0,0 +1,269 @@
+package moderation
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/config"
+)
+
+// Types d'événements examinés par le détecteur de comptes à risque
+const (
+	EventReport  = "report"  // UserID signalé par ActorID
+	EventLike    = "like"    // UserID a liké ActorID
+	EventMessage = "message" // UserID a écrit Key (empreinte du contenu) à ActorID
+	EventPhoto   = "photo"   // UserID possède une photo d'empreinte Key
+	EventSignup  = "signup"  // Création du compte UserID
+)
+
+// Signaux de risque, repris dans l'explication d'un score
+const (
+	SignalReporters  = "distinct_reporters"
+	SignalLikeSpray  = "like_spray"
+	SignalDuplicates = "duplicate_messages"
+	SignalNewAccount = "new_account_activity"
+	SignalPhotos     = "shared_photos"
+)
+
+// Décisions d'un modérateur sur un compte mis de côté par le détecteur
+const (
+	RiskDecisionClear   = "clear"   // Faux positif : le profil redevient visible
+	RiskDecisionConfirm = "confirm" // Risque avéré : le profil reste masqué
+)
+
+// Actions du détecteur et des modérateurs enregistrées dans le journal de modération
+const (
+	ActionRiskAutoHide = "risk.auto_hide"
+	ActionRiskReview   = "risk.review"
+)
+
+var (
+	// ErrRiskNotFound est retournée pour un compte sans score de risque
+	ErrRiskNotFound = errors.New("aucun score de risque pour cet utilisateur")
+	// ErrInvalidRiskDecision est retournée pour une décision d'examen inconnue
+	ErrInvalidRiskDecision = errors.New("décision d'examen invalide")
+)
+
+// Poids (points) de chaque signal lorsqu'il atteint son seuil ; un signal
+// compte jusqu'au double de son poids, le score total est plafonné à 100
+var riskWeights = map[string]float64{
+	SignalReporters:  40,
+	SignalLikeSpray:  20,
+	SignalDuplicates: 25,
+	SignalNewAccount: 15,
+	SignalPhotos:     35,
+}
+
+// RiskEvent est un fait daté attribué à un utilisateur
+type RiskEvent struct {
+	Kind    string
+	UserID  int
+	ActorID int
+	Key     string
+	At      time.Time
+}
+
+// RiskSignals agrège les événements d'un utilisateur sur la fenêtre d'analyse
+type RiskSignals struct {
+	UserID            int `json:"user_id"`
+	DistinctReporters int `json:"distinct_reporters"`
+	PeakLikesPerHour  int `json:"peak_likes_per_hour"`
+	DuplicateMessages int `json:"duplicate_messages"`    // Destinataires distincts du message le plus répété
+	NewAccountActions int `json:"new_account_actions"`   // Likes et messages depuis l'inscription, si elle est récente
+	SharedPhotoUsers  int `json:"shared_photo_accounts"` // Autres comptes possédant une photo identique
+}
+
+// RiskFactor explique la contribution d'un signal au score
+type RiskFactor struct {
+	Signal    string  `json:"signal"`
+	Value     int     `json:"value"`
+	Threshold int     `json:"threshold"`
+	Points    float64 `json:"points"`
+}
+
+// RiskAssessment est le score d'un utilisateur et son explication
+type RiskAssessment struct {
+	UserID     int          `json:"user_id"`
+	User       *UserSummary `json:"user,omitempty"`
+	Score      float64      `json:"score"`
+	Factors    []RiskFactor `json:"factors"`
+	Signals    RiskSignals  `json:"signals"`
+	Hidden     bool         `json:"hidden"`
+	ComputedAt time.Time    `json:"computed_at"`
+	ReviewedAt *time.Time   `json:"reviewed_at,omitempty"`
+	ReviewedBy *int         `json:"reviewed_by,omitempty"`
+}
+
+// RiskFilter restreint la file des comptes à risque
+type RiskFilter struct {
+	Pending bool // Profils masqués non encore examinés
+	Limit   int
+	Offset  int
+}
+
+// RiskRepository interface pour les événements analysés et les scores de risque
+type RiskRepository interface {
+	// LoadRiskEvents charge les signalements, likes et messages depuis since, les
+	// inscriptions depuis signupSince et les photos partagées entre plusieurs comptes
+	LoadRiskEvents(since, signupSince time.Time) ([]RiskEvent, error)
+	// SaveAssessments remplace les scores, masque les profils atteignant hideScore
+	// et retourne les utilisateurs nouvellement masqués
+	SaveAssessments(assessments []RiskAssessment, hideScore float64) ([]int, error)
+	ListRisk(filter RiskFilter) ([]*RiskAssessment, error)
+	GetRisk(userID int) (*RiskAssessment, error)
+	// ReviewRisk enregistre l'examen d'un modérateur ; clear rend le profil visible
+	ReviewRisk(userID, moderatorID int, clear bool, entry *LogEntry) (*RiskAssessment, error)
+}
+
+// CollectRiskSignals agrège un flux d'événements par utilisateur. Les
+// inscriptions servent de référence aux actions des comptes récents.
+func CollectRiskSignals(events []RiskEvent, cfg config.RiskConfig) map[int]*RiskSignals {
+	signals := make(map[int]*RiskSignals)
+	get := func(userID int) *RiskSignals {
+		s, ok := signals[userID]
+		if !ok {
+			s = &RiskSignals{UserID: userID}
+			signals[userID] = s
+		}
+		return s
+	}
+
+	reporters := make(map[int]map[int]bool)
+	likes := make(map[int][]time.Time)
+	recipients := make(map[int]map[string]map[int]bool)
+	photoOwners := make(map[string]map[int]bool)
+	signups := make(map[int]time.Time)
+	var actions []RiskEvent
+
+	for _, event := range events {
+		switch event.Kind {
+		case EventReport:
+			if reporters[event.UserID] == nil {
+				reporters[event.UserID] = make(map[int]bool)
+			}
+			reporters[event.UserID][event.ActorID] = true
+		case EventLike:
+			likes[event.UserID] = append(likes[event.UserID], event.At)
+			actions = append(actions, event)
+		case EventMessage:
+			if recipients[event.UserID] == nil {
+				recipients[event.UserID] = make(map[string]map[int]bool)
+			}
+			if recipients[event.UserID][event.Key] == nil {
+				recipients[event.UserID][event.Key] = make(map[int]bool)
+			}
+			recipients[event.UserID][event.Key][event.ActorID] = true
+			actions = append(actions, event)
+		case EventPhoto:
+			if photoOwners[event.Key] == nil {
+				photoOwners[event.Key] = make(map[int]bool)
+			}
+			photoOwners[event.Key][event.UserID] = true
+		case EventSignup:
+			signups[event.UserID] = event.At
+		}
+	}
+
+	for userID, set := range reporters {
+		get(userID).DistinctReporters = len(set)
+	}
+
+	for userID, times := range likes {
+		get(userID).PeakLikesPerHour = peakPerWindow(times, time.Hour)
+	}
+
+	for userID, byContent := range recipients {
+		peak := 0
+		for _, set := range byContent {
+			if len(set) > peak {
+				peak = len(set)
+			}
+		}
+		get(userID).DuplicateMessages = peak
+	}
+
+	for _, owners := range photoOwners {
+		if len(owners) < 2 {
+			continue
+		}
+		for userID := range owners {
+			if others := len(owners) - 1; others > get(userID).SharedPhotoUsers {
+				get(userID).SharedPhotoUsers = others
+			}
+		}
+	}
+
+	for _, action := range actions {
+		signup, ok := signups[action.UserID]
+		if ok && action.At.Sub(signup) <= cfg.NewAccountAge {
+			get(action.UserID).NewAccountActions++
+		}
+	}
+
+	return signals
+}
+
+// peakPerWindow retourne le plus grand nombre d'instants contenus dans une fenêtre glissante
+func peakPerWindow(times []time.Time, window time.Duration) int {
+	sorted := append([]time.Time(nil), times...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Before(sorted[j]) })
+
+	peak, start := 0, 0
+	for end := range sorted {
+		for sorted[end].Sub(sorted[start]) >= window {
+			start++
+		}
+		if count := end - start + 1; count > peak {
+			peak = count
+		}
+	}
+	return peak
+}
+
+// AssessRisk calcule le score de risque (0 à 100) des signaux d'un utilisateur.
+// Chaque signal ayant atteint son seuil rapporte son poids, proportionnellement
+// au dépassement et jusqu'au double ; les facteurs sont triés par contribution.
+func AssessRisk(signals RiskSignals, cfg config.RiskConfig) RiskAssessment {
+	assessment := RiskAssessment{
+		UserID:  signals.UserID,
+		Signals: signals,
+		Factors: []RiskFactor{},
+	}
+
+	values := []struct {
+		signal    string
+		value     int
+		threshold int
+	}{
+		{SignalReporters, signals.DistinctReporters, cfg.Reporters},
+		{SignalLikeSpray, signals.PeakLikesPerHour, cfg.LikesPerHour},
+		{SignalDuplicates, signals.DuplicateMessages, cfg.DuplicateMessages},
+		{SignalNewAccount, signals.NewAccountActions, cfg.NewAccountActions},
+		{SignalPhotos, signals.SharedPhotoUsers, cfg.SharedPhotoAccounts},
+	}
+
+	for _, v := range values {
+		if v.threshold <= 0 || v.value < v.threshold {
+			continue
+		}
+		ratio := math.Min(float64(v.value)/float64(v.threshold), 2)
+		points := math.Round(riskWeights[v.signal]*ratio*10) / 10
+
+		assessment.Factors = append(assessment.Factors, RiskFactor{
+			Signal:    v.signal,
+			Value:     v.value,
+			Threshold: v.threshold,
+			Points:    points,
+		})
+		assessment.Score += points
+	}
+
+	assessment.Score = math.Min(assessment.Score, 100)
+	sort.SliceStable(assessment.Factors, func(i, j int) bool {
+		return assessment.Factors[i].Points > assessment.Factors[j].Points
+	})
+
+	return assessment
+}
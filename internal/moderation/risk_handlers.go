@@ -0,0 +1,122 @@
+package moderation
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cduffaut/matcha/internal/audit"
+	"github.com/cduffaut/matcha/internal/validation"
+	"goji.io/pat"
+)
+
+// RiskHandlers gère les requêtes HTTP de la file des comptes à risque
+type RiskHandlers struct {
+	service *RiskService
+	audit   *audit.Service
+}
+
+// NewRiskHandlers crée de nouveaux handlers pour les comptes à risque
+func NewRiskHandlers(service *RiskService, auditService *audit.Service) *RiskHandlers {
+	return &RiskHandlers{
+		service: service,
+		audit:   auditService,
+	}
+}
+
+// riskUserID lit l'identifiant d'utilisateur de l'URL
+func riskUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(pat.Param(r, "userID"))
+	if err != nil || id <= 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "ID utilisateur invalide"})
+		return 0, false
+	}
+	return id, true
+}
+
+// ListRiskHandler liste les comptes à risque (filtres : pending=true pour les
+// profils masqués en attente d'examen, limit, offset)
+func (h *RiskHandlers) ListRiskHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := actorFromRequest(w, r); !ok {
+		return
+	}
+
+	accounts, err := h.service.ListQueue(RiskFilter{
+		Pending: r.URL.Query().Get("pending") == "true",
+		Limit:   queryInt(r, "limit"),
+		Offset:  queryInt(r, "offset"),
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"accounts":   accounts,
+		"hide_score": h.service.HideScore(),
+	})
+}
+
+// GetRiskHandler explique le score de risque d'un utilisateur
+func (h *RiskHandlers) GetRiskHandler(w http.ResponseWriter, r *http.Request) {
+	if _, ok := actorFromRequest(w, r); !ok {
+		return
+	}
+	userID, ok := riskUserID(w, r)
+	if !ok {
+		return
+	}
+
+	assessment, err := h.service.Explain(userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"risk":       assessment,
+		"hide_score": h.service.HideScore(),
+	})
+}
+
+// ReviewRiskHandler enregistre l'examen d'un compte à risque (decision : clear ou confirm)
+func (h *RiskHandlers) ReviewRiskHandler(w http.ResponseWriter, r *http.Request) {
+	actor, ok := actorFromRequest(w, r)
+	if !ok {
+		return
+	}
+	userID, ok := riskUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Decision string `json:"decision"`
+		Comment  string `json:"comment"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<14)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "Format de données invalide"})
+		return
+	}
+
+	comment := validation.SanitizeInput(strings.TrimSpace(req.Comment))
+	assessment, err := h.service.Review(actor, userID, req.Decision, comment)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    actor.UserID,
+		Action:     ActionRiskReview,
+		TargetType: TargetUser,
+		TargetID:   userID,
+		Details:    map[string]interface{}{"decision": req.Decision, "comment": comment},
+	})
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message": "Examen enregistré",
+		"risk":    assessment,
+	})
+}
@@ -0,0 +1,258 @@
+package moderation
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/database"
+	"github.com/lib/pq"
+)
+
+// PostgresRiskRepository implémentation PostgreSQL du repository des scores de risque
+type PostgresRiskRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRiskRepository crée un nouveau repository des scores de risque
+func NewPostgresRiskRepository(db *sql.DB) RiskRepository {
+	return &PostgresRiskRepository{db: db}
+}
+
+// LoadRiskEvents charge le flux d'événements analysé par le détecteur. Le contenu
+// des messages est réduit à une empreinte, seule utile à la détection des doublons.
+func (r *PostgresRiskRepository) LoadRiskEvents(since, signupSince time.Time) ([]RiskEvent, error) {
+	query := `
+		SELECT 'report', reported_id, reporter_id, ''::text, created_at
+		FROM user_reports WHERE created_at >= $1
+		UNION ALL
+		SELECT 'like', liker_id, liked_id, '', created_at
+		FROM user_likes WHERE created_at >= $1 AND liker_id IS NOT NULL AND liked_id IS NOT NULL
+		UNION ALL
+		SELECT 'message', sender_id, recipient_id, md5(lower(btrim(content))), created_at
+		FROM messages WHERE created_at >= $1
+		UNION ALL
+		SELECT 'signup', id, 0, '', created_at
+		FROM users WHERE created_at >= $2
+		UNION ALL
+		SELECT 'photo', user_id, 0, content_hash, COALESCE(created_at, CURRENT_TIMESTAMP)
+		FROM user_photos
+		WHERE content_hash IN (
+			SELECT content_hash FROM user_photos
+			WHERE content_hash IS NOT NULL
+			GROUP BY content_hash
+			HAVING COUNT(DISTINCT user_id) > 1
+		)
+	`
+
+	rows, err := r.db.Query(query, since, signupSince)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du chargement des événements de risque: %w", err)
+	}
+	defer rows.Close()
+
+	var events []RiskEvent
+	for rows.Next() {
+		var event RiskEvent
+		if err := rows.Scan(&event.Kind, &event.UserID, &event.ActorID, &event.Key, &event.At); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un événement de risque: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// SaveAssessments enregistre les scores du dernier calcul. Les comptes sans
+// signal retombent à 0 mais restent masqués tant qu'un modérateur ne les a pas
+// examinés ; chaque masquage automatique est journalisé.
+func (r *PostgresRiskRepository) SaveAssessments(assessments []RiskAssessment, hideScore float64) ([]int, error) {
+	var newlyHidden []int
+
+	err := database.WithTx(r.db, func(tx *sql.Tx) error {
+		ids := make([]int64, 0, len(assessments))
+		for _, assessment := range assessments {
+			ids = append(ids, int64(assessment.UserID))
+		}
+
+		reset := `
+			UPDATE user_risk
+			SET score = 0, factors = '[]', signals = '{}', computed_at = CURRENT_TIMESTAMP
+			WHERE NOT (user_id = ANY($1)) AND score <> 0
+		`
+		if _, err := tx.Exec(reset, pq.Array(ids)); err != nil {
+			return fmt.Errorf("erreur lors de la remise à zéro des scores de risque: %w", err)
+		}
+
+		// Le CTE lit l'état antérieur à l'upsert, pour ne journaliser que les nouveaux masquages
+		upsert, err := tx.Prepare(`
+			WITH previous AS (SELECT hidden FROM user_risk WHERE user_id = $1)
+			INSERT INTO user_risk (user_id, score, factors, signals, hidden, computed_at)
+			VALUES ($1, $2, $3, $4, $2 >= $5, CURRENT_TIMESTAMP)
+			ON CONFLICT (user_id) DO UPDATE
+			SET score = EXCLUDED.score, factors = EXCLUDED.factors, signals = EXCLUDED.signals,
+			    computed_at = EXCLUDED.computed_at,
+			    hidden = user_risk.hidden OR (
+			        EXCLUDED.score >= $5
+			        AND (user_risk.reviewed_at IS NULL OR EXCLUDED.score > user_risk.reviewed_score)
+			    )
+			RETURNING hidden AND NOT COALESCE((SELECT hidden FROM previous), FALSE)
+		`)
+		if err != nil {
+			return fmt.Errorf("erreur lors de la préparation de l'enregistrement des scores: %w", err)
+		}
+		defer upsert.Close()
+
+		for _, assessment := range assessments {
+			factors, err := json.Marshal(assessment.Factors)
+			if err != nil {
+				return fmt.Errorf("erreur lors de l'encodage des facteurs de risque: %w", err)
+			}
+			signals, err := json.Marshal(assessment.Signals)
+			if err != nil {
+				return fmt.Errorf("erreur lors de l'encodage des signaux de risque: %w", err)
+			}
+
+			var hidden bool
+			err = upsert.QueryRow(assessment.UserID, assessment.Score, factors, signals, hideScore).Scan(&hidden)
+			if err != nil {
+				return fmt.Errorf("erreur lors de l'enregistrement du score de l'utilisateur %d: %w", assessment.UserID, err)
+			}
+			if !hidden {
+				continue
+			}
+
+			newlyHidden = append(newlyHidden, assessment.UserID)
+			entry := &LogEntry{
+				Action:     ActionRiskAutoHide,
+				TargetType: TargetUser,
+				TargetID:   assessment.UserID,
+				Details: map[string]interface{}{
+					"score":   assessment.Score,
+					"factors": assessment.Factors,
+				},
+			}
+			if err := insertLogEntry(tx, entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return newlyHidden, err
+}
+
+const riskSelect = `
+	SELECT rk.user_id, rk.score, rk.factors, rk.signals, rk.hidden, rk.computed_at,
+	       rk.reviewed_at, rk.reviewed_by, u.username, u.first_name, u.last_name
+	FROM user_risk rk
+	JOIN users u ON u.id = rk.user_id
+`
+
+func scanRisk(scanner interface{ Scan(...interface{}) error }) (*RiskAssessment, error) {
+	assessment := &RiskAssessment{User: &UserSummary{}}
+	var factors, signals []byte
+	var reviewedAt sql.NullTime
+	var reviewedBy sql.NullInt64
+
+	err := scanner.Scan(
+		&assessment.UserID, &assessment.Score, &factors, &signals, &assessment.Hidden, &assessment.ComputedAt,
+		&reviewedAt, &reviewedBy, &assessment.User.Username, &assessment.User.FirstName, &assessment.User.LastName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	assessment.User.ID = assessment.UserID
+
+	if err := json.Unmarshal(factors, &assessment.Factors); err != nil {
+		return nil, fmt.Errorf("facteurs illisibles pour l'utilisateur %d: %w", assessment.UserID, err)
+	}
+	if err := json.Unmarshal(signals, &assessment.Signals); err != nil {
+		return nil, fmt.Errorf("signaux illisibles pour l'utilisateur %d: %w", assessment.UserID, err)
+	}
+	assessment.Signals.UserID = assessment.UserID
+
+	if reviewedAt.Valid {
+		assessment.ReviewedAt = &reviewedAt.Time
+	}
+	if reviewedBy.Valid {
+		id := int(reviewedBy.Int64)
+		assessment.ReviewedBy = &id
+	}
+
+	return assessment, nil
+}
+
+// ListRisk récupère les comptes à risque, les scores les plus élevés d'abord
+func (r *PostgresRiskRepository) ListRisk(filter RiskFilter) ([]*RiskAssessment, error) {
+	query := riskSelect + " WHERE rk.score > 0 OR rk.hidden"
+	if filter.Pending {
+		query = riskSelect + " WHERE rk.hidden AND rk.reviewed_at IS NULL"
+	}
+	query += " ORDER BY rk.score DESC, rk.user_id LIMIT $1 OFFSET $2"
+
+	rows, err := r.db.Query(query, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des comptes à risque: %w", err)
+	}
+	defer rows.Close()
+
+	assessments := []*RiskAssessment{}
+	for rows.Next() {
+		assessment, err := scanRisk(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un compte à risque: %w", err)
+		}
+		assessments = append(assessments, assessment)
+	}
+
+	return assessments, rows.Err()
+}
+
+// GetRisk récupère le score de risque d'un utilisateur
+func (r *PostgresRiskRepository) GetRisk(userID int) (*RiskAssessment, error) {
+	assessment, err := scanRisk(r.db.QueryRow(riskSelect+" WHERE rk.user_id = $1", userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrRiskNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération du score de risque: %w", err)
+	}
+	return assessment, nil
+}
+
+// ReviewRisk enregistre l'examen d'un compte à risque et le journalise
+func (r *PostgresRiskRepository) ReviewRisk(userID, moderatorID int, clear bool, entry *LogEntry) (*RiskAssessment, error) {
+	err := database.WithTx(r.db, func(tx *sql.Tx) error {
+		query := `
+			UPDATE user_risk
+			SET reviewed_at = CURRENT_TIMESTAMP, reviewed_by = $2, reviewed_score = score,
+			    hidden = hidden AND NOT $3
+			WHERE user_id = $1
+			RETURNING score
+		`
+
+		var score float64
+		err := tx.QueryRow(query, userID, moderatorID, clear).Scan(&score)
+		if err == sql.ErrNoRows {
+			return ErrRiskNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("erreur lors de l'enregistrement de l'examen: %w", err)
+		}
+
+		if entry.Details == nil {
+			entry.Details = map[string]interface{}{}
+		}
+		entry.Details["score"] = score
+
+		return insertLogEntry(tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetRisk(userID)
+}
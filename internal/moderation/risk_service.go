@@ -0,0 +1,122 @@
+package moderation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cduffaut/matcha/internal/config"
+)
+
+// RiskService calcule périodiquement les scores de risque, masque les profils
+// suspects des suggestions et les soumet à l'examen des modérateurs
+type RiskService struct {
+	repo RiskRepository
+	cfg  config.RiskConfig
+}
+
+// NewRiskService crée un nouveau service de détection des comptes à risque
+func NewRiskService(repo RiskRepository, cfg config.RiskConfig) *RiskService {
+	return &RiskService{
+		repo: repo,
+		cfg:  cfg,
+	}
+}
+
+// Start lance le recalcul périodique en arrière-plan
+func (s *RiskService) Start() {
+	go s.run()
+}
+
+func (s *RiskService) run() {
+	ticker := time.NewTicker(s.cfg.RefreshEvery)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Recompute(); err != nil {
+			fmt.Printf("Erreur recalcul des scores de risque: %v\n", err)
+		}
+		<-ticker.C
+	}
+}
+
+// Recompute évalue les événements de la fenêtre et enregistre les scores
+func (s *RiskService) Recompute() error {
+	now := time.Now()
+	events, err := s.repo.LoadRiskEvents(now.Add(-s.cfg.Window), now.Add(-s.cfg.NewAccountAge))
+	if err != nil {
+		return err
+	}
+
+	hidden, err := s.repo.SaveAssessments(s.Assess(events), s.cfg.HideScore)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range hidden {
+		fmt.Printf("Profil %d masqué des suggestions en attente d'examen (score de risque)\n", userID)
+	}
+	return nil
+}
+
+// Assess calcule le score des utilisateurs présentant au moins un signal au-delà
+// de son seuil, par identifiant croissant ; sans accès à la base, il peut être
+// éprouvé sur un flux d'événements synthétique
+func (s *RiskService) Assess(events []RiskEvent) []RiskAssessment {
+	var assessments []RiskAssessment
+	for _, signals := range CollectRiskSignals(events, s.cfg) {
+		assessment := AssessRisk(*signals, s.cfg)
+		if assessment.Score > 0 {
+			assessments = append(assessments, assessment)
+		}
+	}
+
+	sort.Slice(assessments, func(i, j int) bool {
+		return assessments[i].UserID < assessments[j].UserID
+	})
+	return assessments
+}
+
+// HideScore retourne le score à partir duquel un profil est masqué
+func (s *RiskService) HideScore() float64 {
+	return s.cfg.HideScore
+}
+
+// ListQueue récupère les comptes à risque ; pending restreint aux profils masqués non examinés
+func (s *RiskService) ListQueue(filter RiskFilter) ([]*RiskAssessment, error) {
+	filter.Limit = pageSize(filter.Limit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListRisk(filter)
+}
+
+// Explain récupère le score d'un utilisateur et les facteurs qui le composent
+func (s *RiskService) Explain(userID int) (*RiskAssessment, error) {
+	return s.repo.GetRisk(userID)
+}
+
+// Review enregistre la décision d'un modérateur sur un compte à risque. Un
+// compte confirmé reste masqué ; les sanctions passent par ApplySanction.
+func (s *RiskService) Review(actor Actor, userID int, decision, comment string) (*RiskAssessment, error) {
+	if decision != RiskDecisionClear && decision != RiskDecisionConfirm {
+		return nil, ErrInvalidRiskDecision
+	}
+	if utf8.RuneCountInString(comment) > MaxCommentLength {
+		return nil, ErrCommentTooLong
+	}
+
+	entry := &LogEntry{
+		ModeratorID: actor.UserID,
+		Action:      ActionRiskReview,
+		TargetType:  TargetUser,
+		TargetID:    userID,
+		Details: map[string]interface{}{
+			"decision": decision,
+			"comment":  comment,
+		},
+	}
+
+	return s.repo.ReviewRisk(userID, actor.UserID, decision == RiskDecisionClear, entry)
+}
@@ -0,0 +1,210 @@
+package moderation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/config"
+)
+
+// testRiskConfig utilise des seuils bas pour garder les flux synthétiques courts
+var testRiskConfig = config.RiskConfig{
+	HideScore:           60,
+	Reporters:           3,
+	LikesPerHour:        5,
+	DuplicateMessages:   3,
+	NewAccountAge:       48 * time.Hour,
+	NewAccountActions:   4,
+	SharedPhotoAccounts: 1,
+}
+
+var riskEpoch = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// reportsOf retourne n signalements de userID par des signaleurs distincts
+func reportsOf(userID, n int) []RiskEvent {
+	var events []RiskEvent
+	for i := 0; i < n; i++ {
+		events = append(events, RiskEvent{Kind: EventReport, UserID: userID, ActorID: 1000 + i, At: riskEpoch})
+	}
+	return events
+}
+
+// likesBy retourne n likes de userID espacés de step
+func likesBy(userID, n int, step time.Duration) []RiskEvent {
+	var events []RiskEvent
+	for i := 0; i < n; i++ {
+		events = append(events, RiskEvent{Kind: EventLike, UserID: userID, ActorID: 2000 + i, At: riskEpoch.Add(time.Duration(i) * step)})
+	}
+	return events
+}
+
+// sameMessageBy retourne un même message de userID envoyé à n destinataires
+func sameMessageBy(userID, n int) []RiskEvent {
+	var events []RiskEvent
+	for i := 0; i < n; i++ {
+		events = append(events, RiskEvent{Kind: EventMessage, UserID: userID, ActorID: 3000 + i, Key: "spam", At: riskEpoch})
+	}
+	return events
+}
+
+// distinctMessagesBy retourne n messages différents de userID, envoyés at
+func distinctMessagesBy(userID, n int, at time.Time) []RiskEvent {
+	var events []RiskEvent
+	for i := 0; i < n; i++ {
+		events = append(events, RiskEvent{Kind: EventMessage, UserID: userID, ActorID: 4000 + i, Key: fmt.Sprintf("m%d", i), At: at})
+	}
+	return events
+}
+
+// sharedPhoto retourne une même photo possédée par userID et others autres comptes
+func sharedPhoto(userID, others int) []RiskEvent {
+	events := []RiskEvent{{Kind: EventPhoto, UserID: userID, Key: "photo", At: riskEpoch}}
+	for i := 0; i < others; i++ {
+		events = append(events, RiskEvent{Kind: EventPhoto, UserID: 5000 + i, Key: "photo", At: riskEpoch})
+	}
+	return events
+}
+
+// signedUp retourne l'inscription de userID à riskEpoch, suivie de n messages at
+func signedUp(userID, n int, at time.Time) []RiskEvent {
+	events := []RiskEvent{{Kind: EventSignup, UserID: userID, At: riskEpoch}}
+	return append(events, distinctMessagesBy(userID, n, at)...)
+}
+
+func TestAssessSignalThresholds(t *testing.T) {
+	const userID = 42
+
+	tests := []struct {
+		name   string
+		events []RiskEvent
+		signal string
+		points float64 // 0 : aucun score attendu
+	}{
+		{"signaleurs sous le seuil", reportsOf(userID, 2), SignalReporters, 0},
+		{"signaleurs au seuil", reportsOf(userID, 3), SignalReporters, 40},
+		{"signaleurs au-dessus du seuil", reportsOf(userID, 4), SignalReporters, 53.3},
+		{"signaleurs au double du seuil", reportsOf(userID, 6), SignalReporters, 80},
+		{"signaleurs plafonnés au double", reportsOf(userID, 9), SignalReporters, 80},
+
+		{"likes sous le seuil", likesBy(userID, 4, time.Minute), SignalLikeSpray, 0},
+		{"likes au seuil", likesBy(userID, 5, time.Minute), SignalLikeSpray, 20},
+		{"likes au-dessus du seuil", likesBy(userID, 7, time.Minute), SignalLikeSpray, 28},
+		{"likes plafonnés au double", likesBy(userID, 20, time.Minute), SignalLikeSpray, 40},
+		{"likes étalés hors de la fenêtre", likesBy(userID, 20, 15*time.Minute), SignalLikeSpray, 0},
+
+		{"message répété sous le seuil", sameMessageBy(userID, 2), SignalDuplicates, 0},
+		{"message répété au seuil", sameMessageBy(userID, 3), SignalDuplicates, 25},
+		{"message répété au-dessus du seuil", sameMessageBy(userID, 4), SignalDuplicates, 33.3},
+		{"message répété plafonné au double", sameMessageBy(userID, 9), SignalDuplicates, 50},
+
+		{"compte récent sous le seuil", signedUp(userID, 3, riskEpoch.Add(time.Hour)), SignalNewAccount, 0},
+		{"compte récent au seuil", signedUp(userID, 4, riskEpoch.Add(time.Hour)), SignalNewAccount, 15},
+		{"compte récent au-dessus du seuil", signedUp(userID, 6, riskEpoch.Add(time.Hour)), SignalNewAccount, 22.5},
+		{"compte récent plafonné au double", signedUp(userID, 12, riskEpoch.Add(time.Hour)), SignalNewAccount, 30},
+		{"actions à la limite de l'âge récent", signedUp(userID, 4, riskEpoch.Add(48*time.Hour)), SignalNewAccount, 15},
+		{"actions après l'âge récent", signedUp(userID, 4, riskEpoch.Add(48*time.Hour+time.Second)), SignalNewAccount, 0},
+
+		{"photo non partagée", sharedPhoto(userID, 0), SignalPhotos, 0},
+		{"photo partagée au seuil", sharedPhoto(userID, 1), SignalPhotos, 35},
+		{"photo partagée au double", sharedPhoto(userID, 2), SignalPhotos, 70},
+		{"photo partagée plafonnée au double", sharedPhoto(userID, 5), SignalPhotos, 70},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRiskService(nil, testRiskConfig)
+			var assessment *RiskAssessment
+			for _, a := range service.Assess(tt.events) {
+				if a.UserID == userID {
+					a := a
+					assessment = &a
+				}
+			}
+
+			if tt.points == 0 {
+				if assessment != nil {
+					t.Fatalf("aucun score attendu, obtenu %v (%+v)", assessment.Score, assessment.Factors)
+				}
+				return
+			}
+			if assessment == nil {
+				t.Fatalf("score attendu pour le signal %s, aucun obtenu", tt.signal)
+			}
+			if len(assessment.Factors) != 1 || assessment.Factors[0].Signal != tt.signal {
+				t.Fatalf("facteurs = %+v, attendu le seul signal %s", assessment.Factors, tt.signal)
+			}
+			if assessment.Factors[0].Points != tt.points || assessment.Score != tt.points {
+				t.Errorf("points = %v, score = %v, attendu %v", assessment.Factors[0].Points, assessment.Score, tt.points)
+			}
+		})
+	}
+}
+
+func TestAssessScoreCappedAt100(t *testing.T) {
+	const userID = 7
+	events := append(reportsOf(userID, 6), sharedPhoto(userID, 2)...)
+
+	assessments := NewRiskService(nil, testRiskConfig).Assess(events)
+	var assessment *RiskAssessment
+	for i := range assessments {
+		if assessments[i].UserID == userID {
+			assessment = &assessments[i]
+		}
+	}
+	if assessment == nil {
+		t.Fatal("score attendu, aucun obtenu")
+	}
+
+	if assessment.Score != 100 {
+		t.Errorf("score = %v, attendu 100 (80 + 70 plafonné)", assessment.Score)
+	}
+	if len(assessment.Factors) != 2 || assessment.Factors[0].Signal != SignalReporters || assessment.Factors[1].Signal != SignalPhotos {
+		t.Errorf("facteurs = %+v, attendus par contribution décroissante", assessment.Factors)
+	}
+}
+
+func TestAssessOrdersUsersAndSkipsZeroScores(t *testing.T) {
+	var events []RiskEvent
+	events = append(events, reportsOf(30, 3)...)
+	events = append(events, reportsOf(10, 3)...)
+	events = append(events, reportsOf(20, 1)...)
+
+	assessments := NewRiskService(nil, testRiskConfig).Assess(events)
+	if len(assessments) != 2 || assessments[0].UserID != 10 || assessments[1].UserID != 30 {
+		t.Fatalf("assessments = %+v, attendus les utilisateurs 10 puis 30", assessments)
+	}
+}
+
+func TestPeakPerWindow(t *testing.T) {
+	at := func(offsets ...time.Duration) []time.Time {
+		var times []time.Time
+		for _, offset := range offsets {
+			times = append(times, riskEpoch.Add(offset))
+		}
+		return times
+	}
+
+	tests := []struct {
+		name  string
+		times []time.Time
+		want  int
+	}{
+		{"aucun instant", nil, 0},
+		{"un seul instant", at(0), 1},
+		{"juste sous la fenêtre", at(0, time.Hour-time.Second), 2},
+		{"exactement une fenêtre d'écart", at(0, time.Hour), 1},
+		{"bornes exclues de part et d'autre", at(0, 30*time.Minute, time.Hour), 2},
+		{"fenêtre glissante au milieu", at(0, 30*time.Minute, 59*time.Minute, 89*time.Minute), 3},
+		{"instants non triés", at(89*time.Minute, 0, 59*time.Minute, 30*time.Minute), 3},
+		{"instants identiques", at(0, 0, 0), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := peakPerWindow(tt.times, time.Hour); got != tt.want {
+				t.Errorf("peakPerWindow = %d, attendu %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+package moderation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cduffaut/matcha/internal/models"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// Sessions répercute les décisions de modération sur les sessions ouvertes
+type Sessions interface {
+	UpdateRole(userID int, role string)
+	RevokeUser(userID int)
+}
+
+// Mailer prévient un utilisateur d'une sanction sur son compte
+type Mailer interface {
+	SendModerationNotice(to, username, status, reason string, until *time.Time, appealLink string) error
+}
+
+// Notifier prévient le signaleur de la clôture de son signalement
+type Notifier interface {
+	NotifyReportResolved(reporterID, reportedID int, upheld bool) error
+}
+
+// Service gère la file des signalements, les sanctions et les rôles, en
+// journalisant chaque action
+type Service struct {
+	repo     Repository
+	sessions Sessions
+	mailer   Mailer
+	notifier Notifier
+	baseURL  string
+}
+
+// NewService crée un nouveau service de modération
+func NewService(repo Repository, sessions Sessions, mailer Mailer, notifier Notifier, baseURL string) *Service {
+	return &Service{
+		repo:     repo,
+		sessions: sessions,
+		mailer:   mailer,
+		notifier: notifier,
+		baseURL:  baseURL,
+	}
+}
+
+// pageSize ramène une taille de page demandée dans les bornes autorisées
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return defaultPageSize
+	}
+	if limit > maxPageSize {
+		return maxPageSize
+	}
+	return limit
+}
+
+// ListReports récupère les signalements selon le filtre
+func (s *Service) ListReports(filter ReportFilter) ([]*Report, error) {
+	switch filter.Status {
+	case "", StatusOpen, StatusClaimed, StatusResolved:
+	default:
+		return nil, ErrInvalidStatus
+	}
+	if filter.Category != "" && !models.IsValidReportCategory(filter.Category) {
+		return nil, ErrInvalidCategory
+	}
+
+	filter.Limit = pageSize(filter.Limit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return s.repo.ListReports(filter)
+}
+
+// GetReport récupère un signalement avec le contenu de ses pièces jointes
+func (s *Service) GetReport(reportID int) (*Report, error) {
+	return s.repo.GetReport(reportID, true)
+}
+
+// ClaimReport prend un signalement en charge ; un administrateur peut reprendre
+// un signalement déjà attribué à un autre modérateur
+func (s *Service) ClaimReport(actor Actor, reportID int) (*Report, error) {
+	entry := &LogEntry{
+		ModeratorID: actor.UserID,
+		Action:      ActionReportClaim,
+		TargetType:  TargetReport,
+		TargetID:    reportID,
+	}
+
+	applied, err := s.repo.ClaimReport(reportID, actor.UserID, isAdmin(actor), entry)
+	return s.afterTransition(actor, reportID, applied, err)
+}
+
+// ReleaseReport rend un signalement pris en charge aux autres modérateurs
+func (s *Service) ReleaseReport(actor Actor, reportID int) (*Report, error) {
+	entry := &LogEntry{
+		ModeratorID: actor.UserID,
+		Action:      ActionReportRelease,
+		TargetType:  TargetReport,
+		TargetID:    reportID,
+	}
+
+	applied, err := s.repo.ReleaseReport(reportID, actor.UserID, isAdmin(actor), entry)
+	return s.afterTransition(actor, reportID, applied, err)
+}
+
+// ResolveReport clôt un signalement pris en charge avec une issue et un
+// commentaire ; une sanction (facultative) vise alors l'utilisateur signalé
+func (s *Service) ResolveReport(actor Actor, reportID int, resolution, comment string, sanction *Sanction) (*Report, error) {
+	if resolution != ResolutionDismissed && resolution != ResolutionUpheld {
+		return nil, ErrInvalidResolution
+	}
+	if utf8.RuneCountInString(comment) > MaxCommentLength {
+		return nil, ErrCommentTooLong
+	}
+
+	var update *AccountUpdate
+	if sanction != nil {
+		if resolution != ResolutionUpheld {
+			return nil, ErrSanctionRequiresUpheld
+		}
+
+		report, err := s.repo.GetReport(reportID, false)
+		if err != nil {
+			return nil, err
+		}
+
+		update, err = s.accountUpdate(actor, report.Reported.ID, *sanction)
+		if err != nil {
+			return nil, err
+		}
+		update.Entry.Details["report_id"] = reportID
+	}
+
+	entry := &LogEntry{
+		ModeratorID: actor.UserID,
+		Action:      ActionReportResolve,
+		TargetType:  TargetReport,
+		TargetID:    reportID,
+		Details: map[string]interface{}{
+			"resolution": resolution,
+			"comment":    comment,
+		},
+	}
+
+	applied, err := s.repo.ResolveReport(reportID, actor.UserID, isAdmin(actor), resolution, comment, entry, update)
+	report, err := s.afterTransition(actor, reportID, applied, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if update != nil {
+		s.enforce(update)
+	}
+
+	if err := s.notifier.NotifyReportResolved(report.Reporter.ID, report.Reported.ID, resolution == ResolutionUpheld); err != nil {
+		fmt.Printf("Erreur notification du signaleur pour le signalement %d: %v\n", reportID, err)
+	}
+	return report, nil
+}
+
+// ApplySanction modifie l'état du compte d'un utilisateur en dehors de tout signalement
+func (s *Service) ApplySanction(actor Actor, userID int, sanction Sanction) error {
+	update, err := s.accountUpdate(actor, userID, sanction)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateAccountStatus(update, isAdmin(actor)); err != nil {
+		return err
+	}
+
+	s.enforce(update)
+	return nil
+}
+
+// accountUpdate valide une sanction et prépare le changement d'état correspondant
+func (s *Service) accountUpdate(actor Actor, userID int, sanction Sanction) (*AccountUpdate, error) {
+	status, ok := sanctionStatuses[sanction.Action]
+	if !ok {
+		return nil, ErrInvalidSanction
+	}
+	if userID == actor.UserID {
+		return nil, ErrProtectedAccount
+	}
+
+	reason := strings.TrimSpace(sanction.Reason)
+	if sanction.Action != SanctionReinstate && reason == "" {
+		return nil, fmt.Errorf("%w : un motif est requis", ErrInvalidSanction)
+	}
+	if utf8.RuneCountInString(reason) > MaxSanctionReason {
+		return nil, fmt.Errorf("%w : le motif doit contenir au maximum %d caractères", ErrInvalidSanction, MaxSanctionReason)
+	}
+
+	var until *time.Time
+	if sanction.Action == SanctionSuspend {
+		if sanction.Duration <= 0 || sanction.Duration > MaxSuspension {
+			return nil, fmt.Errorf("%w : durée de suspension invalide", ErrInvalidSanction)
+		}
+		end := time.Now().UTC().Add(sanction.Duration)
+		until = &end
+	}
+
+	return &AccountUpdate{
+		UserID: userID,
+		Status: status,
+		Until:  until,
+		Reason: reason,
+		Entry: &LogEntry{
+			ModeratorID: actor.UserID,
+			Action:      ActionAccountStatus,
+			TargetType:  TargetUser,
+			TargetID:    userID,
+			Details: map[string]interface{}{
+				"sanction": sanction.Action,
+			},
+		},
+	}, nil
+}
+
+// enforce ferme les sessions d'un compte suspendu ou banni et prévient
+// l'utilisateur ; un shadow-ban reste volontairement silencieux
+func (s *Service) enforce(update *AccountUpdate) {
+	if update.Status == models.AccountSuspended || update.Status == models.AccountBanned {
+		s.sessions.RevokeUser(update.UserID)
+	}
+
+	if update.Status == models.AccountShadowBanned {
+		return
+	}
+
+	if err := s.mailer.SendModerationNotice(update.Email, update.Username, update.Status, update.Reason, update.Until, s.baseURL+"/appeal"); err != nil {
+		fmt.Printf("Erreur envoi de l'email de modération à l'utilisateur %d: %v\n", update.UserID, err)
+	}
+}
+
+// afterTransition retourne le signalement à jour, ou la raison pour laquelle
+// la transition n'a pas pu s'appliquer
+func (s *Service) afterTransition(actor Actor, reportID int, applied bool, err error) (*Report, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.repo.GetReport(reportID, false)
+	if err != nil {
+		return nil, err
+	}
+	if applied {
+		return report, nil
+	}
+
+	switch {
+	case report.Status == StatusResolved:
+		return nil, ErrReportResolved
+	case report.Status == StatusOpen:
+		return nil, ErrReportNotClaimed
+	case report.ClaimedBy != nil && *report.ClaimedBy != actor.UserID:
+		return nil, ErrReportClaimed
+	default:
+		return nil, fmt.Errorf("transition impossible pour le signalement %d (statut %s)", reportID, report.Status)
+	}
+}
+
+// ChangeRole modifie le rôle d'un utilisateur et l'applique à ses sessions ouvertes
+func (s *Service) ChangeRole(actor Actor, userID int, role string) error {
+	if !models.IsValidRole(role) {
+		return ErrInvalidRole
+	}
+	if userID == actor.UserID {
+		return ErrOwnRole
+	}
+
+	entry := &LogEntry{
+		ModeratorID: actor.UserID,
+		Action:      ActionRoleChange,
+		TargetType:  TargetUser,
+		TargetID:    userID,
+		Details: map[string]interface{}{
+			"role": role,
+		},
+	}
+
+	if err := s.repo.UpdateRole(userID, role, entry); err != nil {
+		return err
+	}
+
+	s.sessions.UpdateRole(userID, role)
+	return nil
+}
+
+// ListLog consulte le journal de modération
+func (s *Service) ListLog(filter LogFilter) ([]*LogEntry, error) {
+	filter.Limit = pageSize(filter.Limit)
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	return s.repo.ListLog(filter)
+}
+
+func isAdmin(actor Actor) bool {
+	return models.HasRole(actor.Role, models.RoleAdmin)
+}
@@ -0,0 +1,125 @@
+package notifications
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Broker diffuse les notifications créées vers les flux SSE ouverts
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[int]map[chan *Notification]struct{}
+}
+
+// NewBroker crée un nouveau broker de notifications
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: make(map[int]map[chan *Notification]struct{}),
+	}
+}
+
+// Subscribe abonne un flux aux notifications d'un utilisateur
+func (b *Broker) Subscribe(userID int) chan *Notification {
+	ch := make(chan *Notification, 32)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan *Notification]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+
+	return ch
+}
+
+// Unsubscribe désabonne un flux et ferme son canal
+func (b *Broker) Unsubscribe(userID int, ch chan *Notification) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs, ok := b.subscribers[userID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subscribers, userID)
+	}
+}
+
+// HasSubscribers indique si au moins un flux est ouvert pour l'utilisateur
+func (b *Broker) HasSubscribers(userID int) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers[userID]) > 0
+}
+
+// Publish envoie une notification à tous les flux de son destinataire. Un flux
+// saturé est fermé plutôt que de sauter la notification : le client se
+// reconnecte avec le dernier Last-Event-ID reçu et la rattrape par la reprise
+func (b *Broker) Publish(notification *Notification) {
+	var saturated []chan *Notification
+
+	b.mu.RLock()
+	for ch := range b.subscribers[notification.UserID] {
+		select {
+		case ch <- notification:
+		default:
+			saturated = append(saturated, ch)
+		}
+	}
+	b.mu.RUnlock()
+
+	for _, ch := range saturated {
+		b.Unsubscribe(notification.UserID, ch)
+	}
+}
+
+// StreamMessage reprend le format des messages WebSocket pour que le client
+// traite de la même façon les notifications reçues par SSE
+type StreamMessage struct {
+	Type      string      `json:"type"`
+	Data      interface{} `json:"data"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+// NewStreamMessage construit le message temps réel d'une notification
+func NewStreamMessage(notification *Notification) StreamMessage {
+	data := map[string]interface{}{
+		"id":           notification.ID,
+		"type":         notification.Type,
+		"from_user_id": notification.FromID,
+		"to_user_id":   notification.UserID,
+		"message":      notification.Message,
+	}
+
+	if notification.FromUser != nil {
+		data["from_username"] = notification.FromUser.Username
+		data["message"] = notification.DisplayMessage()
+	}
+
+	return StreamMessage{
+		Type:      "notification",
+		Data:      data,
+		Timestamp: notification.CreatedAt,
+	}
+}
+
+// DisplayMessage construit le libellé affiché à l'utilisateur, comme pour les messages WebSocket
+func (n *Notification) DisplayMessage() string {
+	if n.FromUser == nil || n.Type == NotificationSearchMatch || n.Type == NotificationReportDone {
+		return n.Message
+	}
+
+	if n.Type == NotificationMatch {
+		return fmt.Sprintf("Vous avez un nouveau match avec %s !", n.FromUser.Username)
+	}
+	return fmt.Sprintf("%s %s", n.FromUser.Username, n.Message)
+}
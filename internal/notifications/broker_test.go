@@ -0,0 +1,34 @@
+package notifications
+
+import "testing"
+
+func TestBrokerClosesSaturatedSubscriber(t *testing.T) {
+	broker := NewBroker()
+	slow := broker.Subscribe(1)
+	other := broker.Subscribe(2)
+
+	for id := 1; id <= cap(slow)+1; id++ {
+		broker.Publish(&Notification{ID: id, UserID: 1})
+	}
+
+	// Le flux saturé est fermé après les notifications déjà mises en file
+	received := 0
+	for range slow {
+		received++
+	}
+	if received != cap(slow) {
+		t.Errorf("%d notifications reçues avant fermeture, attendu %d", received, cap(slow))
+	}
+	if broker.HasSubscribers(1) {
+		t.Error("le flux saturé est encore abonné")
+	}
+
+	// Les flux des autres utilisateurs ne sont pas touchés
+	broker.Publish(&Notification{ID: 100, UserID: 2})
+	if notification := <-other; notification.ID != 100 {
+		t.Errorf("notification %d reçue, attendu 100", notification.ID)
+	}
+
+	// Le désabonnement par le handler reste sans effet sur un flux déjà fermé
+	broker.Unsubscribe(1, slow)
+}
@@ -2,14 +2,23 @@ package notifications
 
 import (
 	"encoding/json"
+	"fmt"
 	"html" // ✅ AJOUT de l'import html
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/cduffaut/matcha/internal/session"
 	"goji.io/pat"
 )
 
+// Paramètres du flux SSE
+const (
+	streamHeartbeatInterval = 25 * time.Second
+	streamRetryDelay        = 5 * time.Second
+	streamReplayLimit       = 100 // Taille des lots relus à la reprise
+)
+
 // Handlers gère les requêtes HTTP pour les notifications
 type Handlers struct {
 	service NotificationService
@@ -127,6 +136,112 @@ func (h *Handlers) MarkAllAsReadHandler(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+// StreamHandler diffuse les notifications en Server-Sent Events (repli quand le WebSocket est bloqué)
+func (h *Handlers) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	// Récupérer la session
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming non supporté", http.StatusInternalServerError)
+		return
+	}
+
+	// Reprise : le navigateur renvoie Last-Event-ID à la reconnexion
+	lastEventID := 0
+	lastEventIDStr := r.Header.Get("Last-Event-ID")
+	if lastEventIDStr == "" {
+		lastEventIDStr = r.URL.Query().Get("last_event_id")
+	}
+	if lastEventIDStr != "" {
+		if id, err := strconv.Atoi(lastEventIDStr); err == nil && id > 0 {
+			lastEventID = id
+		}
+	}
+
+	// S'abonner avant la reprise pour ne rien perdre entre les deux
+	ch := h.service.Subscribe(session.UserID)
+	defer h.service.Unsubscribe(session.UserID, ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", streamRetryDelay.Milliseconds()); err != nil {
+		return
+	}
+
+	// Rejouer les notifications manquées depuis la table, par lots, jusqu'à un
+	// lot incomplet. En cas d'échec le flux est fermé sans avancer lastEventID :
+	// le client se reconnecte et la reprise recommence au même point
+	for lastEventID > 0 {
+		missed, err := h.service.GetNotificationsSince(session.UserID, lastEventID, streamReplayLimit)
+		if err != nil {
+			fmt.Printf("Erreur lors de la reprise du flux de notifications: %v\n", err)
+			return
+		}
+		for _, notification := range missed {
+			if err := writeStreamEvent(w, notification); err != nil {
+				return
+			}
+			lastEventID = notification.ID
+		}
+		flusher.Flush()
+		if len(missed) < streamReplayLimit {
+			break
+		}
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case notification, ok := <-ch:
+			if !ok {
+				// Flux saturé et fermé par le broker : le client reprendra à lastEventID
+				return
+			}
+			// Déjà envoyée pendant la reprise
+			if notification.ID <= lastEventID {
+				continue
+			}
+			if err := writeStreamEvent(w, notification); err != nil {
+				return
+			}
+			lastEventID = notification.ID
+			flusher.Flush()
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeStreamEvent écrit une notification au format SSE
+func writeStreamEvent(w http.ResponseWriter, notification *Notification) error {
+	data, err := json.Marshal(NewStreamMessage(notification))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: notification\ndata: %s\n\n", notification.ID, data)
+	return err
+}
+
 // NotificationsPageHandler affiche la page des notifications
 func (h *Handlers) NotificationsPageHandler(w http.ResponseWriter, r *http.Request) {
 	// Récupérer la session
@@ -181,6 +296,7 @@ func generateNotificationsHTML(notifications []*Notification) string {
         <h2>Notifications</h2>
         <div class="notifications-actions">
             <button id="mark-all-read">Tout marquer comme lu</button>
+            <button id="toggle-push" hidden>Activer les notifications push</button>
         </div>
         <div class="notifications-list">`
 
@@ -209,10 +325,12 @@ func generateNotificationsHTML(notifications []*Notification) string {
 	htmlContent += `</div>
     </div>
 
+	<script src="/static/js/csrf.js"></script>
 	<script src="/static/js/global-error-handler.js"></script>
     <script src="/static/js/user_status.js"></script>
     <script src="/static/js/navigation_active.js"></script>
     <script src="/static/js/notifications_unified.js"></script>
+    <script src="/static/js/push.js"></script>
     
     <!-- Script spécifique -->
     <script src="/static/js/profile.js"></script>
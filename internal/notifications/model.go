@@ -13,8 +13,30 @@ const (
 	NotificationMatch       NotificationType = "match"   // Match mutuel
 	NotificationUnlike      NotificationType = "unlike"  // Quelqu'un vous a unliké
 	NotificationProfileView NotificationType = "profile_view"
+	NotificationSearchMatch NotificationType = "search_match"    // Nouveaux profils correspondant à une recherche sauvegardée
+	NotificationReportDone  NotificationType = "report_resolved" // Un signalement déposé a été traité
 )
 
+// DefaultMessage retourne le message standard associé à un type de notification
+func DefaultMessage(notificationType NotificationType) string {
+	switch notificationType {
+	case NotificationLike:
+		return "a liké votre profil"
+	case NotificationMatch:
+		return "Vous avez un nouveau match ! Vous pouvez maintenant discuter"
+	case NotificationUnlike:
+		return "ne vous like plus"
+	case NotificationProfileView:
+		return "a consulté votre profil"
+	case NotificationSearchMatch:
+		return "De nouveaux profils correspondent à votre recherche"
+	case NotificationReportDone:
+		return "Votre signalement a été traité par l'équipe de modération"
+	default:
+		return ""
+	}
+}
+
 // Notification représente une notification utilisateur
 type Notification struct {
 	ID        int              `json:"id" db:"id"`
@@ -24,6 +46,7 @@ type Notification struct {
 	Message   string           `json:"message" db:"message"` // Message de la notification
 	IsRead    bool             `json:"is_read" db:"is_read"`
 	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	DedupKey  *string          `json:"-" db:"dedup_key"` // Clé d'idempotence pour les livraisons outbox
 
 	// Informations supplémentaires sur l'utilisateur source
 	FromUser *UserInfo `json:"from_user,omitempty" db:"-"`
@@ -40,16 +63,27 @@ type UserInfo struct {
 type NotificationRepository interface {
 	Create(notification *Notification) error
 	GetByUserID(userID int, limit int) ([]*Notification, error)
+	GetByID(notificationID int) (*Notification, error)
+	GetSince(userID, afterID int, limit int) ([]*Notification, error)
 	MarkAsRead(notificationID int) error
 	MarkAllAsRead(userID int) error
 	GetUnreadCount(userID int) (int, error)
 	Delete(notificationID int) error
 }
 
+// Dispatcher reçoit chaque notification créée pour une livraison complémentaire (ex: Web Push)
+type Dispatcher interface {
+	Dispatch(notification *Notification)
+}
+
 // NotificationService interface pour la logique métier des notifications
 type NotificationService interface {
 	CreateNotification(userID, fromID int, notificationType NotificationType, message string) error
+	CreateNotificationOnce(userID, fromID int, notificationType NotificationType, message, dedupKey string) error
 	GetNotifications(userID int, limit int) ([]*Notification, error)
+	GetNotificationsSince(userID, afterID int, limit int) ([]*Notification, error)
+	Subscribe(userID int) chan *Notification
+	Unsubscribe(userID int, ch chan *Notification)
 	MarkAsRead(notificationID int) error
 	MarkAllAsRead(userID int) error
 	GetUnreadCount(userID int) (int, error)
@@ -60,4 +94,6 @@ type NotificationService interface {
 	NotifyMatch(user1ID, user2ID int) error
 	NotifyUnlike(unlikedUserID, unlikerID int) error
 	NotifyProfileView(viewedUserID, viewerID int) error // ✅ AJOUTER
+	NotifySearchMatch(userID, profileID int, searchName string, count int) error
+	NotifyReportResolved(reporterID, reportedID int, upheld bool) error
 }
@@ -0,0 +1,24 @@
+package notifications
+
+import (
+	"github.com/cduffaut/matcha/internal/outbox"
+)
+
+// OutboxHandler crée les notifications enregistrées dans l'outbox.
+// La clé de déduplication de l'événement garantit qu'une relivraison ne crée pas de doublon.
+func OutboxHandler(service NotificationService) outbox.Handler {
+	return func(event *outbox.Event) error {
+		var payload outbox.NotificationPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+
+		return service.CreateNotificationOnce(
+			payload.UserID,
+			payload.FromID,
+			NotificationType(payload.Type),
+			payload.Message,
+			event.DedupKey,
+		)
+	}
+}
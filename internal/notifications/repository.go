@@ -18,8 +18,9 @@ func NewPostgresNotificationRepository(db *sql.DB) NotificationRepository {
 // Create crée une nouvelle notification
 func (r *PostgresNotificationRepository) Create(notification *Notification) error {
 	query := `
-		INSERT INTO notifications (user_id, from_id, type, message, is_read)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO notifications (user_id, from_id, type, message, is_read, dedup_key)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (dedup_key) DO NOTHING
 		RETURNING id, created_at
 	`
 
@@ -30,8 +31,14 @@ func (r *PostgresNotificationRepository) Create(notification *Notification) erro
 		notification.Type,
 		notification.Message,
 		notification.IsRead,
+		notification.DedupKey,
 	).Scan(&notification.ID, &notification.CreatedAt)
 
+	// Aucune ligne retournée : une notification avec la même clé existe déjà
+	if err == sql.ErrNoRows {
+		notification.ID = 0
+		return nil
+	}
 	if err != nil {
 		return fmt.Errorf("erreur lors de la création de la notification: %w", err)
 	}
@@ -89,6 +96,92 @@ func (r *PostgresNotificationRepository) GetByUserID(userID int, limit int) ([]*
 	return notifications, nil
 }
 
+// GetByID récupère une notification avec les informations de son émetteur
+func (r *PostgresNotificationRepository) GetByID(notificationID int) (*Notification, error) {
+	query := `
+		SELECT n.id, n.user_id, n.from_id, n.type, n.message, n.is_read, n.created_at,
+			   u.username, CONCAT(u.first_name, ' ', u.last_name) as full_name
+		FROM notifications n
+		JOIN users u ON n.from_id = u.id
+		WHERE n.id = $1
+	`
+
+	notification := &Notification{
+		FromUser: &UserInfo{},
+	}
+
+	err := r.db.QueryRow(query, notificationID).Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.FromID,
+		&notification.Type,
+		&notification.Message,
+		&notification.IsRead,
+		&notification.CreatedAt,
+		&notification.FromUser.Username,
+		&notification.FromUser.Name,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("notification avec ID %d non trouvée", notificationID)
+		}
+		return nil, fmt.Errorf("erreur lors de la récupération de la notification: %w", err)
+	}
+
+	notification.FromUser.ID = notification.FromID
+	return notification, nil
+}
+
+// GetSince récupère les notifications créées après un ID donné, de la plus ancienne à la plus récente
+func (r *PostgresNotificationRepository) GetSince(userID, afterID int, limit int) ([]*Notification, error) {
+	query := `
+		SELECT n.id, n.user_id, n.from_id, n.type, n.message, n.is_read, n.created_at,
+			   u.username, CONCAT(u.first_name, ' ', u.last_name) as full_name
+		FROM notifications n
+		JOIN users u ON n.from_id = u.id
+		WHERE n.user_id = $1 AND n.id > $2
+		ORDER BY n.id ASC
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(query, userID, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des notifications manquées: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []*Notification
+	for rows.Next() {
+		notification := &Notification{
+			FromUser: &UserInfo{},
+		}
+
+		err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.FromID,
+			&notification.Type,
+			&notification.Message,
+			&notification.IsRead,
+			&notification.CreatedAt,
+			&notification.FromUser.Username,
+			&notification.FromUser.Name,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'une notification: %w", err)
+		}
+
+		notification.FromUser.ID = notification.FromID
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erreur lors du parcours des notifications: %w", err)
+	}
+
+	return notifications, nil
+}
+
 // MarkAsRead marque une notification comme lue
 func (r *PostgresNotificationRepository) MarkAsRead(notificationID int) error {
 	query := `UPDATE notifications SET is_read = TRUE WHERE id = $1`
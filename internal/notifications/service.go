@@ -6,13 +6,17 @@ import (
 
 // Service implémentation du service de notifications
 type Service struct {
-	repo NotificationRepository
+	repo        NotificationRepository
+	broker      *Broker
+	dispatchers []Dispatcher
 }
 
 // NewService crée un nouveau service de notifications
-func NewService(repo NotificationRepository) NotificationService {
+func NewService(repo NotificationRepository, broker *Broker, dispatchers ...Dispatcher) NotificationService {
 	return &Service{
-		repo: repo,
+		repo:        repo,
+		broker:      broker,
+		dispatchers: dispatchers,
 	}
 }
 
@@ -31,7 +35,62 @@ func (s *Service) CreateNotification(userID, fromID int, notificationType Notifi
 		IsRead:  false,
 	}
 
-	return s.repo.Create(notification)
+	if err := s.repo.Create(notification); err != nil {
+		return err
+	}
+
+	s.publish(notification)
+	return nil
+}
+
+// CreateNotificationOnce crée une notification identifiée par une clé d'idempotence :
+// une nouvelle livraison avec la même clé n'a aucun effet
+func (s *Service) CreateNotificationOnce(userID, fromID int, notificationType NotificationType, message, dedupKey string) error {
+	if userID == fromID {
+		return nil
+	}
+
+	notification := &Notification{
+		UserID:   userID,
+		FromID:   fromID,
+		Type:     notificationType,
+		Message:  message,
+		IsRead:   false,
+		DedupKey: &dedupKey,
+	}
+
+	if err := s.repo.Create(notification); err != nil {
+		return err
+	}
+
+	// ID nul : notification déjà créée lors d'une livraison précédente
+	if notification.ID == 0 {
+		return nil
+	}
+
+	s.publish(notification)
+	return nil
+}
+
+// publish diffuse une notification créée vers les flux temps réel et les dispatchers
+func (s *Service) publish(notification *Notification) {
+	hasSubscribers := s.broker != nil && s.broker.HasSubscribers(notification.UserID)
+	if !hasSubscribers && len(s.dispatchers) == 0 {
+		return
+	}
+
+	// Recharger la notification pour inclure les informations de l'émetteur
+	full, err := s.repo.GetByID(notification.ID)
+	if err != nil {
+		full = notification
+	}
+
+	if hasSubscribers {
+		s.broker.Publish(full)
+	}
+	for _, dispatcher := range s.dispatchers {
+		dispatcher.Dispatch(full)
+	}
 }
 
 // GetNotifications récupère les notifications d'un utilisateur
@@ -42,6 +101,30 @@ func (s *Service) GetNotifications(userID int, limit int) ([]*Notification, erro
 	return s.repo.GetByUserID(userID, limit)
 }
 
+// GetNotificationsSince récupère les notifications postérieures à un ID (reprise d'un flux)
+func (s *Service) GetNotificationsSince(userID, afterID int, limit int) ([]*Notification, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return s.repo.GetSince(userID, afterID, limit)
+}
+
+// Subscribe ouvre un flux temps réel des notifications d'un utilisateur
+func (s *Service) Subscribe(userID int) chan *Notification {
+	if s.broker == nil {
+		return make(chan *Notification)
+	}
+	return s.broker.Subscribe(userID)
+}
+
+// Unsubscribe ferme un flux temps réel ouvert avec Subscribe
+func (s *Service) Unsubscribe(userID int, ch chan *Notification) {
+	if s.broker == nil {
+		return
+	}
+	s.broker.Unsubscribe(userID, ch)
+}
+
 // MarkAsRead marque une notification comme lue
 func (s *Service) MarkAsRead(notificationID int) error {
 	return s.repo.MarkAsRead(notificationID)
@@ -59,7 +142,7 @@ func (s *Service) GetUnreadCount(userID int) (int, error) {
 
 // NotifyLike crée une notification de "like"
 func (s *Service) NotifyLike(likedUserID, likerID int) error {
-	message := "a liké votre profil"
+	message := DefaultMessage(NotificationLike)
 	return s.CreateNotification(likedUserID, likerID, NotificationLike, message)
 }
 
@@ -74,7 +157,7 @@ func (s *Service) NotifyMessage(recipientID, senderID int, messagePreview string
 
 // NotifyMatch crée une notification de match
 func (s *Service) NotifyMatch(user1ID, user2ID int) error {
-	message := "Vous avez un nouveau match ! Vous pouvez maintenant discuter"
+	message := DefaultMessage(NotificationMatch)
 
 	// Créer une notification pour chaque utilisateur
 	err1 := s.CreateNotification(user1ID, user2ID, NotificationMatch, message)
@@ -88,12 +171,32 @@ func (s *Service) NotifyMatch(user1ID, user2ID int) error {
 
 // NotifyUnlike crée une notification d'"unlike"
 func (s *Service) NotifyUnlike(unlikedUserID, unlikerID int) error {
-	message := "ne vous like plus"
+	message := DefaultMessage(NotificationUnlike)
 	return s.CreateNotification(unlikedUserID, unlikerID, NotificationUnlike, message)
 }
 
 // NotifyProfileView crée une notification de vue de profil
 func (s *Service) NotifyProfileView(viewedUserID, viewerID int) error {
-	message := "a consulté votre profil"
+	message := DefaultMessage(NotificationProfileView)
 	return s.CreateNotification(viewedUserID, viewerID, NotificationProfileView, message)
 }
+
+// NotifySearchMatch signale les nouveaux profils d'une recherche sauvegardée ;
+// profileID (le premier d'entre eux) sert d'émetteur de la notification
+func (s *Service) NotifySearchMatch(userID, profileID int, searchName string, count int) error {
+	message := fmt.Sprintf("%d nouveaux profils correspondent à votre recherche « %s »", count, searchName)
+	if count == 1 {
+		message = fmt.Sprintf("Un nouveau profil correspond à votre recherche « %s »", searchName)
+	}
+	return s.CreateNotification(userID, profileID, NotificationSearchMatch, message)
+}
+
+// NotifyReportResolved prévient le signaleur de la clôture de son signalement ;
+// reportedID (la personne signalée) sert d'émetteur de la notification
+func (s *Service) NotifyReportResolved(reporterID, reportedID int, upheld bool) error {
+	message := DefaultMessage(NotificationReportDone) + " : aucune infraction n'a été constatée"
+	if upheld {
+		message = DefaultMessage(NotificationReportDone) + " : des mesures ont été prises. Merci de votre vigilance"
+	}
+	return s.CreateNotification(reporterID, reportedID, NotificationReportDone, message)
+}
@@ -0,0 +1,152 @@
+package outbox
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Paramètres du dispatcher
+const (
+	dispatchInterval = 2 * time.Second // Scrutation de secours si aucun réveil n'est reçu
+	dispatchBatch    = 50
+	dispatchLease    = 30 * time.Second // Délai avant qu'un événement réservé non traité soit repris
+	maxAttempts      = 8
+	retryBaseDelay   = 2 * time.Second
+	retryMaxDelay    = 10 * time.Minute
+	purgeInterval    = time.Hour
+	purgeRetention   = 7 * 24 * time.Hour
+)
+
+// Dispatcher livre les événements de l'outbox à leurs handlers, au moins une fois
+type Dispatcher struct {
+	repo     Repository
+	mu       sync.RWMutex
+	handlers map[EventType]Handler
+	wake     chan struct{}
+}
+
+// NewDispatcher crée un nouveau dispatcher outbox
+func NewDispatcher(repo Repository) *Dispatcher {
+	return &Dispatcher{
+		repo:     repo,
+		handlers: make(map[EventType]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Handle enregistre le handler d'un type d'événement
+func (d *Dispatcher) Handle(eventType EventType, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.handlers[eventType] = handler
+}
+
+// Enqueue enregistre des événements dans la transaction fournie
+func (d *Dispatcher) Enqueue(tx *sql.Tx, events ...*Event) error {
+	return d.repo.Enqueue(tx, events...)
+}
+
+// Wake déclenche une livraison immédiate, sans attendre la prochaine scrutation
+func (d *Dispatcher) Wake() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+		// Un réveil est déjà en attente
+	}
+}
+
+// Start lance la boucle de livraison en arrière-plan
+func (d *Dispatcher) Start() {
+	go d.run()
+}
+
+func (d *Dispatcher) run() {
+	ticker := time.NewTicker(dispatchInterval)
+	defer ticker.Stop()
+	purgeTicker := time.NewTicker(purgeInterval)
+	defer purgeTicker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-d.wake:
+		case <-purgeTicker.C:
+			if purged, err := d.repo.PurgeProcessed(purgeRetention); err != nil {
+				fmt.Printf("Erreur purge outbox: %v\n", err)
+			} else if purged > 0 {
+				fmt.Printf("Outbox : %d événements livrés purgés\n", purged)
+			}
+			continue
+		}
+
+		d.ProcessPending()
+	}
+}
+
+// ProcessPending livre tous les événements disponibles
+func (d *Dispatcher) ProcessPending() {
+	for {
+		events, err := d.repo.Claim(dispatchBatch, dispatchLease)
+		if err != nil {
+			fmt.Printf("Erreur lecture outbox: %v\n", err)
+			return
+		}
+
+		for _, event := range events {
+			d.deliver(event)
+		}
+
+		if len(events) < dispatchBatch {
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(event *Event) {
+	d.mu.RLock()
+	handler, ok := d.handlers[event.Type]
+	d.mu.RUnlock()
+
+	if !ok {
+		d.fail(event, fmt.Sprintf("aucun handler pour le type %s", event.Type))
+		return
+	}
+
+	if err := handler(event); err != nil {
+		if event.Attempts >= maxAttempts {
+			d.fail(event, err.Error())
+			return
+		}
+
+		if err := d.repo.MarkRetry(event.ID, err.Error(), time.Now().Add(retryDelay(event.Attempts))); err != nil {
+			fmt.Printf("Erreur outbox: %v\n", err)
+		}
+		return
+	}
+
+	if err := d.repo.MarkDone(event.ID); err != nil {
+		// L'événement sera relivré après expiration du bail : les handlers sont idempotents
+		fmt.Printf("Erreur outbox: %v\n", err)
+	}
+}
+
+func (d *Dispatcher) fail(event *Event, reason string) {
+	fmt.Printf("Événement outbox %d (%s) abandonné: %s\n", event.ID, event.DedupKey, reason)
+	if err := d.repo.MarkFailed(event.ID, reason); err != nil {
+		fmt.Printf("Erreur outbox: %v\n", err)
+	}
+}
+
+// retryDelay calcule un délai exponentiel plafonné avant la tentative suivante
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
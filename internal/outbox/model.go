@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EventType représente le type d'effet de bord à livrer
+type EventType string
+
+const (
+	EventNotification  EventType = "notification"   // Création d'une notification en base
+	EventWebSocketPush EventType = "ws_push"        // Message temps réel via chat.Hub
+	EventFameRecompute EventType = "fame_recompute" // N'est plus émis : le fame rating est recalculé par une tâche planifiée
+)
+
+// Event représente un événement enregistré dans la table outbox
+type Event struct {
+	ID        int64           `json:"id" db:"id"`
+	Type      EventType       `json:"event_type" db:"event_type"`
+	DedupKey  string          `json:"dedup_key" db:"dedup_key"` // Unique : un même effet n'est enregistré qu'une fois
+	Payload   json.RawMessage `json:"payload" db:"payload"`
+	Attempts  int             `json:"attempts" db:"attempts"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// NotificationPayload décrit une notification à créer
+type NotificationPayload struct {
+	UserID  int    `json:"user_id"`
+	FromID  int    `json:"from_id"`
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// WebSocketPayload décrit un message temps réel lié à une interaction entre deux utilisateurs
+type WebSocketPayload struct {
+	Type       string `json:"type"` // like, match, unlike
+	FromUserID int    `json:"from_user_id"`
+	ToUserID   int    `json:"to_user_id"`
+}
+
+// NewEvent construit un événement en sérialisant sa charge utile
+func NewEvent(eventType EventType, dedupKey string, payload interface{}) (*Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de l'encodage de l'événement %s: %w", eventType, err)
+	}
+
+	return &Event{
+		Type:     eventType,
+		DedupKey: dedupKey,
+		Payload:  data,
+	}, nil
+}
+
+// Decode désérialise la charge utile de l'événement
+func (e *Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("charge utile invalide pour l'événement %d: %w", e.ID, err)
+	}
+	return nil
+}
+
+// Handler livre un événement ; une erreur provoque une nouvelle tentative
+type Handler func(event *Event) error
+
+// Publisher enregistre des événements dans une transaction métier
+type Publisher interface {
+	Enqueue(tx *sql.Tx, events ...*Event) error
+	// Wake signale au dispatcher que de nouveaux événements ont été validés
+	Wake()
+}
+
+// Repository interface pour la gestion de la table outbox
+type Repository interface {
+	Enqueue(tx *sql.Tx, events ...*Event) error
+	Claim(limit int, lease time.Duration) ([]*Event, error)
+	MarkDone(eventID int64) error
+	MarkRetry(eventID int64, lastError string, retryAt time.Time) error
+	MarkFailed(eventID int64, lastError string) error
+	PurgeProcessed(olderThan time.Duration) (int64, error)
+}
@@ -0,0 +1,145 @@
+package outbox
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// PostgresRepository implémentation PostgreSQL du repository outbox
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository crée un nouveau repository outbox
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// Enqueue enregistre des événements dans la transaction fournie.
+// Un événement dont la clé de déduplication existe déjà est ignoré.
+func (r *PostgresRepository) Enqueue(tx *sql.Tx, events ...*Event) error {
+	query := `
+		INSERT INTO outbox_events (event_type, dedup_key, payload)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (dedup_key) DO NOTHING
+	`
+
+	for _, event := range events {
+		if _, err := tx.Exec(query, event.Type, event.DedupKey, []byte(event.Payload)); err != nil {
+			return fmt.Errorf("erreur lors de l'enregistrement de l'événement %s: %w", event.DedupKey, err)
+		}
+	}
+
+	return nil
+}
+
+// Claim réserve un lot d'événements à livrer pour la durée du bail.
+// SKIP LOCKED permet à plusieurs instances de se partager la file sans doublon.
+func (r *PostgresRepository) Claim(limit int, lease time.Duration) ([]*Event, error) {
+	query := `
+		UPDATE outbox_events
+		SET locked_until = CURRENT_TIMESTAMP + make_interval(secs => $2),
+			attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox_events
+			WHERE processed_at IS NULL AND failed_at IS NULL
+			AND available_at <= CURRENT_TIMESTAMP
+			AND (locked_until IS NULL OR locked_until < CURRENT_TIMESTAMP)
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, event_type, dedup_key, payload, attempts, created_at
+	`
+
+	rows, err := r.db.Query(query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la réservation des événements outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		event := &Event{}
+		var payload []byte
+		if err := rows.Scan(&event.ID, &event.Type, &event.DedupKey, &payload, &event.Attempts, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un événement outbox: %w", err)
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erreur lors du parcours des événements outbox: %w", err)
+	}
+
+	// RETURNING ne garantit pas l'ordre : livrer dans l'ordre d'enregistrement
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	return events, nil
+}
+
+// MarkDone marque un événement comme livré
+func (r *PostgresRepository) MarkDone(eventID int64) error {
+	query := `
+		UPDATE outbox_events
+		SET processed_at = CURRENT_TIMESTAMP, locked_until = NULL, last_error = NULL
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(query, eventID); err != nil {
+		return fmt.Errorf("erreur lors de la validation de l'événement %d: %w", eventID, err)
+	}
+
+	return nil
+}
+
+// MarkRetry replanifie un événement dont la livraison a échoué
+func (r *PostgresRepository) MarkRetry(eventID int64, lastError string, retryAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET available_at = $2, locked_until = NULL, last_error = $3
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(query, eventID, retryAt, lastError); err != nil {
+		return fmt.Errorf("erreur lors de la replanification de l'événement %d: %w", eventID, err)
+	}
+
+	return nil
+}
+
+// MarkFailed abandonne un événement après trop d'échecs (conservé pour analyse)
+func (r *PostgresRepository) MarkFailed(eventID int64, lastError string) error {
+	query := `
+		UPDATE outbox_events
+		SET failed_at = CURRENT_TIMESTAMP, locked_until = NULL, last_error = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.db.Exec(query, eventID, lastError); err != nil {
+		return fmt.Errorf("erreur lors de l'abandon de l'événement %d: %w", eventID, err)
+	}
+
+	return nil
+}
+
+// PurgeProcessed supprime les événements livrés depuis plus de olderThan
+func (r *PostgresRepository) PurgeProcessed(olderThan time.Duration) (int64, error) {
+	query := `
+		DELETE FROM outbox_events
+		WHERE processed_at IS NOT NULL
+		AND processed_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`
+
+	result, err := r.db.Exec(query, olderThan.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("erreur lors de la purge de l'outbox: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,231 @@
+package push
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// recordSize taille d'enregistrement aes128gcm annoncée dans l'en-tête (RFC 8188)
+	recordSize = 4096
+	// maxPayloadSize charge utile maximale dans un seul enregistrement (délimiteur + tag GCM)
+	maxPayloadSize = recordSize - 1 - 16
+	// vapidTokenLifetime durée de validité du JWT VAPID (24h maximum selon RFC 8292)
+	vapidTokenLifetime = 12 * time.Hour
+)
+
+// VAPIDKeys contient la paire de clés P-256 identifiant le serveur auprès des services push
+type VAPIDKeys struct {
+	private *ecdsa.PrivateKey
+	public  []byte // Point non compressé (65 octets)
+}
+
+// GenerateVAPIDKeys génère une nouvelle paire de clés VAPID
+func GenerateVAPIDKeys() (*VAPIDKeys, error) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la génération des clés VAPID: %w", err)
+	}
+
+	return newVAPIDKeys(private)
+}
+
+// ParseVAPIDKeys décode une clé privée VAPID (scalaire de 32 octets en base64url)
+// et vérifie qu'elle correspond à la clé publique configurée
+func ParseVAPIDKeys(publicKey, privateKey string) (*VAPIDKeys, error) {
+	d, err := decodeBase64URL(privateKey)
+	if err != nil || len(d) != 32 {
+		return nil, fmt.Errorf("clé privée VAPID invalide")
+	}
+
+	ecdhKey, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, fmt.Errorf("clé privée VAPID invalide: %w", err)
+	}
+	point := ecdhKey.PublicKey().Bytes()
+
+	private := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(point[1:33]),
+			Y:     new(big.Int).SetBytes(point[33:65]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}
+
+	keys, err := newVAPIDKeys(private)
+	if err != nil {
+		return nil, err
+	}
+
+	if publicKey != "" && publicKey != keys.PublicKey() {
+		return nil, fmt.Errorf("la clé publique VAPID ne correspond pas à la clé privée")
+	}
+
+	return keys, nil
+}
+
+func newVAPIDKeys(private *ecdsa.PrivateKey) (*VAPIDKeys, error) {
+	ecdhKey, err := private.PublicKey.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("clé VAPID invalide: %w", err)
+	}
+
+	return &VAPIDKeys{
+		private: private,
+		public:  ecdhKey.Bytes(),
+	}, nil
+}
+
+// PublicKey retourne la clé publique en base64url, à fournir comme applicationServerKey
+func (k *VAPIDKeys) PublicKey() string {
+	return base64.RawURLEncoding.EncodeToString(k.public)
+}
+
+// PrivateKey retourne la clé privée en base64url, au format attendu par VAPID_PRIVATE_KEY
+func (k *VAPIDKeys) PrivateKey() string {
+	return base64.RawURLEncoding.EncodeToString(k.private.D.FillBytes(make([]byte, 32)))
+}
+
+// authorizationHeader construit l'en-tête Authorization VAPID (RFC 8292) pour une origine
+func (k *VAPIDKeys) authorizationHeader(audience, subject string, now time.Time) (string, error) {
+	header := map[string]string{"typ": "JWT", "alg": "ES256"}
+	claims := map[string]interface{}{
+		"aud": audience,
+		"exp": now.Add(vapidTokenLifetime).Unix(),
+		"sub": subject,
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." +
+		base64.RawURLEncoding.EncodeToString(claimsJSON)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, k.private, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("erreur lors de la signature du jeton VAPID: %w", err)
+	}
+
+	// Signature JWS ES256 : r || s sur 32 octets chacun
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+	return fmt.Sprintf("vapid t=%s, k=%s", token, k.PublicKey()), nil
+}
+
+// encryptPayload chiffre une charge utile pour un abonnement selon RFC 8291 (aes128gcm)
+func encryptPayload(plaintext []byte, p256dh, authSecret string) ([]byte, error) {
+	if len(plaintext) > maxPayloadSize {
+		return nil, fmt.Errorf("charge utile trop volumineuse (%d octets)", len(plaintext))
+	}
+
+	uaPublicBytes, err := decodeBase64URL(p256dh)
+	if err != nil {
+		return nil, fmt.Errorf("clé p256dh invalide: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("clé p256dh invalide: %w", err)
+	}
+
+	auth, err := decodeBase64URL(authSecret)
+	if err != nil || len(auth) != 16 {
+		return nil, fmt.Errorf("secret auth invalide")
+	}
+
+	// Clé éphémère du serveur d'application, utilisée pour ce seul message
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la génération de la clé éphémère: %w", err)
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+
+	ecdhSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de l'échange ECDH: %w", err)
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("erreur lors de la génération du sel: %w", err)
+	}
+
+	// IKM = HKDF(auth_secret, ecdh_secret, "WebPush: info" || 0x00 || ua_public || as_public)
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublicBytes...)
+	keyInfo = append(keyInfo, asPublicBytes...)
+	ikm, err := hkdfExpand(hkdf.Extract(sha256.New, ecdhSecret, auth), keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek, err := hkdfExpand(prk, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdfExpand(prk, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Un seul enregistrement : délimiteur 0x02 de dernier enregistrement, sans bourrage
+	record := append(append([]byte{}, plaintext...), 0x02)
+	ciphertext := gcm.Seal(nil, nonce, record, nil)
+
+	// En-tête : salt (16) || rs (4) || idlen (1) || keyid (clé publique éphémère)
+	body := make([]byte, 0, 16+4+1+len(asPublicBytes)+len(ciphertext))
+	body = append(body, salt...)
+	body = binary.BigEndian.AppendUint32(body, recordSize)
+	body = append(body, byte(len(asPublicBytes)))
+	body = append(body, asPublicBytes...)
+	body = append(body, ciphertext...)
+
+	return body, nil
+}
+
+func hkdfExpand(prk, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), out); err != nil {
+		return nil, fmt.Errorf("erreur lors de la dérivation HKDF: %w", err)
+	}
+	return out, nil
+}
+
+// decodeBase64URL accepte le base64url avec ou sans bourrage, comme le produisent les navigateurs
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
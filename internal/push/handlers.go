@@ -0,0 +1,83 @@
+package push
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/cduffaut/matcha/internal/session"
+)
+
+// Handlers gère les requêtes HTTP pour les abonnements Web Push
+type Handlers struct {
+	service *Service
+}
+
+// NewHandlers crée de nouveaux handlers Web Push
+func NewHandlers(service *Service) *Handlers {
+	return &Handlers{
+		service: service,
+	}
+}
+
+// PublicKeyHandler retourne la clé publique VAPID
+func (h *Handlers) PublicKeyHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"public_key": h.service.PublicKey(),
+	})
+}
+
+// SubscribeHandler enregistre l'abonnement push du navigateur
+func (h *Handlers) SubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req SubscriptionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 8192)).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Format de données invalide"})
+		return
+	}
+
+	if _, err := h.service.Subscribe(session.UserID, &req, r.UserAgent()); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]string{"message": "Notifications push activées"})
+}
+
+// UnsubscribeHandler supprime l'abonnement push du navigateur
+func (h *Handlers) UnsubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	var req struct {
+		Endpoint string `json:"endpoint"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 8192)).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "Format de données invalide"})
+		return
+	}
+
+	if err := h.service.Unsubscribe(session.UserID, req.Endpoint); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"message": "Notifications push désactivées"})
+}
@@ -0,0 +1,45 @@
+package push
+
+import (
+	"time"
+)
+
+// Subscription représente un abonnement Web Push d'un navigateur
+type Subscription struct {
+	ID         int        `json:"id" db:"id"`
+	UserID     int        `json:"user_id" db:"user_id"`
+	Endpoint   string     `json:"endpoint" db:"endpoint"`
+	P256dh     string     `json:"p256dh" db:"p256dh"` // Clé publique ECDH du navigateur (base64url)
+	Auth       string     `json:"auth" db:"auth"`     // Secret d'authentification (base64url)
+	UserAgent  string     `json:"user_agent,omitempty" db:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+}
+
+// SubscriptionRequest correspond au JSON produit par PushSubscription.toJSON() côté navigateur
+type SubscriptionRequest struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// Payload est le contenu chiffré envoyé au service worker
+type Payload struct {
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	Type           string `json:"type"`
+	URL            string `json:"url"`
+	NotificationID int    `json:"notification_id"`
+	FromUserID     int    `json:"from_user_id"`
+}
+
+// Repository interface pour la gestion des abonnements Web Push
+type Repository interface {
+	Save(subscription *Subscription) error
+	Delete(userID int, endpoint string) error
+	DeleteByEndpoint(endpoint string) error
+	GetByUserID(userID int) ([]*Subscription, error)
+	MarkUsed(subscriptionID int) error
+}
@@ -0,0 +1,113 @@
+package push
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// PostgresRepository implémentation PostgreSQL du repository
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository crée un nouveau repository d'abonnements
+func NewPostgresRepository(db *sql.DB) Repository {
+	return &PostgresRepository{db: db}
+}
+
+// Save enregistre un abonnement, ou le rattache à l'utilisateur si l'endpoint existe déjà
+func (r *PostgresRepository) Save(subscription *Subscription) error {
+	query := `
+		INSERT INTO push_subscriptions (user_id, endpoint, p256dh, auth, user_agent)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (endpoint) DO UPDATE
+		SET user_id = EXCLUDED.user_id, p256dh = EXCLUDED.p256dh,
+			auth = EXCLUDED.auth, user_agent = EXCLUDED.user_agent
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(
+		query,
+		subscription.UserID,
+		subscription.Endpoint,
+		subscription.P256dh,
+		subscription.Auth,
+		subscription.UserAgent,
+	).Scan(&subscription.ID, &subscription.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement de l'abonnement push: %w", err)
+	}
+
+	return nil
+}
+
+// Delete supprime l'abonnement d'un utilisateur
+func (r *PostgresRepository) Delete(userID int, endpoint string) error {
+	query := `DELETE FROM push_subscriptions WHERE user_id = $1 AND endpoint = $2`
+
+	if _, err := r.db.Exec(query, userID, endpoint); err != nil {
+		return fmt.Errorf("erreur lors de la suppression de l'abonnement push: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByEndpoint supprime un abonnement expiré, quel que soit son propriétaire
+func (r *PostgresRepository) DeleteByEndpoint(endpoint string) error {
+	query := `DELETE FROM push_subscriptions WHERE endpoint = $1`
+
+	if _, err := r.db.Exec(query, endpoint); err != nil {
+		return fmt.Errorf("erreur lors de la suppression de l'abonnement push: %w", err)
+	}
+
+	return nil
+}
+
+// GetByUserID récupère les abonnements d'un utilisateur
+func (r *PostgresRepository) GetByUserID(userID int) ([]*Subscription, error) {
+	query := `
+		SELECT id, user_id, endpoint, p256dh, auth, COALESCE(user_agent, ''), created_at, last_used_at
+		FROM push_subscriptions
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+	`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des abonnements push: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*Subscription
+	for rows.Next() {
+		subscription := &Subscription{}
+		err := rows.Scan(
+			&subscription.ID,
+			&subscription.UserID,
+			&subscription.Endpoint,
+			&subscription.P256dh,
+			&subscription.Auth,
+			&subscription.UserAgent,
+			&subscription.CreatedAt,
+			&subscription.LastUsedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors du scan de l'abonnement push: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	return subscriptions, rows.Err()
+}
+
+// MarkUsed met à jour la date de dernière livraison réussie
+func (r *PostgresRepository) MarkUsed(subscriptionID int) error {
+	query := `UPDATE push_subscriptions SET last_used_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	if _, err := r.db.Exec(query, subscriptionID); err != nil {
+		return fmt.Errorf("erreur lors de la mise à jour de l'abonnement push: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,349 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/notifications"
+)
+
+// Paramètres de livraison Web Push
+const (
+	pushTTL          = 24 * time.Hour // Durée de conservation par le service push si le navigateur est hors ligne
+	pushMaxAttempts  = 3
+	pushRetryDelay   = 2 * time.Second
+	pushRequestLimit = 10 * time.Second
+	pushMaxRetryWait = 30 * time.Second // Plafond appliqué au Retry-After du service push
+	pushWorkers      = 4                // Envois simultanés
+	pushQueueSize    = 256              // Notifications en attente au-delà desquelles les suivantes sont abandonnées
+)
+
+// ErrSubscriptionGone indique que le service push a invalidé l'abonnement (404/410)
+var ErrSubscriptionGone = errors.New("abonnement push expiré")
+
+// errInternalEndpoint refuse les endpoints résolus vers une adresse interne
+var errInternalEndpoint = errors.New("endpoint push vers une adresse interne refusé")
+
+// sharedAddressSpace plage 100.64.0.0/10 (CGNAT), absente de net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// pushedTypes types de notifications relayés en Web Push quand l'utilisateur n'a pas d'onglet ouvert
+var pushedTypes = map[notifications.NotificationType]bool{
+	notifications.NotificationMatch:   true,
+	notifications.NotificationMessage: true,
+}
+
+// pushJob notification en attente d'envoi à tous les abonnements d'un utilisateur
+type pushJob struct {
+	userID  int
+	payload *Payload
+}
+
+// Service gère les abonnements et l'envoi des notifications Web Push
+type Service struct {
+	repo         Repository
+	keys         *VAPIDKeys
+	subject      string
+	client       *http.Client
+	isOnline     func(userID int) bool
+	retryDelay   time.Duration
+	maxRetryWait time.Duration
+	queue        chan pushJob
+}
+
+// NewService crée un nouveau service Web Push.
+// isOnline indique si l'utilisateur reçoit déjà les notifications en temps réel (WebSocket ou SSE).
+func NewService(repo Repository, keys *VAPIDKeys, subject string, isOnline func(userID int) bool) *Service {
+	return &Service{
+		repo:         repo,
+		keys:         keys,
+		subject:      subject,
+		client:       newPushClient(),
+		isOnline:     isOnline,
+		retryDelay:   pushRetryDelay,
+		maxRetryWait: pushMaxRetryWait,
+		queue:        make(chan pushJob, pushQueueSize),
+	}
+}
+
+// Start lance les workers d'envoi des notifications mises en file par Dispatch
+func (s *Service) Start() {
+	for i := 0; i < pushWorkers; i++ {
+		go s.work()
+	}
+}
+
+func (s *Service) work() {
+	for job := range s.queue {
+		s.SendToUser(job.userID, job.payload)
+	}
+}
+
+// PublicKey retourne la clé publique VAPID à utiliser comme applicationServerKey
+func (s *Service) PublicKey() string {
+	return s.keys.PublicKey()
+}
+
+// Subscribe enregistre l'abonnement d'un navigateur pour un utilisateur
+func (s *Service) Subscribe(userID int, req *SubscriptionRequest, userAgent string) (*Subscription, error) {
+	if err := validateEndpoint(req.Endpoint); err != nil {
+		return nil, err
+	}
+
+	p256dh, err := decodeBase64URL(req.Keys.P256dh)
+	if err != nil || len(p256dh) != 65 || p256dh[0] != 0x04 {
+		return nil, fmt.Errorf("clé p256dh invalide")
+	}
+	auth, err := decodeBase64URL(req.Keys.Auth)
+	if err != nil || len(auth) != 16 {
+		return nil, fmt.Errorf("secret auth invalide")
+	}
+
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	subscription := &Subscription{
+		UserID:    userID,
+		Endpoint:  req.Endpoint,
+		P256dh:    req.Keys.P256dh,
+		Auth:      req.Keys.Auth,
+		UserAgent: userAgent,
+	}
+
+	if err := s.repo.Save(subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Unsubscribe supprime l'abonnement d'un navigateur
+func (s *Service) Unsubscribe(userID int, endpoint string) error {
+	if endpoint == "" {
+		return fmt.Errorf("endpoint manquant")
+	}
+	return s.repo.Delete(userID, endpoint)
+}
+
+// Dispatch implémente notifications.Dispatcher : les matchs et messages sont
+// envoyés en Web Push uniquement si l'utilisateur n'a aucun onglet connecté
+func (s *Service) Dispatch(notification *notifications.Notification) {
+	if !pushedTypes[notification.Type] {
+		return
+	}
+	if s.isOnline != nil && s.isOnline(notification.UserID) {
+		return
+	}
+
+	payload := &Payload{
+		Title:          "Matcha",
+		Body:           notification.DisplayMessage(),
+		Type:           string(notification.Type),
+		URL:            "/notifications",
+		NotificationID: notification.ID,
+		FromUserID:     notification.FromID,
+	}
+	if notification.Type == notifications.NotificationMessage || notification.Type == notifications.NotificationMatch {
+		payload.URL = "/chat"
+	}
+
+	// File bornée : si les services push sont trop lents, on abandonne plutôt que
+	// d'accumuler des goroutines
+	select {
+	case s.queue <- pushJob{userID: notification.UserID, payload: payload}:
+	default:
+		fmt.Printf("File push saturée, notification %d abandonnée pour l'utilisateur %d\n", notification.ID, notification.UserID)
+	}
+}
+
+// SendToUser envoie une charge utile à tous les abonnements d'un utilisateur
+func (s *Service) SendToUser(userID int, payload *Payload) {
+	subscriptions, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		fmt.Printf("Erreur récupération abonnements push pour l'utilisateur %d: %v\n", userID, err)
+		return
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		fmt.Printf("Erreur encodage notification push: %v\n", err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		err := s.sendWithRetry(subscription, body)
+		switch {
+		case err == nil:
+			_ = s.repo.MarkUsed(subscription.ID)
+		case errors.Is(err, ErrSubscriptionGone):
+			// Le navigateur s'est désabonné ou l'abonnement a expiré : on l'oublie
+			if err := s.repo.DeleteByEndpoint(subscription.Endpoint); err != nil {
+				fmt.Printf("Erreur suppression abonnement push %d: %v\n", subscription.ID, err)
+			}
+		default:
+			fmt.Printf("Échec envoi push à l'utilisateur %d: %v\n", userID, err)
+		}
+	}
+}
+
+// sendWithRetry réessaie les erreurs transitoires (réseau, 429, 5xx) avec un délai
+// croissant ; le Retry-After demandé par le service push est plafonné à maxRetryWait
+func (s *Service) sendWithRetry(subscription *Subscription, body []byte) error {
+	var err error
+	for attempt := 1; attempt <= pushMaxAttempts; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = s.Send(subscription, body)
+		if err == nil || errors.Is(err, ErrSubscriptionGone) || retryAfter < 0 {
+			return err
+		}
+
+		if attempt < pushMaxAttempts {
+			delay := s.retryDelay * time.Duration(1<<(attempt-1))
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+			if delay > s.maxRetryWait {
+				delay = s.maxRetryWait
+			}
+			time.Sleep(delay)
+		}
+	}
+	return err
+}
+
+// Send chiffre et envoie un message à un abonnement.
+// Le délai retourné vaut -1 si l'erreur est définitive, ou la valeur de Retry-After si fournie.
+func (s *Service) Send(subscription *Subscription, body []byte) (time.Duration, error) {
+	encrypted, err := encryptPayload(body, subscription.P256dh, subscription.Auth)
+	if err != nil {
+		return -1, err
+	}
+
+	endpoint, err := url.Parse(subscription.Endpoint)
+	if err != nil {
+		return -1, fmt.Errorf("endpoint invalide: %w", err)
+	}
+
+	audience := endpoint.Scheme + "://" + endpoint.Host
+	authorization, err := s.keys.authorizationHeader(audience, s.subject, time.Now())
+	if err != nil {
+		return -1, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, subscription.Endpoint, bytes.NewReader(encrypted))
+	if err != nil {
+		return -1, fmt.Errorf("erreur lors de la création de la requête push: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(pushTTL.Seconds())))
+	req.Header.Set("Urgency", "high")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if errors.Is(err, errInternalEndpoint) {
+			return -1, err
+		}
+		return 0, fmt.Errorf("erreur lors de l'envoi push: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return 0, nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return -1, ErrSubscriptionGone
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		var retryAfter time.Duration
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return retryAfter, fmt.Errorf("service push indisponible (statut %d)", resp.StatusCode)
+	default:
+		return -1, fmt.Errorf("requête push rejetée (statut %d)", resp.StatusCode)
+	}
+}
+
+// validateEndpoint vérifie que l'endpoint est une URL HTTPS absolue dont toutes
+// les adresses résolues sont publiques
+func validateEndpoint(endpoint string) error {
+	if endpoint == "" || len(endpoint) > 2048 {
+		return fmt.Errorf("endpoint invalide")
+	}
+
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Hostname() == "" || parsed.Scheme != "https" || parsed.User != nil {
+		return fmt.Errorf("endpoint invalide")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pushRequestLimit)
+	defer cancel()
+
+	addresses, err := net.DefaultResolver.LookupIPAddr(ctx, parsed.Hostname())
+	if err != nil || len(addresses) == 0 {
+		return fmt.Errorf("endpoint invalide: hôte introuvable")
+	}
+	for _, address := range addresses {
+		if !isPublicIP(address.IP) {
+			return errInternalEndpoint
+		}
+	}
+
+	return nil
+}
+
+// isPublicIP refuse la boucle locale, les réseaux privés, le lien local et les
+// adresses non routables, qui exposeraient les services internes au serveur
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		sharedAddressSpace.Contains(ip))
+}
+
+// newPushClient crée le client HTTP des envois push. L'adresse est revérifiée à
+// la connexion, après résolution DNS, pour qu'un endpoint validé à l'abonnement
+// ne puisse pas être redirigé plus tard vers le réseau interne ; les
+// redirections HTTP ne sont pas suivies pour la même raison.
+func newPushClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: pushRequestLimit,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errInternalEndpoint
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   pushRequestLimit,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
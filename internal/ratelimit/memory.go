@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore conserve les seaux en mémoire, pour une instance unique
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	expiresAt time.Time
+}
+
+// NewMemoryStore crée un stockage en mémoire des seaux de jetons
+func NewMemoryStore() Store {
+	return &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+// Take prélève un jeton dans le seau de la clé, créé plein s'il n'existe pas
+func (s *MemoryStore) Take(key string, policy Policy) (Result, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(policy.Limit), updatedAt: now}
+		s.buckets[key] = bucket
+	}
+
+	tokens, result := policy.take(bucket.tokens, now.Sub(bucket.updatedAt))
+	bucket.tokens, bucket.updatedAt, bucket.expiresAt = tokens, now, now.Add(result.Reset)
+	return result, nil
+}
+
+// Purge supprime les seaux redevenus pleins
+func (s *MemoryStore) Purge() (int64, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, bucket := range s.buckets {
+		if bucket.expiresAt.Before(now) {
+			delete(s.buckets, key)
+			purged++
+		}
+	}
+	return purged, nil
+}
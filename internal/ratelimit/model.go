@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// KeyBy désigne ce qui identifie un client pour une politique
+type KeyBy int
+
+const (
+	ByIP   KeyBy = iota // Adresse IP du client
+	ByUser              // Utilisateur connecté, à défaut son adresse IP
+)
+
+// Policy est un seau de jetons : Limit requêtes d'affilée au plus, le seau se
+// remplissant entièrement en Period
+type Policy struct {
+	Name   string // Préfixe des clés ; les routes d'une même politique partagent leur seau
+	Limit  int
+	Period time.Duration
+	By     KeyBy
+}
+
+// Result est l'état du seau après une requête
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // Délai avant que le seau soit de nouveau plein
+	RetryAfter time.Duration // Délai avant le prochain jeton, si la requête est refusée
+}
+
+// Store conserve les seaux de jetons ; Take est atomique pour une même clé
+type Store interface {
+	Take(key string, policy Policy) (Result, error)
+	// Purge supprime les seaux redevenus pleins, équivalents à des seaux absents
+	Purge() (int64, error)
+}
+
+// rate retourne le nombre de jetons rendus par seconde
+func (p Policy) rate() float64 {
+	return float64(p.Limit) / p.Period.Seconds()
+}
+
+// take remplit le seau du temps écoulé depuis la dernière requête, puis y
+// prélève un jeton s'il en reste ; retourne le nouveau nombre de jetons
+func (p Policy) take(tokens float64, elapsed time.Duration) (float64, Result) {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	tokens = math.Min(float64(p.Limit), tokens+elapsed.Seconds()*p.rate())
+
+	result := Result{Limit: p.Limit}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = p.duration(1 - tokens)
+	}
+	result.Remaining = int(math.Floor(tokens))
+	result.Reset = p.duration(float64(p.Limit) - tokens)
+	return tokens, result
+}
+
+// duration retourne le temps nécessaire pour rendre le nombre de jetons donné
+func (p Policy) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / p.rate() * float64(time.Second))
+}
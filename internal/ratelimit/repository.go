@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/database"
+)
+
+// PostgresStore conserve les seaux dans PostgreSQL, pour que plusieurs
+// instances du serveur partagent les mêmes limites
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore crée un stockage PostgreSQL des seaux de jetons
+func NewPostgresStore(db *sql.DB) Store {
+	return &PostgresStore{db: db}
+}
+
+// Take prélève un jeton dans le seau de la clé ; la ligne est verrouillée le
+// temps du calcul, et l'horloge de la base sert de référence à toutes les instances
+func (s *PostgresStore) Take(key string, policy Policy) (Result, error) {
+	var result Result
+
+	err := database.WithTx(s.db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at, expires_at)
+			VALUES ($1, $2, NOW(), NOW())
+			ON CONFLICT (bucket_key) DO NOTHING
+		`, key, float64(policy.Limit))
+		if err != nil {
+			return fmt.Errorf("erreur lors de la création du seau: %w", err)
+		}
+
+		var tokens, elapsed float64
+		err = tx.QueryRow(`
+			SELECT tokens, EXTRACT(EPOCH FROM (NOW() - updated_at))
+			FROM rate_limit_buckets
+			WHERE bucket_key = $1
+			FOR UPDATE
+		`, key).Scan(&tokens, &elapsed)
+		if err != nil {
+			return fmt.Errorf("erreur lors de la lecture du seau: %w", err)
+		}
+
+		tokens, result = policy.take(tokens, time.Duration(elapsed*float64(time.Second)))
+
+		_, err = tx.Exec(`
+			UPDATE rate_limit_buckets
+			SET tokens = $2, updated_at = NOW(), expires_at = NOW() + make_interval(secs => $3)
+			WHERE bucket_key = $1
+		`, key, tokens, result.Reset.Seconds())
+		if err != nil {
+			return fmt.Errorf("erreur lors de la mise à jour du seau: %w", err)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// Purge supprime les seaux redevenus pleins
+func (s *PostgresStore) Purge() (int64, error) {
+	res, err := s.db.Exec("DELETE FROM rate_limit_buckets WHERE expires_at < NOW()")
+	if err != nil {
+		return 0, fmt.Errorf("erreur lors de la purge des seaux: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+)
+
+// purgeEvery est la période de suppression des seaux inutilisés
+const purgeEvery = 5 * time.Minute
+
+// Limiter applique les politiques de débit sur un stockage de seaux
+type Limiter struct {
+	store Store
+}
+
+// NewLimiter crée un limiteur de débit
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{
+		store: store,
+	}
+}
+
+// Take consomme une requête du client identifié par key pour la politique donnée
+func (l *Limiter) Take(policy Policy, key string) (Result, error) {
+	return l.store.Take(policy.Name+":"+key, policy)
+}
+
+// Start lance la purge périodique des seaux inutilisés
+func (l *Limiter) Start() {
+	go l.run()
+}
+
+func (l *Limiter) run() {
+	ticker := time.NewTicker(purgeEvery)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if _, err := l.store.Purge(); err != nil {
+			fmt.Printf("Erreur purge des limites de débit: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// internal/security/csp.go
+package security
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// CSPReportPath reçoit les rapports de violation envoyés par les navigateurs
+const CSPReportPath = "/csp-report"
+
+// maxCSPReportSize borne la taille d'un rapport de violation accepté
+const maxCSPReportSize = 64 << 10
+
+// NewCSPNonce génère le nonce d'une requête, à poser sur ses balises inline
+func NewCSPNonce() (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("erreur lors de la génération du nonce CSP: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(nonce), nil
+}
+
+type cspNonceContextKey struct{}
+
+// WithCSPNonce ajoute le nonce CSP de la requête au contexte
+func WithCSPNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, cspNonceContextKey{}, nonce)
+}
+
+// CSPNonce récupère le nonce CSP de la requête, vide s'il n'y en a pas
+func CSPNonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceContextKey{}).(string)
+	return nonce
+}
+
+// NonceAttr retourne l'attribut nonce (précédé d'une espace) à poser sur les
+// seules balises <script> et <style> inline des pages rendues par le serveur.
+// Les fichiers du site (<script src>, <link>) sont autorisés par 'self' et ne
+// le portent pas.
+func NonceAttr(ctx context.Context) string {
+	nonce := CSPNonce(ctx)
+	if nonce == "" {
+		return ""
+	}
+	return fmt.Sprintf(` nonce="%s"`, nonce)
+}
+
+// cspViolation reprend les champs utiles d'un rapport, au format report-uri
+// comme au format de la Reporting API
+type cspViolation struct {
+	DocumentURI        string `json:"document-uri"`
+	BlockedURI         string `json:"blocked-uri"`
+	EffectiveDirective string `json:"effective-directive"`
+	ViolatedDirective  string `json:"violated-directive"`
+	Disposition        string `json:"disposition"`
+	SourceFile         string `json:"source-file"`
+	LineNumber         int    `json:"line-number"`
+
+	// Noms de champs de la Reporting API (report-to)
+	DocumentURL      string `json:"documentURL"`
+	BlockedURL       string `json:"blockedURL"`
+	ReportDirective  string `json:"effectiveDirective"`
+	ReportSourceFile string `json:"sourceFile"`
+	ReportLineNumber int    `json:"lineNumber"`
+}
+
+// CSPReportHandler collecte les rapports de violation de la politique CSP
+func CSPReportHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCSPReportSize))
+	if err != nil {
+		http.Error(w, "Rapport trop volumineux", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	violations, err := parseCSPReport(body)
+	if err != nil {
+		http.Error(w, "Rapport invalide", http.StatusBadRequest)
+		return
+	}
+
+	for _, v := range violations {
+		directive := v.EffectiveDirective
+		if directive == "" {
+			directive = v.ViolatedDirective
+		}
+		fmt.Printf("CSP VIOLATION (%s): Page: %s, Directive: %s, Ressource: %s, Source: %s:%d, IP: %s\n",
+			v.Disposition, withoutQuery(v.DocumentURI), directive, withoutQuery(v.BlockedURI),
+			withoutQuery(v.SourceFile), v.LineNumber, ClientIP(r))
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseCSPReport lit un rapport report-uri ({"csp-report": {...}}) ou une
+// liste de rapports de la Reporting API ([{"type": "csp-violation", "body": {...}}])
+func parseCSPReport(body []byte) ([]cspViolation, error) {
+	var legacy struct {
+		Report *cspViolation `json:"csp-report"`
+	}
+	if err := json.Unmarshal(body, &legacy); err == nil && legacy.Report != nil {
+		return []cspViolation{*legacy.Report}, nil
+	}
+
+	var reports []struct {
+		Type string       `json:"type"`
+		Body cspViolation `json:"body"`
+	}
+	if err := json.Unmarshal(body, &reports); err != nil {
+		return nil, err
+	}
+
+	violations := []cspViolation{}
+	for _, report := range reports {
+		if report.Type != "csp-violation" {
+			continue
+		}
+		v := report.Body
+		v.DocumentURI, v.BlockedURI = v.DocumentURL, v.BlockedURL
+		v.EffectiveDirective, v.SourceFile, v.LineNumber = v.ReportDirective, v.ReportSourceFile, v.ReportLineNumber
+		violations = append(violations, v)
+	}
+	return violations, nil
+}
+
+// withoutQuery retire la requête et le fragment d'une URL rapportée, qui
+// peuvent contenir des jetons (réinitialisation de mot de passe, vérification)
+func withoutQuery(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	parsed.RawQuery, parsed.Fragment = "", ""
+	return parsed.String()
+}
@@ -0,0 +1,81 @@
+// internal/security/csrf.go
+package security
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Transport du jeton CSRF entre le serveur et les pages
+const (
+	CSRFCookieName = "matcha_csrf"  // Cookie lisible par le JavaScript des pages
+	CSRFHeader     = "X-CSRF-Token" // En-tête renvoyé par fetch
+	CSRFFormField  = "csrf_token"   // Champ des formulaires classiques
+)
+
+// CSRFTokens émet et vérifie des jetons liés à la session : un aléa signé
+// avec l'identifiant de session, qu'un autre site ne peut ni lire ni forger
+type CSRFTokens struct {
+	secret []byte
+}
+
+// NewCSRFTokens crée un émetteur de jetons CSRF à partir d'un secret serveur
+func NewCSRFTokens(secret []byte) *CSRFTokens {
+	return &CSRFTokens{secret: secret}
+}
+
+// Issue émet un jeton pour la session donnée (vide avant la connexion)
+func (t *CSRFTokens) Issue(sessionID string) (string, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("erreur lors de la génération du jeton CSRF: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(nonce)
+	return encoded + "." + t.sign(encoded, sessionID), nil
+}
+
+// Valid indique si le jeton a été émis pour cette session
+func (t *CSRFTokens) Valid(token, sessionID string) bool {
+	nonce, signature, ok := strings.Cut(token, ".")
+	if !ok || nonce == "" {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(t.sign(nonce, sessionID)))
+}
+
+func (t *CSRFTokens) sign(nonce, sessionID string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(nonce + "|" + sessionID))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SameCSRFToken compare deux jetons en temps constant
+func SameCSRFToken(a, b string) bool {
+	return a != "" && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+type csrfContextKey struct{}
+
+// WithCSRFToken ajoute le jeton CSRF de la requête au contexte
+func WithCSRFToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, csrfContextKey{}, token)
+}
+
+// CSRFToken récupère le jeton CSRF de la requête, vide s'il n'y en a pas
+func CSRFToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfContextKey{}).(string)
+	return token
+}
+
+// CSRFMetaTag retourne la balise meta qui expose le jeton aux pages rendues par le serveur
+func CSRFMetaTag(ctx context.Context) string {
+	return fmt.Sprintf(`<meta name="csrf-token" content="%s">`, html.EscapeString(CSRFToken(ctx)))
+}
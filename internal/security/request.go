@@ -0,0 +1,112 @@
+// internal/security/request.go
+package security
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// maxClientIPLength borne la valeur retournée par ClientIP (colonnes VARCHAR(64))
+const maxClientIPLength = 64
+
+// trustedProxies réseaux des reverse proxies dont les en-têtes X-Forwarded-For
+// et X-Real-IP sont crus ; vide, seule l'adresse de connexion fait foi
+var trustedProxies atomic.Pointer[[]*net.IPNet]
+
+// SetTrustedProxies configure les reverse proxies de confiance, donnés par
+// adresse IP ou plage CIDR
+func SetTrustedProxies(proxies []string) error {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		network, err := parseProxy(proxy)
+		if err != nil {
+			return err
+		}
+		networks = append(networks, network)
+	}
+
+	trustedProxies.Store(&networks)
+	return nil
+}
+
+// parseProxy lit une plage CIDR, ou une adresse seule
+func parseProxy(proxy string) (*net.IPNet, error) {
+	proxy = strings.TrimSpace(proxy)
+	if _, network, err := net.ParseCIDR(proxy); err == nil {
+		return network, nil
+	}
+
+	ip := net.ParseIP(proxy)
+	if ip == nil {
+		return nil, fmt.Errorf("proxy de confiance invalide: %q", proxy)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+func isTrustedProxy(ip net.IP) bool {
+	networks := trustedProxies.Load()
+	if networks == nil {
+		return false
+	}
+	for _, network := range *networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP récupère l'IP du client. Les en-têtes de proxy ne sont lus que si la
+// connexion provient d'un proxy de confiance : X-Forwarded-For est alors
+// parcouru de droite à gauche jusqu'à la première adresse qui n'est pas un
+// proxy de confiance, les entrées plus à gauche pouvant être forgées par le client.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	remote := net.ParseIP(host)
+	if remote == nil {
+		// Adresse de connexion non IP (socket Unix...) : conservée telle quelle, bornée
+		return truncateIP(host)
+	}
+	if !isTrustedProxy(remote) {
+		return remote.String()
+	}
+
+	if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+		hops := strings.Split(strings.Join(forwarded, ","), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				// Entrée illisible : on ne peut pas remonter plus loin
+				break
+			}
+			if !isTrustedProxy(ip) {
+				return ip.String()
+			}
+		}
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	return remote.String()
+}
+
+// truncateIP borne une adresse non IP, sans couper de caractère UTF-8
+func truncateIP(value string) string {
+	value = strings.ToValidUTF8(value, "")
+	if len(value) > maxClientIPLength {
+		value = strings.ToValidUTF8(value[:maxClientIPLength], "")
+	}
+	return value
+}
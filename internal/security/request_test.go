@@ -0,0 +1,70 @@
+package security
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{"connexion directe", nil, "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"en-têtes ignorés sans proxy de confiance", nil, "203.0.113.7:5000",
+			map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Real-IP": "1.2.3.4", "X-Client-IP": "1.2.3.4"}, "203.0.113.7"},
+		{"en-têtes ignorés depuis un proxy non configuré", []string{"10.0.0.0/8"}, "192.168.1.1:5000",
+			map[string]string{"X-Forwarded-For": "1.2.3.4"}, "192.168.1.1"},
+		{"X-Forwarded-For depuis un proxy de confiance", []string{"10.0.0.0/8"}, "10.0.0.2:5000",
+			map[string]string{"X-Forwarded-For": "198.51.100.9"}, "198.51.100.9"},
+		{"entrée forgée à gauche ignorée", []string{"10.0.0.0/8"}, "10.0.0.2:5000",
+			map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.9"}, "198.51.100.9"},
+		{"chaîne de proxies de confiance", []string{"10.0.0.0/8", "172.16.0.5"}, "10.0.0.2:5000",
+			map[string]string{"X-Forwarded-For": "198.51.100.9, 172.16.0.5, 10.0.0.3"}, "198.51.100.9"},
+		{"entrée illisible : repli sur X-Real-IP", []string{"10.0.0.0/8"}, "10.0.0.2:5000",
+			map[string]string{"X-Forwarded-For": "<script>", "X-Real-IP": "198.51.100.9"}, "198.51.100.9"},
+		{"X-Real-IP depuis un proxy de confiance", []string{"10.0.0.2"}, "10.0.0.2:5000",
+			map[string]string{"X-Real-IP": "198.51.100.9"}, "198.51.100.9"},
+		{"X-Real-IP invalide", []string{"10.0.0.2"}, "10.0.0.2:5000",
+			map[string]string{"X-Real-IP": strings.Repeat("a", 300)}, "10.0.0.2"},
+		{"X-Client-IP jamais lu", []string{"10.0.0.2"}, "10.0.0.2:5000",
+			map[string]string{"X-Client-IP": "198.51.100.9"}, "10.0.0.2"},
+		{"IPv6 normalisée", nil, "[2001:DB8::0001]:5000", nil, "2001:db8::1"},
+		{"IPv6 derrière un proxy", []string{"::1"}, "[::1]:5000",
+			map[string]string{"X-Forwarded-For": "2001:db8::2"}, "2001:db8::2"},
+		{"adresse de connexion non IP bornée", nil, strings.Repeat("x", 100), nil, strings.Repeat("x", 64)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetTrustedProxies(tt.proxies); err != nil {
+				t.Fatalf("SetTrustedProxies: %v", err)
+			}
+			t.Cleanup(func() { SetTrustedProxies(nil) })
+
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for key, value := range tt.headers {
+				r.Header.Set(key, value)
+			}
+
+			if got := ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTrustedProxiesRejectsInvalidEntries(t *testing.T) {
+	t.Cleanup(func() { SetTrustedProxies(nil) })
+
+	for _, proxy := range []string{"", "proxy.local", "10.0.0.0/33", "1.2.3"} {
+		if err := SetTrustedProxies([]string{proxy}); err == nil {
+			t.Errorf("SetTrustedProxies(%q) accepté, attendu une erreur", proxy)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package security
 import (
 	"database/sql"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
 )
@@ -214,9 +215,26 @@ func containsHTML(input string) bool {
 	return matched
 }
 
-// LogSuspiciousActivity enregistre les tentatives d'injection
-func LogSuspiciousActivity(userID int, input string, endpoint string) {
-	// Dans un vrai projet, enregistrer dans un système de logs sécurisé
+// SuspiciousActivityRecorder enregistre les tentatives d'injection dans un
+// journal durable (le journal d'audit)
+type SuspiciousActivityRecorder interface {
+	RecordSuspicious(r *http.Request, userID int, input, endpoint string)
+}
+
+var suspiciousRecorder SuspiciousActivityRecorder
+
+// SetSuspiciousActivityRecorder branche le journal des tentatives d'injection
+func SetSuspiciousActivityRecorder(recorder SuspiciousActivityRecorder) {
+	suspiciousRecorder = recorder
+}
+
+// LogSuspiciousActivity enregistre les tentatives d'injection ; sans journal
+// branché, elles sont seulement affichées
+func LogSuspiciousActivity(r *http.Request, userID int, input string, endpoint string) {
+	if suspiciousRecorder != nil {
+		suspiciousRecorder.RecordSuspicious(r, userID, input, endpoint)
+		return
+	}
 	fmt.Printf("SECURITY ALERT: Tentative d'injection détectée - User: %d, Endpoint: %s, Input: %s\n",
 		userID, endpoint, input[:min(50, len(input))])
 }
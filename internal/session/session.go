@@ -7,34 +7,70 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/cduffaut/matcha/internal/models"
 )
 
+// StatusCheckEvery est l'ancienneté au-delà de laquelle une session relit le
+// rôle et l'état du compte : une sanction prise par une autre instance ou par
+// matchactl s'applique dans ce délai
+const StatusCheckEvery = 30 * time.Second
+
 // Session représente une session utilisateur
 type Session struct {
 	UserID    int
 	Username  string
+	Role      string
 	ExpiresAt time.Time
+	// Restricted limite la session à la contestation d'une suspension ou d'un bannissement
+	Restricted bool
+
+	checkedAt time.Time // Dernière lecture du compte en base
 }
 
-// Manager gère les sessions utilisateur
+// AccountLookup relit un utilisateur en base
+type AccountLookup func(userID int) (*models.User, error)
+
+// Manager gère les sessions utilisateur ; les sessions sont partagées entre les
+// requêtes et les actions de modération, d'où le verrou
 type Manager struct {
 	CookieName string
-	Sessions   map[string]Session
+	mu         sync.RWMutex
+	sessions   map[string]Session
+	lookup     AccountLookup
 }
 
 // NewManager crée un nouveau gestionnaire de session
 func NewManager(cookieName string) *Manager {
 	return &Manager{
 		CookieName: cookieName,
-		Sessions:   make(map[string]Session),
+		sessions:   make(map[string]Session),
 	}
 }
 
+// SetAccountLookup active la revérification périodique des sessions : un compte
+// suspendu ou banni perd sa session complète, un compte rétabli sa session
+// restreinte, et un changement de rôle est repris
+func (m *Manager) SetAccountLookup(lookup AccountLookup) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lookup = lookup
+}
+
 // CreateSession crée une nouvelle session pour un utilisateur
 func (m *Manager) CreateSession(w http.ResponseWriter, user *models.User) (string, error) {
+	return m.createSession(w, user, false, 24*time.Hour) // Session de 24 heures
+}
+
+// CreateRestrictedSession crée une session d'une heure limitée à la page de
+// contestation, pour un compte suspendu ou banni
+func (m *Manager) CreateRestrictedSession(w http.ResponseWriter, user *models.User) (string, error) {
+	return m.createSession(w, user, true, time.Hour)
+}
+
+func (m *Manager) createSession(w http.ResponseWriter, user *models.User, restricted bool, duration time.Duration) (string, error) {
 
 	// Générer un token de session
 	sessionToken, err := generateRandomToken(32)
@@ -44,13 +80,18 @@ func (m *Manager) CreateSession(w http.ResponseWriter, user *models.User) (strin
 
 	// Créer la session
 	session := Session{
-		UserID:    user.ID,
-		Username:  user.Username,
-		ExpiresAt: time.Now().Add(24 * time.Hour), // Session de 24 heures
+		UserID:     user.ID,
+		Username:   user.Username,
+		Role:       user.Role,
+		ExpiresAt:  time.Now().Add(duration),
+		Restricted: restricted,
+		checkedAt:  time.Now(),
 	}
 
 	// Stocker la session
-	m.Sessions[sessionToken] = session
+	m.mu.Lock()
+	m.sessions[sessionToken] = session
+	m.mu.Unlock()
 
 	// CORRECTION : Créer le cookie avec les paramètres corrects
 	cookie := http.Cookie{
@@ -71,10 +112,6 @@ func (m *Manager) CreateSession(w http.ResponseWriter, user *models.User) (strin
 
 // GetSession récupère une session à partir d'une requête
 func (m *Manager) GetSession(r *http.Request) (*Session, error) {
-	for _, cookie := range r.Cookies() {
-		fmt.Printf("  - %s = %s\n", cookie.Name, cookie.Value)
-	}
-
 	// Récupérer le cookie de session
 	cookie, err := r.Cookie(m.CookieName)
 	if err != nil {
@@ -82,20 +119,102 @@ func (m *Manager) GetSession(r *http.Request) (*Session, error) {
 	}
 
 	// Récupérer la session
-	session, exists := m.Sessions[cookie.Value]
+	m.mu.RLock()
+	session, exists := m.sessions[cookie.Value]
+	m.mu.RUnlock()
 	if !exists {
-		return nil, fmt.Errorf("session invalide: %s", cookie.Value)
+		return nil, fmt.Errorf("session invalide")
 	}
 
 	// Vérifier si la session a expiré
 	if time.Now().After(session.ExpiresAt) {
-		delete(m.Sessions, cookie.Value)
+		m.mu.Lock()
+		delete(m.sessions, cookie.Value)
+		m.mu.Unlock()
 		return nil, fmt.Errorf("session expirée")
 	}
 
+	if err := m.revalidate(cookie.Value, &session); err != nil {
+		return nil, err
+	}
+
 	return &session, nil
 }
 
+// revalidate relit le compte d'une session vérifiée depuis plus de StatusCheckEvery
+func (m *Manager) revalidate(token string, session *Session) error {
+	m.mu.RLock()
+	lookup := m.lookup
+	m.mu.RUnlock()
+
+	now := time.Now()
+	if lookup == nil || now.Sub(session.checkedAt) < StatusCheckEvery {
+		return nil
+	}
+
+	user, err := lookup(session.UserID)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la vérification du compte: %w", err)
+	}
+
+	// La sanction a été prononcée ou levée depuis l'ouverture de la session
+	if user.IsLocked(now) != session.Restricted {
+		m.mu.Lock()
+		delete(m.sessions, token)
+		m.mu.Unlock()
+		return fmt.Errorf("session révoquée : l'état du compte a changé")
+	}
+
+	session.Role = user.Role
+	session.checkedAt = now
+
+	m.mu.Lock()
+	if _, exists := m.sessions[token]; exists {
+		m.sessions[token] = *session
+	}
+	m.mu.Unlock()
+
+	return nil
+}
+
+// UpdateRole applique un changement de rôle aux sessions ouvertes de l'utilisateur
+func (m *Manager) UpdateRole(userID int, role string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for token, session := range m.sessions {
+		if session.UserID == userID {
+			session.Role = role
+			m.sessions[token] = session
+		}
+	}
+}
+
+// RevokeUser ferme toutes les sessions ouvertes d'un utilisateur (suspension, bannissement)
+func (m *Manager) RevokeUser(userID int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for token, session := range m.sessions {
+		if session.UserID == userID {
+			delete(m.sessions, token)
+		}
+	}
+}
+
+// RevokeRestricted ferme les sessions restreintes d'un utilisateur dont le compte
+// est rétabli ; il retrouve un accès complet en se reconnectant
+func (m *Manager) RevokeRestricted(userID int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for token, session := range m.sessions {
+		if session.UserID == userID && session.Restricted {
+			delete(m.sessions, token)
+		}
+	}
+}
+
 // DestroySession détruit une session
 func (m *Manager) DestroySession(w http.ResponseWriter, r *http.Request) error {
 	// Récupérer le cookie de session
@@ -105,7 +224,9 @@ func (m *Manager) DestroySession(w http.ResponseWriter, r *http.Request) error {
 	}
 
 	// Supprimer la session
-	delete(m.Sessions, cookie.Value)
+	m.mu.Lock()
+	delete(m.sessions, cookie.Value)
+	m.mu.Unlock()
 
 	// Expirer le cookie
 	expiredCookie := http.Cookie{
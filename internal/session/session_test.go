@@ -0,0 +1,183 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/models"
+)
+
+// login ouvre une session et retourne une requête portant son cookie
+func login(t *testing.T, m *Manager, user *models.User, restricted bool) *http.Request {
+	t.Helper()
+	w := httptest.NewRecorder()
+
+	var err error
+	if restricted {
+		_, err = m.CreateRestrictedSession(w, user)
+	} else {
+		_, err = m.CreateSession(w, user)
+	}
+	if err != nil {
+		t.Fatalf("création de session: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+func TestManagerConcurrentAccess(t *testing.T) {
+	m := NewManager("matcha_session")
+	users := []*models.User{{ID: 1, Role: models.RoleUser}, {ID: 2, Role: models.RoleUser}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		user := users[i%len(users)]
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			if i%3 == 0 {
+				m.CreateRestrictedSession(w, user)
+			} else {
+				m.CreateSession(w, user)
+			}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for _, cookie := range w.Result().Cookies() {
+				r.AddCookie(cookie)
+			}
+
+			m.GetSession(r)
+			m.UpdateRole(user.ID, models.RoleModerator)
+			m.RevokeRestricted(user.ID)
+			if i%5 == 0 {
+				m.RevokeUser(user.ID)
+			}
+			m.DestroySession(httptest.NewRecorder(), r)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestManagerRevocations(t *testing.T) {
+	m := NewManager("matcha_session")
+	alice := &models.User{ID: 1, Role: models.RoleUser}
+	bob := &models.User{ID: 2, Role: models.RoleUser}
+
+	aliceFull := login(t, m, alice, false)
+	aliceRestricted := login(t, m, alice, true)
+	bobFull := login(t, m, bob, false)
+
+	m.UpdateRole(alice.ID, models.RoleModerator)
+	if session, err := m.GetSession(aliceFull); err != nil || session.Role != models.RoleModerator {
+		t.Errorf("session = %+v, %v, attendu le rôle moderator", session, err)
+	}
+
+	m.RevokeRestricted(alice.ID)
+	if _, err := m.GetSession(aliceRestricted); err == nil {
+		t.Error("session restreinte toujours valide après RevokeRestricted")
+	}
+	if _, err := m.GetSession(aliceFull); err != nil {
+		t.Errorf("session complète fermée par RevokeRestricted: %v", err)
+	}
+
+	m.RevokeUser(alice.ID)
+	if _, err := m.GetSession(aliceFull); err == nil {
+		t.Error("session toujours valide après RevokeUser")
+	}
+	if _, err := m.GetSession(bobFull); err != nil {
+		t.Errorf("session d'un autre utilisateur fermée: %v", err)
+	}
+}
+
+// age vieillit la dernière vérification de toutes les sessions
+func age(m *Manager, by time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for token, session := range m.sessions {
+		session.checkedAt = session.checkedAt.Add(-by)
+		m.sessions[token] = session
+	}
+}
+
+func TestGetSessionRevalidatesAccount(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		restricted bool
+		stored     models.User // État du compte en base lors de la vérification
+		lookupErr  error
+		wantErr    bool
+		wantKept   bool // La session survit à la vérification
+		wantRole   string
+	}{
+		{"compte actif", false, models.User{Role: models.RoleUser, AccountStatus: models.AccountActive}, nil, false, true, models.RoleUser},
+		{"compte averti", false, models.User{Role: models.RoleUser, AccountStatus: models.AccountWarned}, nil, false, true, models.RoleUser},
+		{"rôle modifié ailleurs", false, models.User{Role: models.RoleAdmin, AccountStatus: models.AccountActive}, nil, false, true, models.RoleAdmin},
+		{"banni ailleurs", false, models.User{Role: models.RoleUser, AccountStatus: models.AccountBanned}, nil, true, false, ""},
+		{"suspendu ailleurs", false, models.User{Role: models.RoleUser, AccountStatus: models.AccountSuspended}, nil, true, false, ""},
+		{"suspension échue", false, models.User{Role: models.RoleUser, AccountStatus: models.AccountSuspended, SuspendedUntil: &past}, nil, false, true, models.RoleUser},
+		{"session restreinte toujours bannie", true, models.User{Role: models.RoleUser, AccountStatus: models.AccountBanned}, nil, false, true, models.RoleUser},
+		{"session restreinte d'un compte rétabli", true, models.User{Role: models.RoleUser, AccountStatus: models.AccountActive}, nil, true, false, ""},
+		{"base indisponible", false, models.User{}, errors.New("connexion perdue"), true, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewManager("matcha_session")
+			user := &models.User{ID: 1, Role: models.RoleUser}
+			r := login(t, m, user, tt.restricted)
+
+			lookups := 0
+			m.SetAccountLookup(func(userID int) (*models.User, error) {
+				lookups++
+				if tt.lookupErr != nil {
+					return nil, tt.lookupErr
+				}
+				stored := tt.stored
+				stored.ID = userID
+				return &stored, nil
+			})
+
+			// Session tout juste vérifiée : pas de lecture en base
+			if _, err := m.GetSession(r); err != nil || lookups != 0 {
+				t.Fatalf("GetSession = %v avec %d lectures, attendu aucune lecture", err, lookups)
+			}
+
+			age(m, StatusCheckEvery)
+			session, err := m.GetSession(r)
+			if lookups != 1 {
+				t.Fatalf("%d lectures du compte, attendu 1", lookups)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetSession = %v, erreur attendue : %v", err, tt.wantErr)
+			}
+			if err == nil && session.Role != tt.wantRole {
+				t.Errorf("rôle = %q, attendu %q", session.Role, tt.wantRole)
+			}
+
+			m.mu.RLock()
+			kept := len(m.sessions) == 1
+			m.mu.RUnlock()
+			if kept != tt.wantKept {
+				t.Errorf("session conservée = %v, attendu %v", kept, tt.wantKept)
+			}
+
+			// Une vérification réussie repousse la suivante
+			if err == nil {
+				m.GetSession(r)
+				if lookups != 1 {
+					t.Errorf("%d lectures du compte, attendu 1 jusqu'à la prochaine échéance", lookups)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// browseSessionSize borne le nombre de profils figés dans un classement
+	browseSessionSize = 500
+	// maxBrowseSessionsPerUser borne le nombre de classements conservés par utilisateur (onglets)
+	maxBrowseSessionsPerUser = 5
+)
+
+// ErrCursorExpired est retournée pour un curseur inconnu, expiré ou appartenant à un autre utilisateur
+var ErrCursorExpired = fmt.Errorf("curseur de pagination invalide ou expiré")
+
+// BrowsePage est une page de résultats ; NextCursor est vide sur la dernière page
+type BrowsePage struct {
+	Results    []SuggestedProfileResult
+	NextCursor string
+}
+
+// browseSession est le classement figé à la première page d'une navigation
+type browseSession struct {
+	userID    int
+	results   []SuggestedProfileResult
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+// BrowseSessions conserve en mémoire les classements de suggestions et de
+// recherche, pour que les pages suivantes soient servies sans doublon ni
+// profil sauté même si les scores évoluent entre deux requêtes
+type BrowseSessions struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*browseSession
+}
+
+// NewBrowseSessions crée le stockage des classements ; ttl est renouvelé à chaque page servie
+func NewBrowseSessions(ttl time.Duration) *BrowseSessions {
+	return &BrowseSessions{
+		ttl:      ttl,
+		sessions: make(map[string]*browseSession),
+	}
+}
+
+// Create fige un classement et retourne le curseur de sa première page
+func (b *BrowseSessions) Create(userID int, results []SuggestedProfileResult) (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("erreur lors de la génération de l'identifiant de navigation: %w", err)
+	}
+	id := hex.EncodeToString(token)
+
+	if len(results) > browseSessionSize {
+		results = results[:browseSessionSize]
+	}
+
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.purge(userID, now)
+	b.sessions[id] = &browseSession{
+		userID:    userID,
+		results:   results,
+		createdAt: now,
+		expiresAt: now.Add(b.ttl),
+	}
+
+	return encodeCursor(id, 0), nil
+}
+
+// Page retourne au plus limit résultats à partir du curseur. Les résultats sont
+// copiés, raisons comprises : l'appelant peut les modifier sans toucher au classement
+func (b *BrowseSessions) Page(userID int, cursor string, limit int) (*BrowsePage, error) {
+	id, position, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, ErrCursorExpired
+	}
+
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	session, ok := b.sessions[id]
+	if !ok || session.userID != userID || now.After(session.expiresAt) || position > len(session.results) {
+		return nil, ErrCursorExpired
+	}
+	session.expiresAt = now.Add(b.ttl)
+
+	end := position + limit
+	if end > len(session.results) {
+		end = len(session.results)
+	}
+
+	results := make([]SuggestedProfileResult, end-position)
+	copy(results, session.results[position:end])
+	for i := range results {
+		results[i].Reasons = append([]MatchReason(nil), results[i].Reasons...)
+	}
+
+	page := &BrowsePage{Results: results}
+	if end < len(session.results) {
+		page.NextCursor = encodeCursor(id, end)
+	}
+
+	return page, nil
+}
+
+// purge supprime les classements expirés et, au-delà de maxBrowseSessionsPerUser,
+// les plus anciens de l'utilisateur ; b.mu doit être verrouillé
+func (b *BrowseSessions) purge(userID int, now time.Time) {
+	var oldestID string
+	var oldest time.Time
+	count := 0
+
+	for id, session := range b.sessions {
+		if now.After(session.expiresAt) {
+			delete(b.sessions, id)
+			continue
+		}
+		if session.userID != userID {
+			continue
+		}
+		count++
+		if oldestID == "" || session.createdAt.Before(oldest) {
+			oldestID, oldest = id, session.createdAt
+		}
+	}
+
+	if count >= maxBrowseSessionsPerUser {
+		delete(b.sessions, oldestID)
+	}
+}
+
+// encodeCursor rend opaque la position dans un classement
+func encodeCursor(id string, position int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id + ":" + strconv.Itoa(position)))
+}
+
+func decodeCursor(cursor string) (string, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, err
+	}
+
+	id, positionStr, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return "", 0, fmt.Errorf("curseur mal formé")
+	}
+
+	position, err := strconv.Atoi(positionStr)
+	if err != nil || position < 0 {
+		return "", 0, fmt.Errorf("curseur mal formé")
+	}
+
+	return id, position, nil
+}
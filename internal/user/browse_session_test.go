@@ -0,0 +1,73 @@
+package user
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBrowseSessionsPageReturnsCopy(t *testing.T) {
+	sessions := NewBrowseSessions(time.Minute)
+	cursor, err := sessions.Create(1, []SuggestedProfileResult{
+		{Profile: &Profile{UserID: 10}, Reasons: []MatchReason{{Code: ReasonVeryPopular}}},
+		{Profile: &Profile{UserID: 11}, Reasons: []MatchReason{{Code: ReasonSimilarToLikes}}},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	page, err := sessions.Page(1, cursor, 2)
+	if err != nil {
+		t.Fatalf("Page: %v", err)
+	}
+	page.Results[0].Reasons[0].Message = "modifié"
+	page.Results[1] = SuggestedProfileResult{}
+
+	again, err := sessions.Page(1, cursor, 2)
+	if err != nil {
+		t.Fatalf("Page: %v", err)
+	}
+	if again.Results[0].Reasons[0].Message == "modifié" {
+		t.Error("la modification des raisons d'une page a atteint le classement figé")
+	}
+	if again.Results[1].Profile == nil || again.Results[1].Profile.UserID != 11 {
+		t.Error("la modification d'une page a atteint le classement figé")
+	}
+}
+
+func TestBrowseSessionsConcurrentLocalization(t *testing.T) {
+	sessions := NewBrowseSessions(time.Minute)
+	cursor, err := sessions.Create(1, []SuggestedProfileResult{
+		{Profile: &Profile{UserID: 10}, Reasons: []MatchReason{{Code: ReasonVeryPopular, Params: map[string]interface{}{"fame_rating": 90}}}},
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, lang := range []string{"fr", "en", "fr", "en"} {
+		wg.Add(1)
+		go func(lang string) {
+			defer wg.Done()
+			page, err := sessions.Page(1, cursor, 1)
+			if err != nil {
+				t.Errorf("Page: %v", err)
+				return
+			}
+			LocalizeResults(page.Results, lang)
+		}(lang)
+	}
+	wg.Wait()
+}
+
+func TestBrowseSessionsRejectsOtherUser(t *testing.T) {
+	sessions := NewBrowseSessions(time.Minute)
+	cursor, err := sessions.Create(1, []SuggestedProfileResult{{Profile: &Profile{UserID: 10}}})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if _, err := sessions.Page(2, cursor, 1); err != ErrCursorExpired {
+		t.Errorf("Page pour un autre utilisateur = %v, attendu ErrCursorExpired", err)
+	}
+}
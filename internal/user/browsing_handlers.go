@@ -2,6 +2,7 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -11,6 +12,9 @@ import (
 	"github.com/cduffaut/matcha/internal/session"
 )
 
+// maxPageSize borne le nombre de profils demandés par page
+const maxPageSize = 100
+
 // BrowsingHandlers gère les requêtes HTTP pour la navigation
 type BrowsingHandlers struct {
 	browsingService *BrowsingService
@@ -34,29 +38,28 @@ func (h *BrowsingHandlers) GetSuggestionsHandler(w http.ResponseWriter, r *http.
 
 	// Récupérer les paramètres de pagination
 	limit := 20
-	offset := 0
 
 	limitStr := r.URL.Query().Get("limit")
 	if limitStr != "" {
 		limitParsed, err := strconv.Atoi(limitStr)
-		if err == nil && limitParsed > 0 {
+		if err == nil && limitParsed > 0 && limitParsed <= maxPageSize {
 			limit = limitParsed
 		}
 	}
 
-	offsetStr := r.URL.Query().Get("offset")
-	if offsetStr != "" {
-		offsetParsed, err := strconv.Atoi(offsetStr)
-		if err == nil && offsetParsed >= 0 {
-			offset = offsetParsed
-		}
-	}
+	// Curseur opaque de la page suivante, absent pour la première page
+	cursor := r.URL.Query().Get("cursor")
 
 	// Récupérer les suggestions
-	suggestions, err := h.browsingService.GetSuggestions(userSession.UserID, limit, offset)
+	page, err := h.browsingService.GetSuggestions(userSession.UserID, limit, cursor)
 	if err != nil {
 		fmt.Printf("Error in GetSuggestions: %v\n", err)
 
+		if errors.Is(err, ErrCursorExpired) {
+			writeCursorExpired(w)
+			return
+		}
+
 		// CORRECTION : Gérer spécifiquement l'erreur de profil incomplet
 		if strings.Contains(err.Error(), "votre profil doit être complété") {
 			w.Header().Set("Content-Type", "application/json")
@@ -75,9 +78,11 @@ func (h *BrowsingHandlers) GetSuggestionsHandler(w http.ResponseWriter, r *http.
 	}
 
 	// Répondre avec les suggestions
+	LocalizeResults(page.Results, PreferredLanguage(r.Header.Get("Accept-Language")))
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"suggestions":        suggestions,
+		"suggestions":        page.Results,
+		"next_cursor":        page.NextCursor,
 		"profile_incomplete": false,
 	}); err != nil {
 		fmt.Printf("Error encoding JSON response: %v\n", err)
@@ -86,7 +91,6 @@ func (h *BrowsingHandlers) GetSuggestionsHandler(w http.ResponseWriter, r *http.
 	}
 }
 
-// SearchProfilesHandler recherche des profils selon des critères
 // SearchProfilesHandler recherche des profils selon des critères
 func (h *BrowsingHandlers) SearchProfilesHandler(w http.ResponseWriter, r *http.Request) {
 	// Récupérer la session
@@ -170,29 +174,28 @@ func (h *BrowsingHandlers) SearchProfilesHandler(w http.ResponseWriter, r *http.
 
 	// Pagination
 	limit := 20
-	offset := 0
 
 	limitStr := r.URL.Query().Get("limit")
 	if limitStr != "" {
 		limitParsed, err := strconv.Atoi(limitStr)
-		if err == nil && limitParsed > 0 {
+		if err == nil && limitParsed > 0 && limitParsed <= maxPageSize {
 			limit = limitParsed
 		}
 	}
 
-	offsetStr := r.URL.Query().Get("offset")
-	if offsetStr != "" {
-		offsetParsed, err := strconv.Atoi(offsetStr)
-		if err == nil && offsetParsed >= 0 {
-			offset = offsetParsed
-		}
-	}
+	// Curseur opaque de la page suivante, absent pour la première page
+	cursor := r.URL.Query().Get("cursor")
 
 	// Rechercher les profils
-	results, err := h.browsingService.SearchProfiles(userSession.UserID, options, limit, offset)
+	page, err := h.browsingService.SearchProfiles(userSession.UserID, options, limit, cursor)
 	if err != nil {
 		fmt.Printf("Error in SearchProfiles: %v\n", err)
 
+		if errors.Is(err, ErrCursorExpired) {
+			writeCursorExpired(w)
+			return
+		}
+
 		// CORRECTION : Gérer spécifiquement l'erreur de profil incomplet
 		if strings.Contains(err.Error(), "votre profil doit être complété") {
 			w.Header().Set("Content-Type", "application/json")
@@ -211,9 +214,11 @@ func (h *BrowsingHandlers) SearchProfilesHandler(w http.ResponseWriter, r *http.
 	}
 
 	// Répondre avec les résultats
+	LocalizeResults(page.Results, PreferredLanguage(r.Header.Get("Accept-Language")))
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"results":            results,
+		"results":            page.Results,
+		"next_cursor":        page.NextCursor,
 		"profile_incomplete": false,
 	}); err != nil {
 		fmt.Printf("Error encoding JSON response: %v\n", err)
@@ -222,6 +227,16 @@ func (h *BrowsingHandlers) SearchProfilesHandler(w http.ResponseWriter, r *http.
 	}
 }
 
+// writeCursorExpired indique au client de recommencer la navigation depuis la première page
+func writeCursorExpired(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusGone)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error":          "La session de navigation a expiré, veuillez recharger les résultats",
+		"cursor_expired": true,
+	})
+}
+
 func (h *BrowsingHandlers) BrowsePageHandler(w http.ResponseWriter, r *http.Request) {
 	// Récupérer la session
 	_, ok := session.FromContext(r.Context())
@@ -332,6 +347,7 @@ func (h *BrowsingHandlers) BrowsePageHandler(w http.ResponseWriter, r *http.Requ
                 </div>
             </div>
             
+			<script src="/static/js/csrf.js"></script>
 			<script src="/static/js/global-error-handler.js"></script>
             <script src="/static/js/browse.js?v=%s"></script>
 			<script src="/static/js/user_status.js"></script>
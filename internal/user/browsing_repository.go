@@ -0,0 +1,504 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/lib/pq"
+)
+
+// kmPerDegreeLat longueur approximative d'un degré de latitude
+const kmPerDegreeLat = 111.045
+
+// hasEarthdistance indique si l'extension earthdistance (et son index GiST) est disponible
+func (r *PostgresProfileRepository) hasEarthdistance() bool {
+	r.spatialOnce.Do(func() {
+		err := r.db.QueryRow(
+			"SELECT EXISTS(SELECT 1 FROM pg_extension WHERE extname = 'earthdistance')",
+		).Scan(&r.earthdistance)
+		if err != nil {
+			fmt.Printf("Détection de earthdistance impossible, recherche par boîte englobante: %v\n", err)
+			r.earthdistance = false
+		}
+	})
+	return r.earthdistance
+}
+
+// visibilityConditions écartent les profils bloqués ou masqués par la modération ;
+// $1 est l'utilisateur qui consulte, p et u le profil et le compte du candidat
+var visibilityConditions = []string{
+	// Blocages dans les deux sens
+	`NOT EXISTS (
+		SELECT 1 FROM user_blocks b
+		WHERE (b.blocker_id = $1 AND b.blocked_id = p.user_id)
+		OR (b.blocker_id = p.user_id AND b.blocked_id = $1)
+	)`,
+	// Comptes masqués par la modération : bannis, shadow-bannis, suspendus
+	"u.account_status NOT IN ('banned', 'shadow_banned')",
+	"NOT (u.account_status = 'suspended' AND (u.suspended_until IS NULL OR u.suspended_until > NOW() AT TIME ZONE 'UTC'))",
+	// Comptes jugés à risque, en attente d'examen par un modérateur
+	"NOT EXISTS (SELECT 1 FROM user_risk rk WHERE rk.user_id = p.user_id AND rk.hidden)",
+}
+
+// FindWithinRadius présélectionne en une requête les profils proposables à un
+// utilisateur dans un rayon autour d'un point (radiusKm = 0 : sans limite).
+// Le rayon s'appuie sur l'index GiST earthdistance, ou à défaut sur une boîte
+// englobante ; la distance exacte de chaque candidat est ensuite vérifiée.
+func (r *PostgresProfileRepository) FindWithinRadius(lat, lon, radiusKm float64, filter CandidateFilter) ([]*Candidate, error) {
+	query, args := r.candidateQuery(lat, lon, radiusKm, filter)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la recherche des candidats: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []*Candidate
+	byUserID := make(map[int]*Profile)
+	var userIDs []int64
+
+	for rows.Next() {
+		profile := &Profile{}
+		user := &models.User{}
+		var bio, locName sql.NullString
+		var birthDate sql.NullTime
+		var profileLat, profileLong sql.NullFloat64
+		var candidateMaxDistance float64
+
+		err := rows.Scan(
+			&profile.UserID,
+			&profile.Gender,
+			&profile.SexualPreference,
+			pq.Array(&profile.InterestedIn),
+			&bio,
+			&birthDate,
+			&profile.FameRating,
+			&profileLat,
+			&profileLong,
+			&locName,
+			&profile.CreatedAt,
+			&profile.UpdatedAt,
+			&user.Username,
+			&user.FirstName,
+			&user.LastName,
+			&user.IsVerified,
+			&user.CreatedAt,
+			&candidateMaxDistance,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un candidat: %w", err)
+		}
+
+		profile.Biography = bio.String
+		profile.LocationName = locName.String
+		profile.Latitude = profileLat.Float64
+		profile.Longitude = profileLong.Float64
+		if birthDate.Valid {
+			profile.BirthDate = &birthDate.Time
+		}
+		profile.FirstName = user.FirstName
+		profile.LastName = user.LastName
+		user.ID = profile.UserID
+
+		// Les coins de la boîte englobante dépassent le rayon demandé
+		distance := calculateDistance(lat, lon, profile.Latitude, profile.Longitude)
+		if radiusKm > 0 && distance > radiusKm {
+			continue
+		}
+		// Distance maximale choisie par le candidat
+		if candidateMaxDistance > 0 && distance > candidateMaxDistance {
+			continue
+		}
+
+		candidates = append(candidates, &Candidate{Profile: profile, User: user, Distance: distance})
+		byUserID[profile.UserID] = profile
+		userIDs = append(userIDs, int64(profile.UserID))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erreur lors du parcours des candidats: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	// Tags et photos chargés en une requête chacun pour tout le lot
+	if err := r.loadCandidateTags(userIDs, byUserID); err != nil {
+		return nil, err
+	}
+	if err := r.loadCandidatePhotos(userIDs, byUserID); err != nil {
+		return nil, err
+	}
+
+	return candidates, nil
+}
+
+// candidateQuery construit la requête de présélection de FindWithinRadius
+func (r *PostgresProfileRepository) candidateQuery(lat, lon, radiusKm float64, filter CandidateFilter) (string, []interface{}) {
+	conditions := []string{
+		"p.user_id <> $1",
+		// Profil complet
+		"COALESCE(p.gender, '') <> ''",
+		"cardinality(p.interested_in) > 0",
+		"TRIM(COALESCE(p.biography, '')) <> ''",
+		"p.birth_date IS NOT NULL",
+		"EXISTS (SELECT 1 FROM user_tags ut WHERE ut.user_id = p.user_id)",
+		"EXISTS (SELECT 1 FROM user_photos ph WHERE ph.user_id = p.user_id AND ph.is_profile = TRUE)",
+		// Déjà liké (exclut aussi les matchs, qui supposent un like de l'utilisateur)
+		"NOT EXISTS (SELECT 1 FROM user_likes l WHERE l.liker_id = $1 AND l.liked_id = p.user_id)",
+		// Intérêts mutuels : chacun appartient aux genres recherchés par l'autre (IsMutuallyCompatible)
+		"p.gender = ANY($3)",
+		"$2 = ANY(p.interested_in)",
+	}
+
+	conditions = append(conditions, visibilityConditions...)
+
+	args := []interface{}{filter.UserID, string(filter.Gender), pq.Array(filter.InterestedIn)}
+
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if radiusKm > 0 {
+		if r.hasEarthdistance() {
+			origin := "ll_to_earth(" + addArg(lat) + ", " + addArg(lon) + ")"
+			meters := addArg(radiusKm * 1000)
+			conditions = append(conditions,
+				"earth_box("+origin+", "+meters+") @> ll_to_earth(p.latitude, p.longitude)",
+				"earth_distance("+origin+", ll_to_earth(p.latitude, p.longitude)) <= "+meters,
+			)
+		} else {
+			conditions = append(conditions, boundingBoxCondition(lat, lon, radiusKm, addArg)...)
+		}
+	}
+	if filter.ExcludePassed {
+		passCondition := "NOT EXISTS (SELECT 1 FROM user_passes up WHERE up.passer_id = $1 AND up.passed_id = p.user_id"
+		if filter.PassCooldown > 0 {
+			passCondition += " AND up.created_at > CURRENT_TIMESTAMP - make_interval(secs => " + addArg(filter.PassCooldown.Seconds()) + ")"
+		}
+		conditions = append(conditions, passCondition+")")
+	}
+	if filter.MinAge > 0 {
+		conditions = append(conditions, "p.birth_date <= CURRENT_DATE - make_interval(years => "+addArg(filter.MinAge)+")")
+	}
+	if filter.MaxAge > 0 {
+		conditions = append(conditions, "p.birth_date > CURRENT_DATE - make_interval(years => "+addArg(filter.MaxAge+1)+")")
+	}
+	if filter.ViewerAge > 0 {
+		// Tranche d'âge du candidat : les préférences doivent être compatibles dans les deux sens
+		viewerAge := addArg(filter.ViewerAge)
+		conditions = append(conditions,
+			"(dp.min_age IS NULL OR dp.min_age = 0 OR dp.min_age <= "+viewerAge+")",
+			"(dp.max_age IS NULL OR dp.max_age = 0 OR dp.max_age >= "+viewerAge+")",
+		)
+	}
+	if filter.SavedSearchID > 0 {
+		conditions = append(conditions, "NOT EXISTS (SELECT 1 FROM saved_search_hits h WHERE h.search_id = "+addArg(filter.SavedSearchID)+" AND h.user_id = p.user_id)")
+	}
+	if filter.ChangedSince != nil {
+		// Un profil devient complet par sa fiche, un tag ou sa photo de profil
+		since := addArg(*filter.ChangedSince)
+		conditions = append(conditions, `(p.updated_at >= `+since+`
+			OR EXISTS (SELECT 1 FROM user_tags ut WHERE ut.user_id = p.user_id AND ut.created_at >= `+since+`)
+			OR EXISTS (SELECT 1 FROM user_photos ph WHERE ph.user_id = p.user_id AND ph.is_profile = TRUE AND ph.updated_at >= `+since+`))`)
+	}
+	if filter.MinFame > 0 {
+		conditions = append(conditions, "p.fame_rating >= "+addArg(filter.MinFame))
+	}
+	if filter.MaxFame > 0 {
+		conditions = append(conditions, "p.fame_rating <= "+addArg(filter.MaxFame))
+	}
+
+	query := `
+        SELECT p.user_id, p.gender, p.sexual_preferences, p.interested_in, p.biography, p.birth_date, p.fame_rating,
+               p.latitude, p.longitude, p.location_name, p.created_at, p.updated_at,
+               u.username, u.first_name, u.last_name, u.is_verified, u.created_at,
+               COALESCE(dp.max_distance_km, 0)
+        FROM user_profiles p
+        JOIN users u ON u.id = p.user_id
+        LEFT JOIN user_discovery_preferences dp ON dp.user_id = p.user_id
+        WHERE ` + strings.Join(conditions, "\n        AND ")
+
+	return query, args
+}
+
+// FilterVisible retourne, parmi userIDs, les profils encore visibles par viewerID :
+// ni bloqués dans un sens ou l'autre, ni masqués par la modération
+func (r *PostgresProfileRepository) FilterVisible(viewerID int, userIDs []int) (map[int]bool, error) {
+	visible := make(map[int]bool, len(userIDs))
+	if len(userIDs) == 0 {
+		return visible, nil
+	}
+
+	ids := make([]int64, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = int64(id)
+	}
+
+	query := `
+        SELECT p.user_id
+        FROM user_profiles p
+        JOIN users u ON u.id = p.user_id
+        WHERE p.user_id = ANY($2)
+        AND ` + strings.Join(visibilityConditions, "\n        AND ")
+
+	rows, err := r.db.Query(query, viewerID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la vérification de la visibilité des profils: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un profil visible: %w", err)
+		}
+		visible[userID] = true
+	}
+
+	return visible, rows.Err()
+}
+
+func (r *PostgresProfileRepository) loadCandidateTags(userIDs []int64, byUserID map[int]*Profile) error {
+	query := `
+        SELECT ut.user_id, t.id, t.name, t.created_at
+        FROM user_tags ut
+        JOIN tags t ON t.id = ut.tag_id
+        WHERE ut.user_id = ANY($1)
+    `
+
+	rows, err := r.db.Query(query, pq.Array(userIDs))
+	if err != nil {
+		return fmt.Errorf("erreur lors de la récupération des tags des candidats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int
+		var tag Tag
+		if err := rows.Scan(&userID, &tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return fmt.Errorf("erreur lors de la lecture d'un tag: %w", err)
+		}
+		if profile, ok := byUserID[userID]; ok {
+			profile.Tags = append(profile.Tags, tag)
+		}
+	}
+
+	return rows.Err()
+}
+
+func (r *PostgresProfileRepository) loadCandidatePhotos(userIDs []int64, byUserID map[int]*Profile) error {
+	query := `
+        SELECT id, user_id, file_path, is_profile, created_at, updated_at
+        FROM user_photos
+        WHERE user_id = ANY($1)
+        ORDER BY user_id, CASE WHEN is_profile THEN 0 ELSE 1 END, created_at
+    `
+
+	rows, err := r.db.Query(query, pq.Array(userIDs))
+	if err != nil {
+		return fmt.Errorf("erreur lors de la récupération des photos des candidats: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var photo Photo
+		if err := rows.Scan(
+			&photo.ID,
+			&photo.UserID,
+			&photo.FilePath,
+			&photo.IsProfile,
+			&photo.CreatedAt,
+			&photo.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("erreur lors de la lecture d'une photo: %w", err)
+		}
+		if profile, ok := byUserID[photo.UserID]; ok {
+			profile.Photos = append(profile.Photos, photo)
+		}
+	}
+
+	return rows.Err()
+}
+
+// geoBox est le rectangle couvrant un rayon autour d'un point. Sans contrainte de
+// longitude près des pôles (anyLon) ; à cheval sur l'antiméridien si minLon > maxLon.
+type geoBox struct {
+	minLat, maxLat float64
+	minLon, maxLon float64
+	anyLon         bool
+}
+
+// boundingBox calcule le préfiltre rectangulaire d'un rayon. L'écart de longitude
+// est pris à la latitude la plus proche du pôle dans la boîte, là où un degré de
+// longitude est le plus court : au centre, le rectangle serait trop étroit côté pôle.
+func boundingBox(lat, lon, radiusKm float64) geoBox {
+	deltaLat := radiusKm / kmPerDegreeLat
+	box := geoBox{minLat: lat - deltaLat, maxLat: lat + deltaLat}
+
+	if box.minLat <= -90 || box.maxLat >= 90 {
+		box.anyLon = true
+		return box
+	}
+
+	poleward := math.Max(math.Abs(box.minLat), math.Abs(box.maxLat))
+	deltaLon := radiusKm / (kmPerDegreeLat * math.Cos(toRadians(poleward)))
+	if deltaLon >= 180 {
+		box.anyLon = true
+		return box
+	}
+
+	box.minLon, box.maxLon = lon-deltaLon, lon+deltaLon
+	if box.minLon < -180 {
+		box.minLon += 360
+	}
+	if box.maxLon > 180 {
+		box.maxLon -= 360
+	}
+	return box
+}
+
+// contains indique si un point est dans le rectangle, comme les conditions SQL
+func (b geoBox) contains(lat, lon float64) bool {
+	if lat < b.minLat || lat > b.maxLat {
+		return false
+	}
+	switch {
+	case b.anyLon:
+		return true
+	case b.minLon > b.maxLon:
+		return lon >= b.minLon || lon <= b.maxLon
+	default:
+		return lon >= b.minLon && lon <= b.maxLon
+	}
+}
+
+// boundingBoxCondition construit le préfiltre rectangulaire couvrant un rayon autour d'un point.
+// Près des pôles la contrainte de longitude est abandonnée ; l'antiméridien est géré par un OR.
+func boundingBoxCondition(lat, lon, radiusKm float64, addArg func(interface{}) string) []string {
+	box := boundingBox(lat, lon, radiusKm)
+
+	conditions := []string{
+		"p.latitude BETWEEN " + addArg(box.minLat) + " AND " + addArg(box.maxLat),
+	}
+
+	switch {
+	case box.anyLon:
+	case box.minLon > box.maxLon:
+		conditions = append(conditions, "(p.longitude >= "+addArg(box.minLon)+" OR p.longitude <= "+addArg(box.maxLon)+")")
+	default:
+		conditions = append(conditions, "p.longitude BETWEEN "+addArg(box.minLon)+" AND "+addArg(box.maxLon))
+	}
+
+	return conditions
+}
+
+// PassUser enregistre qu'un utilisateur écarte un profil ; un nouveau pass relance le délai
+func (r *PostgresProfileRepository) PassUser(passerID, passedID int) error {
+	query := `
+        INSERT INTO user_passes (passer_id, passed_id)
+        VALUES ($1, $2)
+        ON CONFLICT (passer_id, passed_id) DO UPDATE SET created_at = CURRENT_TIMESTAMP
+    `
+
+	if _, err := r.db.Exec(query, passerID, passedID); err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement du pass: %w", err)
+	}
+
+	return nil
+}
+
+// UnpassUser annule le pass d'un profil
+func (r *PostgresProfileRepository) UnpassUser(passerID, passedID int) error {
+	query := "DELETE FROM user_passes WHERE passer_id = $1 AND passed_id = $2"
+
+	if _, err := r.db.Exec(query, passerID, passedID); err != nil {
+		return fmt.Errorf("erreur lors de l'annulation du pass: %w", err)
+	}
+
+	return nil
+}
+
+// UndoLastPass annule le pass le plus récent et retourne l'ID du profil concerné (0 si aucun)
+func (r *PostgresProfileRepository) UndoLastPass(passerID int) (int, error) {
+	query := `
+        DELETE FROM user_passes
+        WHERE id = (
+            SELECT id FROM user_passes
+            WHERE passer_id = $1
+            ORDER BY created_at DESC, id DESC
+            LIMIT 1
+        )
+        RETURNING passed_id
+    `
+
+	var passedID int
+	err := r.db.QueryRow(query, passerID).Scan(&passedID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erreur lors de l'annulation du dernier pass: %w", err)
+	}
+
+	return passedID, nil
+}
+
+// GetDiscoveryPreferences récupère les préférences de découverte (valeurs par
+// défaut si aucune) et les genres recherchés du profil
+func (r *PostgresProfileRepository) GetDiscoveryPreferences(userID int) (*DiscoveryPreferences, error) {
+	query := `
+        SELECT COALESCE(dp.min_age, 0), COALESCE(dp.max_age, 0), COALESCE(dp.max_distance_km, 0),
+               COALESCE(p.interested_in, '{}'), dp.updated_at
+        FROM users u
+        LEFT JOIN user_profiles p ON p.user_id = u.id
+        LEFT JOIN user_discovery_preferences dp ON dp.user_id = u.id
+        WHERE u.id = $1
+    `
+
+	prefs := &DiscoveryPreferences{UserID: userID, Genders: []string{}}
+	var updatedAt sql.NullTime
+	err := r.db.QueryRow(query, userID).Scan(
+		&prefs.MinAge,
+		&prefs.MaxAge,
+		&prefs.MaxDistanceKm,
+		pq.Array(&prefs.Genders),
+		&updatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return prefs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des préférences de découverte: %w", err)
+	}
+	prefs.UpdatedAt = updatedAt.Time
+
+	return prefs, nil
+}
+
+// SaveDiscoveryPreferences crée ou remplace la tranche d'âge et la distance
+// maximale ; les genres recherchés sont enregistrés avec le profil
+func (r *PostgresProfileRepository) SaveDiscoveryPreferences(prefs *DiscoveryPreferences) error {
+	query := `
+        INSERT INTO user_discovery_preferences (user_id, min_age, max_age, max_distance_km, updated_at)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+        ON CONFLICT (user_id) DO UPDATE SET
+            min_age = EXCLUDED.min_age,
+            max_age = EXCLUDED.max_age,
+            max_distance_km = EXCLUDED.max_distance_km,
+            updated_at = CURRENT_TIMESTAMP
+        RETURNING updated_at
+    `
+
+	err := r.db.QueryRow(query, prefs.UserID, prefs.MinAge, prefs.MaxAge, prefs.MaxDistanceKm).Scan(&prefs.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement des préférences de découverte: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cduffaut/matcha/internal/database"
+)
+
+// destination retourne le point situé à distanceKm de (lat, lon) selon le cap donné
+func destination(lat, lon, distanceKm, bearingDeg float64) (float64, float64) {
+	const R = 6371.0
+	lat1, lon1 := toRadians(lat), toRadians(lon)
+	bearing, angular := toRadians(bearingDeg), distanceKm/R
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(angular) + math.Cos(lat1)*math.Sin(angular)*math.Cos(bearing))
+	lon2 := lon1 + math.Atan2(math.Sin(bearing)*math.Sin(angular)*math.Cos(lat1), math.Cos(angular)-math.Sin(lat1)*math.Sin(lat2))
+
+	lon2Deg := math.Mod(lon2*180/math.Pi+540, 360) - 180
+	return lat2 * 180 / math.Pi, lon2Deg
+}
+
+func TestBoundingBoxKeepsPointsWithinRadius(t *testing.T) {
+	centers := []struct {
+		name     string
+		lat, lon float64
+	}{
+		{"équateur", 0, 2},
+		{"Paris", 48.8566, 2.3522},
+		{"nord de la Norvège", 70, 25},
+		{"proche du pôle nord", 84, -40},
+		{"hémisphère sud", -75, 100},
+		{"antiméridien est", 65, 179.9},
+		{"antiméridien ouest", -40, -179.9},
+		{"pôle nord", 89.9, 0},
+	}
+	radii := []float64{5, 50, 300, 1000}
+
+	for _, center := range centers {
+		for _, radius := range radii {
+			t.Run(fmt.Sprintf("%s %.0fkm", center.name, radius), func(t *testing.T) {
+				box := boundingBox(center.lat, center.lon, radius)
+				for bearing := 0.0; bearing < 360; bearing += 5 {
+					for _, fraction := range []float64{0.5, 0.999} {
+						lat, lon := destination(center.lat, center.lon, radius*fraction, bearing)
+						if !box.contains(lat, lon) {
+							t.Fatalf("point (%.4f, %.4f) à %.1f km (cap %.0f°) exclu par %+v",
+								lat, lon, calculateDistance(center.lat, center.lon, lat, lon), bearing, box)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestBoundingBoxConditionWrapsAntimeridian(t *testing.T) {
+	var args []interface{}
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	conditions := boundingBoxCondition(10, 179.5, 200, addArg)
+	if len(conditions) != 2 || conditions[1] != "(p.longitude >= $3 OR p.longitude <= $4)" {
+		t.Fatalf("conditions = %q, attendu une contrainte de longitude en OR", conditions)
+	}
+	if minLon, maxLon := args[2].(float64), args[3].(float64); minLon < 0 || maxLon > 0 {
+		t.Errorf("longitudes = %v, %v, attendues de part et d'autre de l'antiméridien", minLon, maxLon)
+	}
+
+	args = nil
+	if conditions := boundingBoxCondition(89.5, 0, 100, addArg); len(conditions) != 1 {
+		t.Errorf("conditions = %q, attendu la seule contrainte de latitude près du pôle", conditions)
+	}
+}
+
+// spatialTestUsers nombre de profils répartis sur la France métropolitaine
+// pour vérifier le plan de la présélection
+const spatialTestUsers = 100000
+
+// openSpatialTestDB crée un schéma jetable dans la base désignée par
+// MATCHA_TEST_DATABASE_URL (DSN lib/pq), y applique les migrations et le peuple
+// de spatialTestUsers profils complets
+func openSpatialTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("MATCHA_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("MATCHA_TEST_DATABASE_URL non défini : plan de la recherche par rayon non vérifié")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("connexion: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	schema := fmt.Sprintf("matcha_spatial_test_%d", os.Getpid())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("création du schéma: %v", err)
+	}
+	t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+	// Les extensions déjà installées restent visibles via public
+	db, err := sql.Open("postgres", withSearchPath(dsn, schema+",public"))
+	if err != nil {
+		t.Fatalf("connexion au schéma: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// Les migrations sont lues depuis la racine du dépôt
+	wd, _ := os.Getwd()
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	// Des utilisateurs présents avant les migrations écartent le seed CSV
+	users, err := os.ReadFile("internal/database/migrations/create_users_table.sql")
+	if err != nil {
+		t.Fatalf("lecture de la migration des utilisateurs: %v", err)
+	}
+	seed := string(users) + fmt.Sprintf(`;
+		INSERT INTO users (username, email, first_name, last_name, password, is_verified)
+		SELECT 'spatial' || i, 'spatial' || i || '@example.com', 'Spatial', 'Test', 'x', TRUE
+		FROM generate_series(1, %d) i`, spatialTestUsers)
+	if _, err := db.Exec(seed); err != nil {
+		t.Fatalf("création des utilisateurs: %v", err)
+	}
+	if err := database.RunMigrations(db); err != nil {
+		t.Fatalf("migrations: %v", err)
+	}
+
+	_, err = db.Exec(`
+		SELECT setseed(0.42);
+		INSERT INTO user_profiles (user_id, gender, sexual_preferences, interested_in, biography, birth_date,
+		                           fame_rating, latitude, longitude)
+		SELECT id,
+		       CASE WHEN id % 2 = 0 THEN 'male' ELSE 'female' END,
+		       'bisexual', ARRAY['male', 'female'], 'bio',
+		       DATE '1970-01-01' + (id % 12000), id % 100,
+		       42 + random() * 9, -5 + random() * 13
+		FROM users;
+		INSERT INTO tags (name) VALUES ('#spatial') ON CONFLICT (name) DO NOTHING;
+		INSERT INTO user_tags (user_id, tag_id) SELECT id, (SELECT id FROM tags WHERE name = '#spatial') FROM users;
+		INSERT INTO user_photos (user_id, file_path, is_profile) SELECT id, 'spatial.jpg', TRUE FROM users;
+		ANALYZE`)
+	if err != nil {
+		t.Fatalf("création des profils: %v", err)
+	}
+
+	return db
+}
+
+// withSearchPath ajoute le paramètre search_path à un DSN clé=valeur ou URL
+func withSearchPath(dsn, searchPath string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		separator := "?"
+		if strings.Contains(dsn, "?") {
+			separator = "&"
+		}
+		return dsn + separator + "search_path=" + url.QueryEscape(searchPath)
+	}
+	return dsn + " search_path=" + searchPath
+}
+
+// TestFindWithinRadiusUsesSpatialIndex vérifie sur spatialTestUsers profils que
+// la présélection par rayon passe par l'index spatial (GiST earthdistance, ou
+// idx_user_profiles_location pour la boîte englobante) plutôt que par un
+// parcours complet de user_profiles
+func TestFindWithinRadiusUsesSpatialIndex(t *testing.T) {
+	db := openSpatialTestDB(t)
+	const lat, lon, radius = 48.8566, 2.3522, 10.0
+
+	type spatialMode struct {
+		name          string
+		earthdistance bool
+		index         string
+	}
+	modes := []spatialMode{{"boîte englobante", false, "idx_user_profiles_location"}}
+	if detected := NewPostgresProfileRepository(db).(*PostgresProfileRepository); detected.hasEarthdistance() {
+		modes = append(modes, spatialMode{"earthdistance", true, "idx_user_profiles_earth"})
+	} else {
+		t.Log("extension earthdistance indisponible : seule la boîte englobante est vérifiée")
+	}
+
+	for _, mode := range modes {
+		t.Run(mode.name, func(t *testing.T) {
+			repo := &PostgresProfileRepository{db: db}
+			repo.spatialOnce.Do(func() { repo.earthdistance = mode.earthdistance })
+
+			filter := CandidateFilter{UserID: 1, Gender: GenderFemale, InterestedIn: []string{GenderMale, GenderFemale}}
+			query, args := repo.candidateQuery(lat, lon, radius, filter)
+
+			rows, err := db.Query("EXPLAIN "+query, args...)
+			if err != nil {
+				t.Fatalf("EXPLAIN: %v", err)
+			}
+			var plan []string
+			for rows.Next() {
+				var line string
+				if err := rows.Scan(&line); err != nil {
+					t.Fatalf("lecture du plan: %v", err)
+				}
+				plan = append(plan, line)
+			}
+			rows.Close()
+
+			text := strings.Join(plan, "\n")
+			if !strings.Contains(text, mode.index) || strings.Contains(text, "Seq Scan on user_profiles") {
+				t.Errorf("plan sans %s ou avec un parcours complet de user_profiles :\n%s", mode.index, text)
+			}
+
+			candidates, err := repo.FindWithinRadius(lat, lon, radius, filter)
+			if err != nil {
+				t.Fatalf("FindWithinRadius: %v", err)
+			}
+			if len(candidates) == 0 {
+				t.Fatal("aucun candidat dans le rayon")
+			}
+			for _, candidate := range candidates {
+				if candidate.Distance > radius {
+					t.Errorf("candidat %d à %.1f km, hors du rayon", candidate.Profile.UserID, candidate.Distance)
+				}
+			}
+		})
+	}
+}
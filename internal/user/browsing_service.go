@@ -12,15 +12,27 @@ import (
 
 // BrowsingService fournit des services pour explorer les profils
 type BrowsingService struct {
-	userRepo    Repository
-	profileRepo ProfileRepository
+	userRepo     Repository
+	profileRepo  ProfileRepository
+	scorer       Scorer
+	tags         *TagIndex // Normalisation et synonymes des tags
+	recommender  RecommendationRepository
+	zones        []float64     // Limites croissantes (km) des zones géographiques prioritaires
+	passCooldown time.Duration // Délai avant qu'un profil écarté soit reproposé (0 = jamais)
+	sessions     *BrowseSessions
 }
 
 // NewBrowsingService crée un nouveau service de browsing
-func NewBrowsingService(userRepo Repository, profileRepo ProfileRepository) *BrowsingService {
+func NewBrowsingService(userRepo Repository, profileRepo ProfileRepository, scorer Scorer, tags *TagIndex, recommender RecommendationRepository, zones []float64, passCooldown time.Duration, sessions *BrowseSessions) *BrowsingService {
 	return &BrowsingService{
-		userRepo:    userRepo,
-		profileRepo: profileRepo,
+		userRepo:     userRepo,
+		profileRepo:  profileRepo,
+		scorer:       scorer,
+		tags:         tags,
+		recommender:  recommender,
+		zones:        zones,
+		passCooldown: passCooldown,
+		sessions:     sessions,
 	}
 }
 
@@ -29,6 +41,8 @@ type SuggestedProfileResult struct {
 	Profile            *Profile
 	User               *models.User
 	CompatibilityScore float64
+	Scores             ScoreBreakdown // Détail du score par composante
+	Reasons            []MatchReason  // Pourquoi ce profil est proposé
 	Distance           float64
 	CommonTags         int
 	Age                int
@@ -41,8 +55,8 @@ func (s *BrowsingService) IsProfileComplete(profile *Profile) bool {
 		return false
 	}
 
-	// 2. Préférence sexuelle obligatoire
-	if profile.SexualPreference == "" {
+	// 2. Genres recherchés obligatoires
+	if len(profile.InterestedIn) == 0 {
 		return false
 	}
 
@@ -76,247 +90,205 @@ func (s *BrowsingService) IsProfileComplete(profile *Profile) bool {
 	return true
 }
 
-// GetSuggestions récupère des suggestions de profils selon des critères
-func (s *BrowsingService) GetSuggestions(userID int, limit, offset int) ([]SuggestedProfileResult, error) {
-	// Récupérer le profil de l'utilisateur
-	currentProfile, err := s.profileRepo.GetByUserID(userID)
-	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération du profil: %w", err)
+// GetSuggestions récupère une page de suggestions. Sans curseur, le classement
+// est calculé et figé ; les curseurs suivants parcourent ce même classement
+func (s *BrowsingService) GetSuggestions(userID int, limit int, cursor string) (*BrowsePage, error) {
+	if cursor != "" {
+		return s.nextPage(userID, cursor, limit)
 	}
 
-	// AJOUT: Vérifier que le profil actuel est complet AVANT de chercher des suggestions
-	if !s.IsProfileComplete(currentProfile) {
-		return []SuggestedProfileResult{}, fmt.Errorf("votre profil doit être complété pour voir des suggestions")
+	suggestions, err := s.rankSuggestions(userID)
+	if err != nil {
+		return nil, err
 	}
 
-	currentUser, err := s.userRepo.GetByID(userID)
+	return s.startSession(userID, suggestions, limit)
+}
+
+// startSession fige un classement et en retourne la première page
+func (s *BrowsingService) startSession(userID int, results []SuggestedProfileResult, limit int) (*BrowsePage, error) {
+	cursor, err := s.sessions.Create(userID, results)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération de l'utilisateur: %w", err)
+		return nil, err
 	}
+	return s.sessions.Page(userID, cursor, limit)
+}
 
-	// Récupérer tous les profils
-	profiles, err := s.profileRepo.GetAllProfiles()
+// nextPage sert une page d'un classement figé, sans les profils bloqués ou
+// masqués par la modération depuis que le classement a été calculé ; la page
+// peut donc compter moins de limit résultats
+func (s *BrowsingService) nextPage(userID int, cursor string, limit int) (*BrowsePage, error) {
+	page, err := s.sessions.Page(userID, cursor, limit)
 	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération des profils: %w", err)
+		return nil, err
 	}
 
-	// Filtrer les profils selon les préférences
-	var filteredProfiles []SuggestedProfileResult
-
-	for _, profile := range profiles {
-		// Ignorer son propre profil
-		if profile.UserID == userID {
-			continue
-		}
-
-		// AJOUT: Vérifier que le profil candidat est complet
-		if !s.IsProfileComplete(profile) {
-			continue
-		}
-
-		// Récupérer les infos de l'utilisateur
-		user, err := s.userRepo.GetByID(profile.UserID)
-		if err != nil {
-			continue
-		}
+	userIDs := make([]int, len(page.Results))
+	for i, result := range page.Results {
+		userIDs[i] = result.Profile.UserID
+	}
 
-		// Filtrer selon l'orientation sexuelle
-		if !s.isCompatibleOrientation(currentProfile, profile, currentUser, user) {
-			continue
-		}
+	visible, err := s.profileRepo.FilterVisible(userID, userIDs)
+	if err != nil {
+		return nil, err
+	}
 
-		// Bloquer les utilisateurs qui ont bloqué l'utilisateur actuel ou que l'utilisateur actuel a bloqué
-		blocked, err := s.profileRepo.IsBlocked(userID, profile.UserID)
-		if err != nil || blocked {
-			continue
+	results := page.Results[:0]
+	for _, result := range page.Results {
+		if visible[result.Profile.UserID] {
+			results = append(results, result)
 		}
+	}
+	page.Results = results
 
-		alreadyLiked, err := s.profileRepo.CheckIfLiked(userID, profile.UserID)
-		if err != nil || alreadyLiked {
-			continue
-		}
+	return page, nil
+}
 
-		// CORRECTION: Exclure les utilisateurs déjà matchés
-		matched, err := s.profileRepo.CheckIfMatched(userID, profile.UserID)
-		if err != nil {
-			continue // En cas d'erreur, ignorer ce profil
-		}
-		if matched {
-			continue // Ignorer les profils déjà matchés
-		}
+// rankSuggestions calcule le classement complet des suggestions de l'utilisateur
+func (s *BrowsingService) rankSuggestions(userID int) ([]SuggestedProfileResult, error) {
+	// Récupérer le profil de l'utilisateur
+	currentProfile, err := s.profileRepo.GetByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération du profil: %w", err)
+	}
 
-		// Calculer la distance
-		distance := calculateDistance(
-			currentProfile.Latitude, currentProfile.Longitude,
-			profile.Latitude, profile.Longitude,
-		)
+	// AJOUT: Vérifier que le profil actuel est complet AVANT de chercher des suggestions
+	if !s.IsProfileComplete(currentProfile) {
+		return nil, fmt.Errorf("votre profil doit être complété pour voir des suggestions")
+	}
 
-		// Calculer les tags communs
-		commonTags := s.countCommonTags(currentProfile.Tags, profile.Tags)
+	// Présélection SQL : les profils écartés ne sont plus proposés, sauf après le délai configuré
+	filter := s.candidateFilter(userID, currentProfile)
+	filter.ExcludePassed = true
+	filter.PassCooldown = s.passCooldown
 
-		// Calculer le score de compatibilité
-		// 50% basé sur la distance, 30% sur les tags communs, 20% sur le fame rating
-		maxDistance := 100.0 // km
-		distanceScore := math.Max(0, 1.0-distance/maxDistance)
-		maxCommonTags := 5
-		tagsScore := float64(commonTags) / float64(maxCommonTags)
-		maxFameRating := 100
-		fameScore := float64(profile.FameRating) / float64(maxFameRating)
+	// Préférences de découverte de l'utilisateur : âge et distance maximale
+	prefs, err := s.profileRepo.GetDiscoveryPreferences(userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des préférences de découverte: %w", err)
+	}
+	filter.MinAge = prefs.MinAge
+	filter.MaxAge = prefs.MaxAge
 
-		compatibilityScore := distanceScore*0.5 + tagsScore*0.3 + fameScore*0.2
+	affinities := s.affinities(userID)
 
-		age := 0
-		if profile.BirthDate != nil {
-			age = calculateAge(*profile.BirthDate)
+	// Les zones étant servies de la plus proche à la plus lointaine, il suffit
+	// d'élargir le rayon jusqu'à disposer d'assez de profils pour le classement
+	var filteredProfiles []SuggestedProfileResult
+	for _, radius := range s.searchRadii(prefs.MaxDistanceKm) {
+		candidates, err := s.profileRepo.FindWithinRadius(
+			currentProfile.Latitude, currentProfile.Longitude, radius, filter,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la récupération des profils: %w", err)
 		}
 
-		// Ajouter à la liste
-		filteredProfiles = append(filteredProfiles, SuggestedProfileResult{
-			Profile:            profile,
-			User:               user,
-			CompatibilityScore: compatibilityScore,
-			Distance:           distance,
-			CommonTags:         commonTags,
-			Age:                age,
-		})
-	}
-
-	// Trier selon le score de compatibilité
-	sort.Slice(filteredProfiles, func(i, j int) bool {
-		return filteredProfiles[i].CompatibilityScore > filteredProfiles[j].CompatibilityScore
-	})
-
-	// NOUVEAU : Prioriser la zone géographique selon le cahier des charges
-	// Créer 5 groupes par distance pour une démonstration claire de la priorité géographique
-	var zone1Profiles []SuggestedProfileResult // < 180km (très proche)
-	var zone2Profiles []SuggestedProfileResult // 180-250km (proche)
-	var zone3Profiles []SuggestedProfileResult // 250-350km (moyen)
-	var zone4Profiles []SuggestedProfileResult // 350-500km (loin)
-	var zone5Profiles []SuggestedProfileResult // > 500km (très loin)
-
-	for _, profile := range filteredProfiles {
-		if profile.Distance < 180.0 {
-			zone1Profiles = append(zone1Profiles, profile)
-		} else if profile.Distance < 250.0 {
-			zone2Profiles = append(zone2Profiles, profile)
-		} else if profile.Distance < 350.0 {
-			zone3Profiles = append(zone3Profiles, profile)
-		} else if profile.Distance < 500.0 {
-			zone4Profiles = append(zone4Profiles, profile)
-		} else {
-			zone5Profiles = append(zone5Profiles, profile)
+		filteredProfiles = s.scoreCandidates(currentProfile, candidates, affinities)
+		if len(filteredProfiles) >= browseSessionSize {
+			break
 		}
 	}
 
-	// DEBUG: Afficher la répartition par zones (optionnel - à supprimer en production)
-	fmt.Printf("DEBUG - Geographic zones: Zone1(<180km)=%d, Zone2(180-250km)=%d, Zone3(250-350km)=%d, Zone4(350-500km)=%d, Zone5(>500km)=%d\n",
-		len(zone1Profiles), len(zone2Profiles), len(zone3Profiles), len(zone4Profiles), len(zone5Profiles))
-
-	// Trier chaque zone par score de compatibilité
-	sort.Slice(zone1Profiles, func(i, j int) bool {
-		return zone1Profiles[i].CompatibilityScore > zone1Profiles[j].CompatibilityScore
-	})
-	sort.Slice(zone2Profiles, func(i, j int) bool {
-		return zone2Profiles[i].CompatibilityScore > zone2Profiles[j].CompatibilityScore
-	})
-	sort.Slice(zone3Profiles, func(i, j int) bool {
-		return zone3Profiles[i].CompatibilityScore > zone3Profiles[j].CompatibilityScore
-	})
-	sort.Slice(zone4Profiles, func(i, j int) bool {
-		return zone4Profiles[i].CompatibilityScore > zone4Profiles[j].CompatibilityScore
-	})
-	sort.Slice(zone5Profiles, func(i, j int) bool {
-		return zone5Profiles[i].CompatibilityScore > zone5Profiles[j].CompatibilityScore
+	// Prioriser la zone géographique selon le cahier des charges, puis le score de compatibilité
+	sort.SliceStable(filteredProfiles, func(i, j int) bool {
+		zoneI, zoneJ := s.distanceZone(filteredProfiles[i].Distance), s.distanceZone(filteredProfiles[j].Distance)
+		if zoneI != zoneJ {
+			return zoneI < zoneJ
+		}
+		return filteredProfiles[i].CompatibilityScore > filteredProfiles[j].CompatibilityScore
 	})
 
-	// Reconstruire la liste avec priorité géographique stricte
-	filteredProfiles = []SuggestedProfileResult{}
-	filteredProfiles = append(filteredProfiles, zone1Profiles...) // Très proche en premier
-	filteredProfiles = append(filteredProfiles, zone2Profiles...) // Puis proche
-	filteredProfiles = append(filteredProfiles, zone3Profiles...) // Puis moyen
-	filteredProfiles = append(filteredProfiles, zone4Profiles...) // Puis loin
-	filteredProfiles = append(filteredProfiles, zone5Profiles...) // Très loin en dernier
+	return filteredProfiles, nil
+}
 
-	// Pagination
-	start := offset
-	end := offset + limit
-	if start >= len(filteredProfiles) {
-		return []SuggestedProfileResult{}, nil
+// candidateFilter construit la présélection SQL pour l'utilisateur courant
+func (s *BrowsingService) candidateFilter(userID int, currentProfile *Profile) CandidateFilter {
+	filter := CandidateFilter{
+		UserID:       userID,
+		Gender:       currentProfile.Gender,
+		InterestedIn: currentProfile.InterestedIn,
 	}
-	if end > len(filteredProfiles) {
-		end = len(filteredProfiles)
+	if currentProfile.BirthDate != nil {
+		filter.ViewerAge = calculateAge(*currentProfile.BirthDate)
 	}
-
-	return filteredProfiles[start:end], nil
+	return filter
 }
 
-// isCompatibleOrientation vérifie si deux profils sont compatibles selon leur orientation
-func (s *BrowsingService) isCompatibleOrientation(profile1, profile2 *Profile, user1, user2 *models.User) bool {
-	// Si l'utilisateur n'a pas spécifié d'orientation, il est considéré bisexuel
-	pref1 := profile1.SexualPreference
-	if pref1 == "" {
-		pref1 = PrefBisexual
+// affinities retourne l'affinité normalisée de chaque profil avec les likes de
+// l'utilisateur ; une erreur n'empêche pas les suggestions, classées sans elle
+func (s *BrowsingService) affinities(userID int) map[int]float64 {
+	affinities, err := s.recommender.GetAffinities(userID)
+	if err != nil {
+		fmt.Printf("Erreur récupération des affinités de %d: %v\n", userID, err)
+		return nil
 	}
+	return normalizeAffinities(affinities)
+}
 
-	pref2 := profile2.SexualPreference
-	if pref2 == "" {
-		pref2 = PrefBisexual
-	}
+// scoreCandidates calcule le score de compatibilité sur l'ensemble présélectionné
+func (s *BrowsingService) scoreCandidates(currentProfile *Profile, candidates []*Candidate, affinities map[int]float64) []SuggestedProfileResult {
+	results := make([]SuggestedProfileResult, 0, len(candidates))
 
-	gender1 := profile1.Gender
-	gender2 := profile2.Gender
+	for _, candidate := range candidates {
+		profile := candidate.Profile
+		distance := candidate.Distance
 
-	// Cas bisexuel
-	if pref1 == PrefBisexual {
-		// Compatible si l'autre est attiré par le genre de cet utilisateur
-		if pref2 == PrefBisexual {
-			return true
-		}
-		if pref2 == PrefHeterosexual {
-			return gender1 != gender2
-		}
-		if pref2 == PrefHomosexual {
-			return gender1 == gender2
+		// Calculer les tags communs
+		input := ScoreInput{
+			Current:    currentProfile,
+			Candidate:  profile,
+			Distance:   distance,
+			SharedTags: s.tags.Shared(currentProfile.Tags, profile.Tags),
+			Affinity:   affinities[profile.UserID],
+			HasLikes:   len(affinities) > 0,
 		}
-	}
+		scores := s.scorer.Score(input)
 
-	// Cas hétérosexuel
-	if pref1 == PrefHeterosexual {
-		if gender1 == gender2 {
-			return false // Même genre, non compatible
+		age := 0
+		if profile.BirthDate != nil {
+			age = calculateAge(*profile.BirthDate)
 		}
-		// L'autre doit être bisexuel ou hétérosexuel
-		return pref2 == PrefBisexual || pref2 == PrefHeterosexual
-	}
 
-	// Cas homosexuel
-	if pref1 == PrefHomosexual {
-		if gender1 != gender2 {
-			return false // Genre différent, non compatible
-		}
-		// L'autre doit être bisexuel ou homosexuel
-		return pref2 == PrefBisexual || pref2 == PrefHomosexual
+		results = append(results, SuggestedProfileResult{
+			Profile:            profile,
+			User:               candidate.User,
+			CompatibilityScore: scores.Total,
+			Scores:             scores,
+			Reasons:            explainMatch(input, scores),
+			Distance:           distance,
+			CommonTags:         len(input.SharedTags),
+			Age:                age,
+		})
 	}
 
-	return false
+	return results
 }
 
-// countCommonTags compte le nombre de tags communs entre deux listes
-func (s *BrowsingService) countCommonTags(tags1, tags2 []Tag) int {
-	tagMap := make(map[string]bool)
-
-	for _, tag := range tags1 {
-		tagMap[tag.Name] = true
+// searchRadii retourne les rayons (km) successivement explorés pour les suggestions :
+// les limites des zones, puis 0 pour une recherche sans limite. Une distance
+// maximale (maxDistance > 0) plafonne les rayons et remplace la recherche sans limite.
+func (s *BrowsingService) searchRadii(maxDistance float64) []float64 {
+	if maxDistance <= 0 {
+		return append(append([]float64{}, s.zones...), 0)
 	}
 
-	count := 0
-	for _, tag := range tags2 {
-		if tagMap[tag.Name] {
-			count++
+	var radii []float64
+	for _, radius := range s.zones {
+		if radius < maxDistance {
+			radii = append(radii, radius)
 		}
 	}
+	return append(radii, maxDistance)
+}
 
-	return count
+// distanceZone retourne l'indice de la zone géographique de priorité d'une distance
+func (s *BrowsingService) distanceZone(distance float64) int {
+	for zone, radius := range s.zones {
+		if distance < radius {
+			return zone
+		}
+	}
+	return len(s.zones)
 }
 
 // calculateDistance calcule la distance en km entre deux points géographiques
@@ -343,18 +315,36 @@ func toRadians(deg float64) float64 {
 
 // FilterOptions contient les options de filtrage pour la recherche
 type FilterOptions struct {
-	MinAge      int
-	MaxAge      int
-	MinFame     int
-	MaxFame     int
-	MaxDistance float64
-	Tags        []string
-	SortBy      string // "age", "distance", "fame", "common_tags"
-	SortOrder   string // "asc", "desc"
+	MinAge        int        `json:"min_age"`
+	MaxAge        int        `json:"max_age"`
+	MinFame       int        `json:"min_fame"`
+	MaxFame       int        `json:"max_fame"`
+	MaxDistance   float64    `json:"max_distance"`
+	Tags          []string   `json:"tags"`
+	SortBy        string     `json:"sort_by"`    // "age", "distance", "fame", "common_tags"
+	SortOrder     string     `json:"sort_order"` // "asc", "desc"
+	SavedSearchID int        `json:"-"`          // Exclut les profils déjà remontés par cette recherche sauvegardée
+	ChangedSince  *time.Time `json:"-"`          // Restreint aux profils modifiés ou complétés depuis cet instant
 }
 
-// SearchProfiles recherche des profils selon des critères
-func (s *BrowsingService) SearchProfiles(userID int, options FilterOptions, limit, offset int) ([]SuggestedProfileResult, error) {
+// SearchProfiles retourne une page de résultats de recherche. Sans curseur, le
+// classement est calculé et figé ; avec un curseur, les critères sont ceux de la
+// première page
+func (s *BrowsingService) SearchProfiles(userID int, options FilterOptions, limit int, cursor string) (*BrowsePage, error) {
+	if cursor != "" {
+		return s.nextPage(userID, cursor, limit)
+	}
+
+	results, err := s.rankSearch(userID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startSession(userID, results, limit)
+}
+
+// rankSearch calcule le classement complet des profils correspondant aux critères
+func (s *BrowsingService) rankSearch(userID int, options FilterOptions) ([]SuggestedProfileResult, error) {
 	// AJOUT: Vérifier que le profil actuel est complet AVANT de permettre la recherche
 	currentProfile, err := s.profileRepo.GetByUserID(userID)
 	if err != nil {
@@ -362,29 +352,29 @@ func (s *BrowsingService) SearchProfiles(userID int, options FilterOptions, limi
 	}
 
 	if !s.IsProfileComplete(currentProfile) {
-		return []SuggestedProfileResult{}, fmt.Errorf("votre profil doit être complété pour effectuer des recherches")
+		return nil, fmt.Errorf("votre profil doit être complété pour effectuer des recherches")
 	}
 
-	// Similaire à GetSuggestions mais avec des filtres supplémentaires
-	suggestions, err := s.GetSuggestions(userID, 1000, 0) // Récupère un grand nombre
+	// Âge, fame rating et distance (index spatial) sont présélectionnés en base
+	filter := s.candidateFilter(userID, currentProfile)
+	filter.MinAge = options.MinAge
+	filter.MaxAge = options.MaxAge
+	filter.MinFame = options.MinFame
+	filter.MaxFame = options.MaxFame
+	filter.SavedSearchID = options.SavedSearchID
+	filter.ChangedSince = options.ChangedSince
+
+	candidates, err := s.profileRepo.FindWithinRadius(
+		currentProfile.Latitude, currentProfile.Longitude, options.MaxDistance, filter,
+	)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("erreur lors de la recherche des profils: %w", err)
 	}
 
 	var filtered []SuggestedProfileResult
 
-	for _, suggestion := range suggestions {
-		// Filtrer par critères
-		if options.MinFame > 0 && suggestion.Profile.FameRating < options.MinFame {
-			continue
-		}
-		if options.MaxFame > 0 && suggestion.Profile.FameRating > options.MaxFame {
-			continue
-		}
-		if options.MaxDistance > 0 && suggestion.Distance > options.MaxDistance {
-			continue
-		}
-
+	for _, suggestion := range s.scoreCandidates(currentProfile, candidates, s.affinities(userID)) {
+		// L'âge en base est calculé au jour près ; on conserve le contrôle côté Go
 		if options.MinAge > 0 && suggestion.Age < options.MinAge {
 			continue
 		}
@@ -392,43 +382,9 @@ func (s *BrowsingService) SearchProfiles(userID int, options FilterOptions, limi
 			continue
 		}
 
-		// Filtrer par tags
-		if len(options.Tags) > 0 {
-			hasAllTags := true
-			tagMap := make(map[string]bool)
-
-			// Construire une map avec TOUS les formats possibles des tags du profil
-			for _, tag := range suggestion.Profile.Tags {
-				// Ajouter le tag tel qu'il est stocké
-				tagMap[tag.Name] = true
-
-				// Ajouter aussi la version sans # si le tag commence par #
-				if strings.HasPrefix(tag.Name, "#") {
-					tagMap[tag.Name[1:]] = true
-				} else {
-					// Ajouter aussi la version avec # si le tag ne commence pas par #
-					tagMap["#"+tag.Name] = true
-				}
-			}
-
-			// Vérifier si tous les tags recherchés sont présents
-			for _, tag := range options.Tags {
-				// Normaliser le tag recherché : ajouter # si absent
-				normalizedTag := tag
-				if !strings.HasPrefix(tag, "#") {
-					normalizedTag = "#" + tag
-				}
-
-				// Vérifier si le tag (avec ou sans #) existe dans la map
-				if !tagMap[tag] && !tagMap[normalizedTag] {
-					hasAllTags = false
-					break
-				}
-			}
-
-			if !hasAllTags {
-				continue
-			}
+		// Filtrer par tags (normalisés, synonymes compris)
+		if len(options.Tags) > 0 && !s.tags.HasAll(suggestion.Profile.Tags, options.Tags) {
+			continue
 		}
 
 		filtered = append(filtered, suggestion)
@@ -473,17 +429,7 @@ func (s *BrowsingService) SearchProfiles(userID int, options FilterOptions, limi
 		})
 	}
 
-	// Pagination
-	start := offset
-	end := offset + limit
-	if start >= len(filtered) {
-		return []SuggestedProfileResult{}, nil
-	}
-	if end > len(filtered) {
-		end = len(filtered)
-	}
-
-	return filtered[start:end], nil
+	return filtered, nil
 }
 
 // Fonction utilitaire pour calculer l'âge
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/config"
+	"github.com/cduffaut/matcha/internal/outbox"
+)
+
+// FameJob recalcule périodiquement le fame rating de tous les profils. Le
+// calcul porte sur toute la table (percentiles) : il n'est lancé que par la
+// période, et par une seule instance à la fois
+type FameJob struct {
+	repo   ProfileRepository
+	params FameParams
+	every  time.Duration
+}
+
+// NewFameJob crée la tâche de recalcul du fame rating
+func NewFameJob(repo ProfileRepository, cfg config.FameConfig) *FameJob {
+	return &FameJob{
+		repo: repo,
+		params: FameParams{
+			HalfLifeDays: cfg.HalfLife.Hours() / 24,
+			ActorCap:     cfg.ActorCap,
+		},
+		every: cfg.RefreshEvery,
+	}
+}
+
+// Start lance le recalcul périodique, le premier immédiatement
+func (j *FameJob) Start() {
+	go j.run()
+}
+
+// HandleFameRecompute acquitte les événements de recalcul encore présents dans
+// l'outbox : le recalcul planifié suivant en tiendra compte
+func (j *FameJob) HandleFameRecompute(event *outbox.Event) error {
+	return nil
+}
+
+func (j *FameJob) run() {
+	ticker := time.NewTicker(j.every)
+	defer ticker.Stop()
+
+	for {
+		updated, err := j.Recompute()
+		switch {
+		case errors.Is(err, ErrFameRecomputeRunning):
+			// Une autre instance s'en charge
+		case err != nil:
+			fmt.Printf("Erreur recalcul du fame rating: %v\n", err)
+		case updated > 0:
+			fmt.Printf("Fame rating : %d profils mis à jour\n", updated)
+		}
+
+		<-ticker.C
+	}
+}
+
+// Recompute recalcule le fame rating de tous les profils et retourne le
+// nombre de profils mis à jour, ou ErrFameRecomputeRunning si une autre
+// instance est en train de le faire
+func (j *FameJob) Recompute() (int64, error) {
+	return j.repo.RecomputeFameRatings(j.params)
+}
@@ -0,0 +1,143 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cduffaut/matcha/internal/database"
+)
+
+// fameScoresQuery calcule le score brut et le fame rating de tous les profils,
+// enregistrés comme point du jour de fame_history :
+//   - visites (1), likes (2) et matchs (5) reçus, pondérés par leur ancienneté
+//     (demi-vie $1 jours) et par la réputation de leur auteur ;
+//   - seuls les comptes vérifiés comptent, et la contribution d'un même auteur
+//     est plafonnée à $2 points ;
+//   - le fame rating est le percentile du score brut parmi tous les profils.
+const fameScoresQuery = `
+    WITH events AS (
+        SELECT visited_id AS user_id, visitor_id AS actor_id, 1.0 AS points, visited_at AS happened_at
+        FROM profile_visits
+        UNION ALL
+        SELECT liked_id, liker_id, 2.0, created_at
+        FROM user_likes
+        UNION ALL
+        SELECT l1.liked_id, l1.liker_id, 5.0, GREATEST(l1.created_at, l2.created_at)
+        FROM user_likes l1
+        JOIN user_likes l2 ON l2.liker_id = l1.liked_id AND l2.liked_id = l1.liker_id
+    ),
+    weighted AS (
+        SELECT e.user_id, e.actor_id,
+               e.points
+               * power(0.5, EXTRACT(EPOCH FROM (CURRENT_TIMESTAMP - e.happened_at))::FLOAT8 / 86400.0 / $1)
+               * (0.25 + 0.75 * LEAST(COALESCE(ap.fame_rating, 0), 100) / 100.0) AS score
+        FROM events e
+        JOIN users actor ON actor.id = e.actor_id AND actor.is_verified = TRUE
+        LEFT JOIN user_profiles ap ON ap.user_id = e.actor_id
+        WHERE e.actor_id <> e.user_id
+    ),
+    per_actor AS (
+        SELECT user_id, LEAST(SUM(score), $2) AS score
+        FROM weighted
+        GROUP BY user_id, actor_id
+    ),
+    raw AS (
+        SELECT p.user_id, COALESCE(SUM(pa.score), 0)::FLOAT8 AS score
+        FROM user_profiles p
+        LEFT JOIN per_actor pa ON pa.user_id = p.user_id
+        GROUP BY p.user_id
+    )
+    INSERT INTO fame_history (user_id, recorded_on, fame_rating, raw_score)
+    SELECT user_id, CURRENT_DATE,
+           CASE WHEN score > 0 THEN CEIL(100 * cume_dist() OVER (ORDER BY score))::INT ELSE 0 END,
+           score
+    FROM raw
+    ON CONFLICT (user_id, recorded_on) DO UPDATE
+    SET fame_rating = EXCLUDED.fame_rating, raw_score = EXCLUDED.raw_score
+`
+
+// fameHistoryRetentionDays durée de conservation de l'historique du fame rating
+const fameHistoryRetentionDays = 365
+
+// fameLockKey identifie le verrou consultatif qui réserve le recalcul à une instance
+const fameLockKey = 0x66616d65 // "fame"
+
+// ErrFameRecomputeRunning est retournée quand une autre instance recalcule déjà le fame rating
+var ErrFameRecomputeRunning = errors.New("recalcul du fame rating déjà en cours")
+
+// RecomputeFameRatings recalcule en une passe le fame rating de tous les profils,
+// enregistre le point du jour dans fame_history et retourne le nombre de profils modifiés
+func (r *PostgresProfileRepository) RecomputeFameRatings(params FameParams) (int64, error) {
+	var updated int64
+
+	err := database.WithTx(r.db, func(tx *sql.Tx) error {
+		var acquired bool
+		if err := tx.QueryRow("SELECT pg_try_advisory_xact_lock($1)", fameLockKey).Scan(&acquired); err != nil {
+			return fmt.Errorf("erreur lors du verrouillage du recalcul du fame: %w", err)
+		}
+		if !acquired {
+			return ErrFameRecomputeRunning
+		}
+
+		// La réputation des auteurs est lue avant la mise à jour des profils
+		if _, err := tx.Exec(fameScoresQuery, params.HalfLifeDays, params.ActorCap); err != nil {
+			return fmt.Errorf("erreur lors du calcul des scores de fame: %w", err)
+		}
+
+		result, err := tx.Exec(`
+            UPDATE user_profiles p
+            SET fame_rating = h.fame_rating
+            FROM fame_history h
+            WHERE h.user_id = p.user_id
+            AND h.recorded_on = CURRENT_DATE
+            AND p.fame_rating IS DISTINCT FROM h.fame_rating
+        `)
+		if err != nil {
+			return fmt.Errorf("erreur lors de la mise à jour des fame ratings: %w", err)
+		}
+		updated, _ = result.RowsAffected()
+
+		_, err = tx.Exec(
+			"DELETE FROM fame_history WHERE recorded_on < CURRENT_DATE - $1::INT",
+			fameHistoryRetentionDays,
+		)
+		if err != nil {
+			return fmt.Errorf("erreur lors de la purge de l'historique du fame: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return updated, nil
+}
+
+// GetFameHistory récupère l'évolution du fame rating sur les derniers jours
+func (r *PostgresProfileRepository) GetFameHistory(userID, days int) ([]FameHistoryPoint, error) {
+	query := `
+        SELECT recorded_on, fame_rating
+        FROM fame_history
+        WHERE user_id = $1 AND recorded_on > CURRENT_DATE - $2::INT
+        ORDER BY recorded_on
+    `
+
+	rows, err := r.db.Query(query, userID, days)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération de l'historique du fame: %w", err)
+	}
+	defer rows.Close()
+
+	history := []FameHistoryPoint{}
+	for rows.Next() {
+		var point FameHistoryPoint
+		if err := rows.Scan(&point.Date, &point.FameRating); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture de l'historique du fame: %w", err)
+		}
+		history = append(history, point)
+	}
+
+	return history, rows.Err()
+}
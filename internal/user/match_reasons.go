@@ -0,0 +1,170 @@
+package user
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Codes des raisons de suggestion
+const (
+	ReasonSharedInterests = "shared_interests"
+	ReasonDistance        = "distance"
+	ReasonSimilarAge      = "similar_age"
+	ReasonVeryPopular     = "very_popular"
+	ReasonSimilarToLikes  = "similar_to_likes"
+)
+
+// Seuils des raisons de suggestion
+const (
+	similarAgeGap       = 5   // Écart d'âge maximal (ans) pour "âge proche"
+	veryPopularScore    = 0.8 // Composante fame normalisée à partir de laquelle un profil est "très populaire"
+	similarToLikesScore = 0.5 // Affinité à partir de laquelle un profil est "proche de vos likes"
+	maxListedSharedTags = 5
+	defaultReasonLang   = "fr"
+)
+
+// MatchReason explique pourquoi un profil est proposé : Code et Params sont
+// destinés au client, Message est le libellé localisé
+type MatchReason struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+}
+
+// explainMatch construit les raisons d'une suggestion à partir des éléments de score
+func explainMatch(input ScoreInput, scores ScoreBreakdown) []MatchReason {
+	var reasons []MatchReason
+
+	if len(input.SharedTags) > 0 {
+		names := make([]string, 0, len(input.SharedTags))
+		for _, tag := range input.SharedTags {
+			names = append(names, tag.Name)
+		}
+		reasons = append(reasons, MatchReason{
+			Code:   ReasonSharedInterests,
+			Params: map[string]interface{}{"count": len(names), "tags": names},
+		})
+	}
+
+	reasons = append(reasons, MatchReason{
+		Code:   ReasonDistance,
+		Params: map[string]interface{}{"distance_km": int(math.Round(input.Distance))},
+	})
+
+	if input.Current.BirthDate != nil && input.Candidate.BirthDate != nil {
+		gap := calculateAge(*input.Current.BirthDate) - calculateAge(*input.Candidate.BirthDate)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap <= similarAgeGap {
+			reasons = append(reasons, MatchReason{
+				Code:   ReasonSimilarAge,
+				Params: map[string]interface{}{"age_gap": gap},
+			})
+		}
+	}
+
+	if scores.Fame >= veryPopularScore {
+		reasons = append(reasons, MatchReason{
+			Code:   ReasonVeryPopular,
+			Params: map[string]interface{}{"fame_rating": input.Candidate.FameRating},
+		})
+	}
+
+	if scores.Behavior >= similarToLikesScore {
+		reasons = append(reasons, MatchReason{Code: ReasonSimilarToLikes})
+	}
+
+	return localizeReasons(reasons, defaultReasonLang)
+}
+
+// LocalizeResults traduit les raisons des suggestions dans la langue demandée
+func LocalizeResults(results []SuggestedProfileResult, lang string) {
+	for i := range results {
+		results[i].Reasons = localizeReasons(results[i].Reasons, lang)
+	}
+}
+
+// PreferredLanguage retourne la langue de l'en-tête Accept-Language prise en charge (fr par défaut)
+func PreferredLanguage(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		lang := strings.ToLower(strings.TrimSpace(strings.SplitN(part, ";", 2)[0]))
+		if strings.HasPrefix(lang, "fr") {
+			return "fr"
+		}
+		if strings.HasPrefix(lang, "en") {
+			return "en"
+		}
+	}
+	return defaultReasonLang
+}
+
+func localizeReasons(reasons []MatchReason, lang string) []MatchReason {
+	for i := range reasons {
+		reasons[i].Message = reasonMessage(reasons[i], lang)
+	}
+	return reasons
+}
+
+// reasonMessage produit le libellé d'une raison dans la langue demandée
+func reasonMessage(reason MatchReason, lang string) string {
+	english := lang == "en"
+
+	switch reason.Code {
+	case ReasonSharedInterests:
+		tags, _ := reason.Params["tags"].([]string)
+		count := len(tags)
+		listed := tags
+		if len(listed) > maxListedSharedTags {
+			listed = listed[:maxListedSharedTags]
+		}
+		list := strings.Join(listed, ", ")
+		if len(tags) > len(listed) {
+			list += "…"
+		}
+		if english {
+			if count == 1 {
+				return fmt.Sprintf("1 shared interest: %s", list)
+			}
+			return fmt.Sprintf("%d shared interests: %s", count, list)
+		}
+		if count == 1 {
+			return fmt.Sprintf("1 intérêt commun : %s", list)
+		}
+		return fmt.Sprintf("%d intérêts communs : %s", count, list)
+
+	case ReasonDistance:
+		km, _ := reason.Params["distance_km"].(int)
+		if km < 1 {
+			if english {
+				return "Less than 1 km away"
+			}
+			return "À moins d'1 km"
+		}
+		if english {
+			return fmt.Sprintf("%d km away", km)
+		}
+		return fmt.Sprintf("À %d km", km)
+
+	case ReasonSimilarAge:
+		if english {
+			return "Similar age"
+		}
+		return "Âge proche"
+
+	case ReasonVeryPopular:
+		if english {
+			return "Very popular"
+		}
+		return "Très populaire"
+
+	case ReasonSimilarToLikes:
+		if english {
+			return "Liked by people with similar taste"
+		}
+		return "Apprécié par des personnes aux goûts proches des vôtres"
+	}
+
+	return reason.Code
+}
@@ -0,0 +1,100 @@
+package user
+
+import "strings"
+
+// InterestsFromPreference traduit une préférence sexuelle historique en ensemble
+// de genres recherchés. Les préférences hétéro/homosexuelles ne sont définies que
+// pour les genres binaires : nil est retourné lorsqu'aucune traduction n'existe.
+func InterestsFromPreference(gender Gender, preference SexualPreference) []string {
+	switch preference {
+	case PrefBisexual:
+		return []string{GenderMale, GenderFemale}
+	case PrefHomosexual:
+		if gender == GenderMale || gender == GenderFemale {
+			return []string{string(gender)}
+		}
+	case PrefHeterosexual:
+		if gender == GenderMale {
+			return []string{GenderFemale}
+		}
+		if gender == GenderFemale {
+			return []string{GenderMale}
+		}
+	}
+	return nil
+}
+
+// PreferenceFromInterests déduit le libellé historique correspondant à un ensemble
+// de genres recherchés (vide si aucun libellé ne le décrit fidèlement)
+func PreferenceFromInterests(gender Gender, interests []string) SexualPreference {
+	if len(interests) == 0 {
+		return ""
+	}
+
+	for _, preference := range []SexualPreference{PrefHeterosexual, PrefHomosexual, PrefBisexual} {
+		if sameGenders(InterestsFromPreference(gender, preference), interests) {
+			return preference
+		}
+	}
+	return ""
+}
+
+// IsMutuallyCompatible indique si deux profils s'intéressent l'un à l'autre :
+// le genre de chacun doit appartenir aux genres recherchés par l'autre
+func IsMutuallyCompatible(a, b *Profile) bool {
+	return containsGender(a.InterestedIn, b.Gender) && containsGender(b.InterestedIn, a.Gender)
+}
+
+func containsGender(genders []string, gender Gender) bool {
+	for _, g := range genders {
+		if g == string(gender) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameGenders compare deux ensembles de genres sans tenir compte de l'ordre
+func sameGenders(a, b []string) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+	for _, gender := range b {
+		if !containsGender(a, Gender(gender)) {
+			return false
+		}
+	}
+	return true
+}
+
+// uniqueGenders retire les doublons d'une liste de genres en conservant l'ordre
+func uniqueGenders(genders []string) []string {
+	unique := []string{}
+	for _, gender := range genders {
+		if !containsGender(unique, Gender(gender)) {
+			unique = append(unique, gender)
+		}
+	}
+	return unique
+}
+
+// genderLabels libellés français des genres, pour l'affichage
+var genderLabels = map[string]string{
+	GenderMale:      "Homme",
+	GenderFemale:    "Femme",
+	GenderNonBinary: "Non-binaire",
+	GenderOther:     "Autre",
+}
+
+// formatInterests construit le libellé affiché des genres recherchés
+func formatInterests(genders []string) string {
+	labels := make([]string, 0, len(genders))
+	for _, gender := range genders {
+		if label, ok := genderLabels[gender]; ok {
+			labels = append(labels, label)
+		} else {
+			labels = append(labels, gender)
+		}
+	}
+	return strings.Join(labels, ", ")
+}
@@ -0,0 +1,168 @@
+package user
+
+import (
+	"fmt"
+	"testing"
+)
+
+var allGenders = []Gender{GenderMale, GenderFemale, GenderNonBinary, GenderOther}
+
+// genderSubsets énumère les ensembles de genres recherchés, ensemble vide compris
+func genderSubsets() [][]string {
+	var subsets [][]string
+	for mask := 0; mask < 1<<len(allGenders); mask++ {
+		subset := []string{}
+		for i, gender := range allGenders {
+			if mask&(1<<i) != 0 {
+				subset = append(subset, string(gender))
+			}
+		}
+		subsets = append(subsets, subset)
+	}
+	return subsets
+}
+
+func profileOf(gender Gender, interests ...string) *Profile {
+	return &Profile{Gender: gender, InterestedIn: interests}
+}
+
+func TestIsMutuallyCompatible(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *Profile
+		want bool
+	}{
+		{"hétéro homme et femme", profileOf(GenderMale, GenderFemale), profileOf(GenderFemale, GenderMale), true},
+		{"homo hommes", profileOf(GenderMale, GenderMale), profileOf(GenderMale, GenderMale), true},
+		{"homo femmes", profileOf(GenderFemale, GenderFemale), profileOf(GenderFemale, GenderFemale), true},
+		{"hétéro hommes", profileOf(GenderMale, GenderFemale), profileOf(GenderMale, GenderFemale), false},
+		{"intérêt à sens unique", profileOf(GenderMale, GenderFemale), profileOf(GenderFemale, GenderFemale), false},
+		{"bi et homo", profileOf(GenderFemale, GenderMale, GenderFemale), profileOf(GenderFemale, GenderFemale), true},
+		{"non-binaires entre eux", profileOf(GenderNonBinary, GenderNonBinary), profileOf(GenderNonBinary, GenderNonBinary), true},
+		{"non-binaire et femme", profileOf(GenderNonBinary, GenderFemale), profileOf(GenderFemale, GenderNonBinary), true},
+		{"non-binaire ignoré par un bi", profileOf(GenderNonBinary, GenderMale), profileOf(GenderMale, GenderMale, GenderFemale), false},
+		{"autre et tous genres", profileOf(GenderOther, GenderMale), profileOf(GenderMale, GenderMale, GenderFemale, GenderNonBinary, GenderOther), true},
+		{"intérêts non renseignés", profileOf(GenderMale), profileOf(GenderFemale, GenderMale), false},
+		{"genre non renseigné", profileOf("", GenderFemale), profileOf(GenderFemale, GenderMale), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMutuallyCompatible(tt.a, tt.b); got != tt.want {
+				t.Errorf("IsMutuallyCompatible = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIsMutuallyCompatibleAllCombinations parcourt chaque couple de genres et
+// chaque couple d'ensembles recherchés : la compatibilité est l'intersection
+// symétrique des intérêts, indépendante de l'ordre des profils
+func TestIsMutuallyCompatibleAllCombinations(t *testing.T) {
+	subsets := genderSubsets()
+
+	for _, genderA := range allGenders {
+		for _, genderB := range allGenders {
+			for _, interestsA := range subsets {
+				for _, interestsB := range subsets {
+					a, b := profileOf(genderA, interestsA...), profileOf(genderB, interestsB...)
+					want := containsGender(interestsA, genderB) && containsGender(interestsB, genderA)
+
+					if got := IsMutuallyCompatible(a, b); got != want {
+						t.Errorf("%s %v / %s %v = %v, attendu %v", genderA, interestsA, genderB, interestsB, got, want)
+					}
+					if IsMutuallyCompatible(a, b) != IsMutuallyCompatible(b, a) {
+						t.Errorf("%s %v / %s %v : résultat dépendant de l'ordre", genderA, interestsA, genderB, interestsB)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestInterestsFromPreference(t *testing.T) {
+	tests := []struct {
+		gender     Gender
+		preference SexualPreference
+		want       []string
+	}{
+		{GenderMale, PrefHeterosexual, []string{GenderFemale}},
+		{GenderMale, PrefHomosexual, []string{GenderMale}},
+		{GenderMale, PrefBisexual, []string{GenderMale, GenderFemale}},
+		{GenderFemale, PrefHeterosexual, []string{GenderMale}},
+		{GenderFemale, PrefHomosexual, []string{GenderFemale}},
+		{GenderFemale, PrefBisexual, []string{GenderMale, GenderFemale}},
+		{GenderNonBinary, PrefHeterosexual, nil},
+		{GenderNonBinary, PrefHomosexual, nil},
+		{GenderNonBinary, PrefBisexual, []string{GenderMale, GenderFemale}},
+		{GenderOther, PrefHeterosexual, nil},
+		{GenderOther, PrefHomosexual, nil},
+		{GenderOther, PrefBisexual, []string{GenderMale, GenderFemale}},
+		{GenderMale, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %s", tt.gender, tt.preference), func(t *testing.T) {
+			got := InterestsFromPreference(tt.gender, tt.preference)
+			if len(got) != len(tt.want) || (len(got) > 0 && !sameGenders(got, tt.want)) {
+				t.Errorf("InterestsFromPreference = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreferenceFromInterests(t *testing.T) {
+	tests := []struct {
+		gender    Gender
+		interests []string
+		want      SexualPreference
+	}{
+		{GenderMale, []string{GenderFemale}, PrefHeterosexual},
+		{GenderMale, []string{GenderMale}, PrefHomosexual},
+		{GenderFemale, []string{GenderFemale, GenderMale}, PrefBisexual},
+		{GenderFemale, []string{GenderMale}, PrefHeterosexual},
+		{GenderNonBinary, []string{GenderMale, GenderFemale}, PrefBisexual},
+		{GenderNonBinary, []string{GenderNonBinary}, ""},
+		{GenderMale, []string{GenderFemale, GenderNonBinary}, ""},
+		{GenderMale, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %v", tt.gender, tt.interests), func(t *testing.T) {
+			if got := PreferenceFromInterests(tt.gender, tt.interests); got != tt.want {
+				t.Errorf("PreferenceFromInterests = %q, attendu %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLegacyPreferencesCompatibility vérifie que la traduction des préférences
+// historiques reproduit l'ancienne règle binaire
+func TestLegacyPreferencesCompatibility(t *testing.T) {
+	legacy := func(gender Gender, preference SexualPreference) *Profile {
+		return profileOf(gender, InterestsFromPreference(gender, preference)...)
+	}
+
+	tests := []struct {
+		name string
+		a, b *Profile
+		want bool
+	}{
+		{"hétéro homme / hétéro femme", legacy(GenderMale, PrefHeterosexual), legacy(GenderFemale, PrefHeterosexual), true},
+		{"hétéro homme / bi femme", legacy(GenderMale, PrefHeterosexual), legacy(GenderFemale, PrefBisexual), true},
+		{"hétéro homme / homo femme", legacy(GenderMale, PrefHeterosexual), legacy(GenderFemale, PrefHomosexual), false},
+		{"hétéro homme / hétéro homme", legacy(GenderMale, PrefHeterosexual), legacy(GenderMale, PrefHeterosexual), false},
+		{"homo homme / homo homme", legacy(GenderMale, PrefHomosexual), legacy(GenderMale, PrefHomosexual), true},
+		{"homo homme / bi homme", legacy(GenderMale, PrefHomosexual), legacy(GenderMale, PrefBisexual), true},
+		{"homo femme / bi homme", legacy(GenderFemale, PrefHomosexual), legacy(GenderMale, PrefBisexual), false},
+		{"bi homme / bi femme", legacy(GenderMale, PrefBisexual), legacy(GenderFemale, PrefBisexual), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsMutuallyCompatible(tt.a, tt.b); got != tt.want {
+				t.Errorf("IsMutuallyCompatible = %v, attendu %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,18 +2,20 @@ package user
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
-	"net"
 	"net/http"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/cduffaut/matcha/internal/audit"
 	"github.com/cduffaut/matcha/internal/chat"
 	"github.com/cduffaut/matcha/internal/models"
 	"github.com/cduffaut/matcha/internal/notifications"
+	"github.com/cduffaut/matcha/internal/outbox"
 	"github.com/cduffaut/matcha/internal/security"
 	"github.com/cduffaut/matcha/internal/session"
 	"github.com/cduffaut/matcha/internal/validation"
@@ -35,21 +37,24 @@ type ProfileHandlers struct {
 	profileService      *ProfileService
 	notificationService notifications.NotificationService
 	hub                 *chat.Hub
+	audit               *audit.Service
 }
 
 // NewProfileHandlers crée de nouveaux gestionnaires pour les profils
-func NewProfileHandlers(profileService *ProfileService, notificationService notifications.NotificationService, hub *chat.Hub) *ProfileHandlers {
+func NewProfileHandlers(profileService *ProfileService, notificationService notifications.NotificationService, hub *chat.Hub, auditService *audit.Service) *ProfileHandlers {
 	return &ProfileHandlers{
 		profileService:      profileService,
 		notificationService: notificationService,
 		hub:                 hub,
+		audit:               auditService,
 	}
 }
 
 // ProfileUpdateRequest représente les données pour la mise à jour d'un profil
 type ProfileUpdateRequest struct {
 	Gender           string     `json:"gender"`
-	SexualPreference string     `json:"sexual_preference"`
+	SexualPreference string     `json:"sexual_preference"` // Ancien format, traduit en interested_in
+	InterestedIn     []string   `json:"interested_in"`
 	Biography        string     `json:"biography"`
 	BirthDate        *time.Time `json:"birth_date"`
 	Latitude         float64    `json:"latitude"`
@@ -126,6 +131,9 @@ func (h *ProfileHandlers) UpdateProfileHandler(w http.ResponseWriter, r *http.Re
 	// Nettoyer et valider les entrées
 	req.Gender = validation.SanitizeInput(req.Gender)
 	req.SexualPreference = validation.SanitizeInput(req.SexualPreference)
+	for i, gender := range req.InterestedIn {
+		req.InterestedIn[i] = validation.SanitizeInput(gender)
+	}
 	req.Biography = validation.SanitizeInput(req.Biography)
 	req.LocationName = validation.SanitizeInput(req.LocationName)
 
@@ -142,6 +150,22 @@ func (h *ProfileHandlers) UpdateProfileHandler(w http.ResponseWriter, r *http.Re
 		validationErrors = append(validationErrors, err.(validation.ValidationError))
 	}
 
+	// Genres recherchés : fournis explicitement, ou déduits de l'ancienne préférence
+	interests := req.InterestedIn
+	if interests != nil {
+		if err := validation.ValidateInterestedIn(interests); err != nil {
+			validationErrors = append(validationErrors, err.(validation.ValidationError))
+		}
+	} else if req.SexualPreference != "" {
+		interests = InterestsFromPreference(Gender(req.Gender), SexualPreference(req.SexualPreference))
+		if interests == nil {
+			validationErrors = append(validationErrors, validation.ValidationError{
+				Field:   "interested_in",
+				Message: "précisez les genres qui vous intéressent",
+			})
+		}
+	}
+
 	// Valider EXPLICITEMENT la taille de la biographie
 	if len(req.Biography) > validation.MaxBiographyLength {
 		validationErrors = append(validationErrors, validation.ValidationError{
@@ -193,7 +217,7 @@ func (h *ProfileHandlers) UpdateProfileHandler(w http.ResponseWriter, r *http.Re
 			// Utiliser une validation spécialisée pour la biographie
 			if field.name == "biography" {
 				if err := security.ValidateBiographyContent(field.value); err != nil {
-					security.LogSuspiciousActivity(session.UserID, field.value, "/api/profile")
+					security.LogSuspiciousActivity(r, session.UserID, field.value, "/api/profile")
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusBadRequest)
 					json.NewEncoder(w).Encode(map[string]string{
@@ -204,7 +228,7 @@ func (h *ProfileHandlers) UpdateProfileHandler(w http.ResponseWriter, r *http.Re
 			} else {
 				// Pour les autres champs, utiliser la validation normale
 				if err := security.ValidateUserInput(field.value, field.name); err != nil {
-					security.LogSuspiciousActivity(session.UserID, field.value, "/api/profile")
+					security.LogSuspiciousActivity(r, session.UserID, field.value, "/api/profile")
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(http.StatusBadRequest)
 					json.NewEncoder(w).Encode(map[string]string{
@@ -227,7 +251,10 @@ func (h *ProfileHandlers) UpdateProfileHandler(w http.ResponseWriter, r *http.Re
 
 	// Mettre à jour les champs
 	profile.Gender = Gender(req.Gender)
-	profile.SexualPreference = SexualPreference(req.SexualPreference)
+	if interests != nil {
+		profile.InterestedIn = uniqueGenders(interests)
+	}
+	profile.SexualPreference = PreferenceFromInterests(profile.Gender, profile.InterestedIn)
 	profile.Biography = req.Biography
 	profile.BirthDate = req.BirthDate
 	profile.Latitude = req.Latitude
@@ -639,20 +666,25 @@ func (h *ProfileHandlers) GetUserProfileHandler(w http.ResponseWriter, r *http.R
 
 	// ✅ N'ENREGISTRER LA VISITE QUE SI CE N'EST PAS AJAX
 	if !isAjaxRequest {
-		// Enregistrer la visite
-		h.profileService.ViewProfile(session.UserID, userID)
-
-		// Créer notification en base
-		if h.notificationService != nil {
-			go func() {
-				if err := h.notificationService.NotifyProfileView(userID, session.UserID); err != nil {
-					fmt.Printf("Erreur notification vue: %v\n", err)
-				}
-			}()
+		// Enregistrer la visite ; celle d'un compte shadow-banni n'est pas notifiée
+		notify, err := h.profileService.ViewProfile(session.UserID, userID)
+		if err != nil {
+			fmt.Printf("Erreur lors de l'enregistrement de la visite: %v\n", err)
 		}
 
-		// Envoyer WebSocket
-		go h.sendProfileViewNotification(userID, session.UserID)
+		if notify {
+			// Créer notification en base
+			if h.notificationService != nil {
+				go func() {
+					if err := h.notificationService.NotifyProfileView(userID, session.UserID); err != nil {
+						fmt.Printf("Erreur notification vue: %v\n", err)
+					}
+				}()
+			}
+
+			// Envoyer WebSocket
+			go h.sendProfileViewNotification(userID, session.UserID)
+		}
 	}
 
 	// Récupérer le profil
@@ -708,9 +740,6 @@ func (h *ProfileHandlers) LikeUserHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	// ✅ ENVOYER NOTIFICATION WEBSOCKET
-	go h.sendLikeNotification(session.UserID, userID, matched)
-
 	// Succès
 	w.Header().Set("Content-Type", "application/json")
 	response := map[string]interface{}{
@@ -742,9 +771,6 @@ func (h *ProfileHandlers) UnlikeUserHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// ✅ ENVOYER NOTIFICATION WEBSOCKET
-	go h.sendUnlikeNotification(session.UserID, userID)
-
 	// Répondre avec succès
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -752,6 +778,204 @@ func (h *ProfileHandlers) UnlikeUserHandler(w http.ResponseWriter, r *http.Reque
 	})
 }
 
+// PassUserHandler écarte un profil des suggestions
+func (h *ProfileHandlers) PassUserHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := strconv.Atoi(pat.Param(r, "userID"))
+	if err != nil {
+		http.Error(w, "ID d'utilisateur invalide", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := h.profileService.PassUser(session.UserID, userID); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Profil écarté",
+	})
+}
+
+// UnpassUserHandler rend à nouveau proposable un profil écarté
+func (h *ProfileHandlers) UnpassUserHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	userID, err := strconv.Atoi(pat.Param(r, "userID"))
+	if err != nil {
+		http.Error(w, "ID d'utilisateur invalide", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.profileService.UnpassUser(session.UserID, userID); err != nil {
+		http.Error(w, "Erreur lors de l'annulation du pass", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{
+		"message": "Profil à nouveau proposable",
+	})
+}
+
+// UndoLastPassHandler annule le dernier profil écarté
+func (h *ProfileHandlers) UndoLastPassHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	passedID, err := h.profileService.UndoLastPass(session.UserID)
+	if err != nil {
+		http.Error(w, "Erreur lors de l'annulation du pass", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if passedID == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": "Aucun profil écarté à restaurer",
+		})
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Dernier pass annulé",
+		"user_id": passedID,
+	})
+}
+
+// GetFameHistoryHandler retourne l'évolution du fame rating de l'utilisateur (?days=, 90 par défaut)
+func (h *ProfileHandlers) GetFameHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	days := 90
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > 365 {
+			http.Error(w, "Nombre de jours invalide", http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	history, err := h.profileService.GetFameHistory(session.UserID, days)
+	if err != nil {
+		http.Error(w, "Erreur lors de la récupération de l'historique", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// GetDiscoveryPreferencesHandler retourne les préférences de découverte de l'utilisateur
+func (h *ProfileHandlers) GetDiscoveryPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.profileService.GetDiscoveryPreferences(session.UserID)
+	if err != nil {
+		http.Error(w, "Erreur lors de la récupération des préférences", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdateDiscoveryPreferencesHandler met à jour la tranche d'âge, la distance
+// maximale et, s'ils sont fournis, les genres recherchés du profil
+func (h *ProfileHandlers) UpdateDiscoveryPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	session, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<16)
+
+	var req DiscoveryPreferences
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "Invalid request format or too large"}`))
+		return
+	}
+
+	for i, gender := range req.Genders {
+		req.Genders[i] = validation.SanitizeInput(gender)
+	}
+
+	if validationErrors := validation.ValidateDiscoveryPreferences(req.MinAge, req.MaxAge, req.MaxDistanceKm, req.Genders); len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors":  validationErrors,
+			"message": "Données invalides",
+		})
+		return
+	}
+
+	req.UserID = session.UserID
+	if err := h.profileService.UpdateDiscoveryPreferences(&req); err != nil {
+		http.Error(w, "Erreur lors de l'enregistrement des préférences", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":     "Préférences de découverte mises à jour",
+		"preferences": req,
+	})
+}
+
+// HandleOutboxPush livre les messages WebSocket enregistrés dans l'outbox lors
+// d'un like, d'un match ou d'un unlike. Un destinataire hors ligne n'est pas une
+// erreur : il retrouvera la notification en base.
+func (h *ProfileHandlers) HandleOutboxPush(event *outbox.Event) error {
+	var payload outbox.WebSocketPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	switch notifications.NotificationType(payload.Type) {
+	case notifications.NotificationLike:
+		h.sendLikeNotification(payload.FromUserID, payload.ToUserID, false)
+	case notifications.NotificationMatch:
+		h.sendLikeNotification(payload.FromUserID, payload.ToUserID, true)
+	case notifications.NotificationUnlike:
+		h.sendUnlikeNotification(payload.FromUserID, payload.ToUserID)
+	default:
+		return fmt.Errorf("type de push WebSocket inconnu: %s", payload.Type)
+	}
+
+	return nil
+}
+
 func (h *ProfileHandlers) sendLikeNotification(fromUserID, toUserID int, isMatch bool) {
 	if h.hub == nil {
 		return
@@ -913,6 +1137,13 @@ func (h *ProfileHandlers) BlockUserHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    session.UserID,
+		Action:     audit.ActionBlock,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+
 	// Répondre avec succès
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -942,6 +1173,13 @@ func (h *ProfileHandlers) UnblockUserHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    session.UserID,
+		Action:     audit.ActionUnblock,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+
 	// Répondre avec succès
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -1026,7 +1264,10 @@ func (h *ProfileHandlers) ReportUserHandler(w http.ResponseWriter, r *http.Reque
 
 	// Décoder le corps de la requête
 	var req struct {
-		Reason string `json:"reason"`
+		Category   string `json:"category"`
+		Reason     string `json:"reason"`
+		MessageIDs []int  `json:"message_ids"`
+		PhotoIDs   []int  `json:"photo_ids"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Format de requête invalide", http.StatusBadRequest)
@@ -1040,11 +1281,42 @@ func (h *ProfileHandlers) ReportUserHandler(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Enregistrer le signalement
-	if err := h.profileService.ReportUser(session.UserID, userID, req.Reason); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	report := &UserReport{
+		ReporterID: session.UserID,
+		ReportedID: userID,
+		Category:   req.Category,
+		Reason:     strings.TrimSpace(req.Reason),
+		MessageIDs: req.MessageIDs,
+		PhotoIDs:   req.PhotoIDs,
+	}
+	if report.Category == "" {
+		report.Category = models.ReportOther
+	}
+
+	if err := h.profileService.ReportUser(report); err != nil {
+		switch {
+		case errors.Is(err, ErrReportPending):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, ErrInvalidReportCategory), errors.Is(err, ErrInvalidEvidence):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
 		return
 	}
 
+	h.audit.RecordRequest(r, &audit.Event{
+		ActorID:    session.UserID,
+		Action:     audit.ActionReport,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		Details: map[string]interface{}{
+			"category": report.Category,
+			"messages": len(report.MessageIDs),
+			"photos":   len(report.PhotoIDs),
+		},
+	})
+
 	// Répondre avec succès
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -1155,17 +1427,19 @@ func (h *ProfileHandlers) ProfilePageHandler(w http.ResponseWriter, r *http.Requ
                     <option value="" %s>Sélectionnez votre genre</option>
                     <option value="male" %s>Homme</option>
                     <option value="female" %s>Femme</option>
+                    <option value="non_binary" %s>Non-binaire</option>
+                    <option value="other" %s>Autre</option>
                 </select>
             </div>
 
             <div class="form-group">
-                <label for="sexual_preference">Préférence sexuelle *</label>
-                <select id="sexual_preference" name="sexual_preference" required>
-                    <option value="" %s>Sélectionnez votre préférence</option>
-                    <option value="heterosexual" %s>Hétérosexuel(le)</option>
-                    <option value="homosexual" %s>Homosexuel(le)</option>
-                    <option value="bisexual" %s>Bisexuel(le)</option>
-                </select>
+                <label>Genres qui m'intéressent *</label>
+                <div id="interested-in">
+                    <label><input type="checkbox" name="interested_in" value="male" %s> Hommes</label>
+                    <label><input type="checkbox" name="interested_in" value="female" %s> Femmes</label>
+                    <label><input type="checkbox" name="interested_in" value="non_binary" %s> Personnes non-binaires</label>
+                    <label><input type="checkbox" name="interested_in" value="other" %s> Autres</label>
+                </div>
             </div>
 
             <div class="form-group">
@@ -1182,7 +1456,7 @@ func (h *ProfileHandlers) ProfilePageHandler(w http.ResponseWriter, r *http.Requ
                 <label for="location">Localisation</label>
                 <input type="text" id="location" name="location" value="%s" readonly>
                 <button type="button" id="update-location">Mettre à jour ma localisation</button>
-                <button type="button" onclick="enableManualLocation()">Saisie manuelle</button>
+                <button type="button" id="manual-location">Saisie manuelle</button>
             </div>
             
             <div class="form-group">
@@ -1222,6 +1496,7 @@ func (h *ProfileHandlers) ProfilePageHandler(w http.ResponseWriter, r *http.Requ
         </div>
     </div>
     
+	<script src="/static/js/csrf.js"></script>
 	<script src="/static/js/global-error-handler.js"></script>
     <script src="/static/js/profile.js?v=%s"></script>
     <script src="/static/js/user_info.js?v=%s"></script>
@@ -1235,13 +1510,15 @@ func (h *ProfileHandlers) ProfilePageHandler(w http.ResponseWriter, r *http.Requ
 		// ✅ VARIABLES SÉCURISÉES : Informations utilisateur
 		safeFirstName, safeLastName, safeEmail,
 		// Variables du profil
-		getSelected(profile.Gender == ""),                       // Genre vide
-		getSelected(profile.Gender == "male"),                   // Genre masculin
-		getSelected(profile.Gender == "female"),                 // Genre féminin
-		getSelected(profile.SexualPreference == ""),             // Préférence vide
-		getSelected(profile.SexualPreference == "heterosexual"), // Hétérosexuel
-		getSelected(profile.SexualPreference == "homosexual"),   // Homosexuel
-		getSelected(profile.SexualPreference == "bisexual"),     // Bisexuel
+		getSelected(profile.Gender == ""),                                 // Genre vide
+		getSelected(profile.Gender == "male"),                             // Genre masculin
+		getSelected(profile.Gender == "female"),                           // Genre féminin
+		getSelected(profile.Gender == "non_binary"),                       // Genre non-binaire
+		getSelected(profile.Gender == "other"),                            // Autre genre
+		getChecked(containsGender(profile.InterestedIn, GenderMale)),      // Intéressé(e) par les hommes
+		getChecked(containsGender(profile.InterestedIn, GenderFemale)),    // Intéressé(e) par les femmes
+		getChecked(containsGender(profile.InterestedIn, GenderNonBinary)), // Intéressé(e) par les non-binaires
+		getChecked(containsGender(profile.InterestedIn, GenderOther)),     // Intéressé(e) par les autres genres
 		escapedBiography,                   // Biographie échappée
 		formatBirthDate(profile.BirthDate), // Date de naissance
 		escapedLocation,                    // Localisation échappée
@@ -1286,22 +1563,25 @@ func (h *ProfileHandlers) ViewUserProfilePageHandler(w http.ResponseWriter, r *h
 		return
 	}
 
-	// ✅ ENREGISTRER LA VISITE
-	if err := h.profileService.ViewProfile(userSession.UserID, userID); err != nil {
+	// ✅ ENREGISTRER LA VISITE (celle d'un compte shadow-banni n'est pas notifiée)
+	notify, err := h.profileService.ViewProfile(userSession.UserID, userID)
+	if err != nil {
 		fmt.Printf("Erreur lors de l'enregistrement de la visite: %v\n", err)
 	}
 
-	// ✅ CRÉER LA NOTIFICATION EN BASE DE DONNÉES
-	if h.notificationService != nil {
-		go func() {
-			if err := h.notificationService.NotifyProfileView(userID, userSession.UserID); err != nil {
-				fmt.Printf("Erreur lors de la création de la notification de vue: %v\n", err)
-			}
-		}()
-	}
+	if notify {
+		// ✅ CRÉER LA NOTIFICATION EN BASE DE DONNÉES
+		if h.notificationService != nil {
+			go func() {
+				if err := h.notificationService.NotifyProfileView(userID, userSession.UserID); err != nil {
+					fmt.Printf("Erreur lors de la création de la notification de vue: %v\n", err)
+				}
+			}()
+		}
 
-	// ✅ ENVOYER LA NOTIFICATION WEBSOCKET EN TEMPS RÉEL
-	go h.sendProfileViewNotification(userID, userSession.UserID)
+		// ✅ ENVOYER LA NOTIFICATION WEBSOCKET EN TEMPS RÉEL
+		go h.sendProfileViewNotification(userID, userSession.UserID)
+	}
 
 	// Récupérer le profil de l'utilisateur
 	profile, err := h.profileService.GetProfile(userID)
@@ -1357,6 +1637,7 @@ func (h *ProfileHandlers) ViewUserProfilePageHandler(w http.ResponseWriter, r *h
 	escapedUsername := html.EscapeString(user.Username)
 	escapedBiography := html.EscapeString(profile.Biography)
 	escapedLocation := html.EscapeString(profile.LocationName)
+	escapedInterests := html.EscapeString(formatInterests(profile.InterestedIn))
 
 	// Générer le nom à afficher (avec données échappées)
 	displayName := fmt.Sprintf("%s %s (@%s)", escapedFirstName, escapedLastName, escapedUsername)
@@ -1409,7 +1690,7 @@ func (h *ProfileHandlers) ViewUserProfilePageHandler(w http.ResponseWriter, r *h
             <div class="profile-info">
                 <p><strong>Âge:</strong> %s</p>
                 <p><strong>Genre:</strong> %s</p>
-                <p><strong>Orientation:</strong> %s</p>
+                <p><strong>Intéressé(e) par:</strong> %s</p>
                 <p><strong>Localisation:</strong> %s</p>
                 <p><strong>Fame Rating:</strong> %d/100</p>
             </div>
@@ -1432,14 +1713,15 @@ func (h *ProfileHandlers) ViewUserProfilePageHandler(w http.ResponseWriter, r *h
             <div class="profile-actions">
                 %s
                 %s
-                <button onclick="blockUser(%d)" class="block-button" type="button">🚫 Bloquer</button>
-                <button onclick="reportUser(%d)" class="report-button" type="button">🚨 Signaler</button>
+                <button data-action="block-user" data-user-id="%d" class="block-button" type="button">🚫 Bloquer</button>
+                <button data-action="report-user" data-user-id="%d" class="report-button" type="button">🚨 Signaler</button>
             </div>
         </div>
         
         <p><a href="/browse">← Retour à la recherche</a></p>
     </div>
 
+	<script src="/static/js/csrf.js"></script>
 	<script src="/static/js/global-error-handler.js"></script>
     <script src="/static/js/user_status.js?v=%s"></script>
     <script src="/static/js/navigation_active.js?v=%s"></script>
@@ -1457,7 +1739,7 @@ func (h *ProfileHandlers) ViewUserProfilePageHandler(w http.ResponseWriter, r *h
 		profile.UserID,                           // Status ID
 		age,                                      // Âge
 		string(profile.Gender),                   // Genre
-		string(profile.SexualPreference),         // Orientation
+		escapedInterests,                         // Genres recherchés
 		escapedLocation,                          // ✅ Localisation échappée
 		profile.FameRating,                       // Fame rating
 		escapedBiography,                         // ✅ Biographie échappée
@@ -1542,6 +1824,7 @@ func (h *ProfileHandlers) LikesPageHandler(w http.ResponseWriter, r *http.Reques
         <p><a href="/profile">← Retour au profil</a></p>
     </div>
 
+	<script src="/static/js/csrf.js"></script>
 	<script src="/static/js/global-error-handler.js"></script>
 	<script src="/static/js/user_status.js"></script>
     <script src="/static/js/navigation_active.js"></script>
@@ -1624,6 +1907,7 @@ func (h *ProfileHandlers) VisitorsPageHandler(w http.ResponseWriter, r *http.Req
         <p><a href="/profile">← Retour au profil</a></p>
     </div>
 
+	<script src="/static/js/csrf.js"></script>
 	<script src="/static/js/global-error-handler.js"></script>
     <script src="/static/js/user_status.js"></script>
     <script src="/static/js/navigation_active.js"></script>
@@ -1693,7 +1977,7 @@ func (h *ProfileHandlers) BlockedUsersPageHandler(w http.ResponseWriter, r *http
                     <div class="blocked-item">
                         <h4>%s %s (@%s)</h4>
                         <p>Bloqué le: %s</p>
-                        <button onclick="unblockUser(%d)">Débloquer</button>
+                        <button type="button" class="unblock-button" data-user-id="%d">Débloquer</button>
                     </div>
                 `, user.FirstName, user.LastName, user.Username,
 					blocked.CreatedAt.Format("02/01/2006 15:04"), user.ID)
@@ -1704,7 +1988,7 @@ func (h *ProfileHandlers) BlockedUsersPageHandler(w http.ResponseWriter, r *http
 
 	html += `
         <p><a href="/profile">← Retour au profil</a></p>
-        <script>
+        <script` + security.NonceAttr(r.Context()) + `>
             async function unblockUser(userId) {
                 if (!confirm('Voulez-vous débloquer cet utilisateur ?')) return;
                 
@@ -1721,8 +2005,15 @@ func (h *ProfileHandlers) BlockedUsersPageHandler(w http.ResponseWriter, r *http
                     alert('Erreur lors du déblocage');
                 }
             }
+
+            document.querySelectorAll('.unblock-button').forEach(function(button) {
+                button.addEventListener('click', function() {
+                    unblockUser(button.dataset.userId);
+                });
+            });
         </script>
 
+		<script src="/static/js/csrf.js"></script>
 		<script src="/static/js/global-error-handler.js"></script>
 		<script src="/static/js/user_status.js"></script>
 		<script src="/static/js/navigation_active.js"></script>
@@ -1744,6 +2035,13 @@ func getSelected(condition bool) string {
 	return ""
 }
 
+func getChecked(condition bool) string {
+	if condition {
+		return "checked"
+	}
+	return ""
+}
+
 func getDisabled(condition bool) string {
 	if condition {
 		return "disabled"
@@ -1869,15 +2167,15 @@ func renderUserTags(tags []Tag) string {
 // renderLikeButton génère le bouton de like/unlike
 func renderLikeButton(userID int, liked bool, matched bool) string {
 	if liked {
-		return fmt.Sprintf(`<button onclick="unlikeUser(%d)" class="unlike-button" type="button">💔 Ne plus liker</button>`, userID)
+		return fmt.Sprintf(`<button data-action="unlike-user" data-user-id="%d" class="unlike-button" type="button">💔 Ne plus liker</button>`, userID)
 	}
-	return fmt.Sprintf(`<button onclick="likeUser(%d)" class="like-button" type="button">👍 Liker</button>`, userID)
+	return fmt.Sprintf(`<button data-action="like-user" data-user-id="%d" class="like-button" type="button">👍 Liker</button>`, userID)
 }
 
 // renderChatButton génère le bouton de chat si match
 func renderChatButton(userID int, matched bool) string {
 	if matched {
-		return fmt.Sprintf(`<button onclick="openChat(%d)" class="chat-button" type="button">💬 Discuter</button>`, userID)
+		return fmt.Sprintf(`<button data-action="open-chat" data-user-id="%d" class="chat-button" type="button">💬 Discuter</button>`, userID)
 	}
 	return ""
 }
@@ -1935,7 +2233,7 @@ func (h *ProfileHandlers) IPGeolocationHandler(w http.ResponseWriter, r *http.Re
 	}
 
 	// Récupérer l'IP du client
-	clientIP := getClientIP(r)
+	clientIP := security.ClientIP(r)
 
 	// Pour le développement local, utiliser des coordonnées par défaut
 	// Dans un vrai projet, vous utiliseriez un service de géolocalisation comme MaxMind GeoIP2
@@ -1960,31 +2258,6 @@ func (h *ProfileHandlers) IPGeolocationHandler(w http.ResponseWriter, r *http.Re
 
 // Fonctions utilitaires pour IPGeolocationHandler
 
-// getClientIP récupère l'IP réelle du client
-func getClientIP(r *http.Request) string {
-	// Vérifier les headers de proxy
-	if ip := r.Header.Get("X-Forwarded-For"); ip != "" {
-		// X-Forwarded-For peut contenir plusieurs IPs séparées par des virgules
-		ips := strings.Split(ip, ",")
-		return strings.TrimSpace(ips[0])
-	}
-
-	if ip := r.Header.Get("X-Real-IP"); ip != "" {
-		return ip
-	}
-
-	if ip := r.Header.Get("X-Client-IP"); ip != "" {
-		return ip
-	}
-
-	// Fallback sur RemoteAddr
-	ip, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
-	}
-	return ip
-}
-
 // isLocalIP vérifie si une IP est locale
 func isLocalIP(ip string) bool {
 	localIPs := []string{
@@ -1,7 +1,11 @@
 package user
 
 import (
+	"database/sql"
+	"errors"
 	"time"
+
+	"github.com/cduffaut/matcha/internal/models"
 )
 
 // Préférences sexuelles
@@ -11,6 +15,14 @@ const (
 	PrefBisexual     = "bisexual"
 )
 
+// Genres
+const (
+	GenderMale      = "male"
+	GenderFemale    = "female"
+	GenderNonBinary = "non_binary"
+	GenderOther     = "other"
+)
+
 // Gender représente le genre d'un utilisateur
 type Gender string
 
@@ -24,7 +36,8 @@ type Profile struct {
 	FirstName        string           `db:"first_name"`
 	LastName         string           `db:"last_name"`
 	Gender           Gender           `db:"gender"`
-	SexualPreference SexualPreference `db:"sexual_preference"`
+	SexualPreference SexualPreference `db:"sexual_preference"` // Libellé indicatif, déduit de InterestedIn
+	InterestedIn     []string         `db:"interested_in"`     // Genres qui intéressent l'utilisateur
 	Biography        string           `db:"biography"`
 	BirthDate        *time.Time       `db:"birth_date"`
 	LocationName     string           `db:"location_name"`
@@ -57,12 +70,14 @@ type Tag struct {
 
 // Photo représente une photo de profil
 type Photo struct {
-	ID        int       `db:"id"`
-	UserID    int       `db:"user_id"`
-	FilePath  string    `db:"file_path"`
-	IsProfile bool      `db:"is_profile"`
-	CreatedAt time.Time `db:"created_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID        int    `db:"id"`
+	UserID    int    `db:"user_id"`
+	FilePath  string `db:"file_path"`
+	IsProfile bool   `db:"is_profile"`
+	// Empreinte SHA-256 du fichier, pour repérer une même image sur plusieurs comptes
+	ContentHash string    `db:"content_hash"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
 }
 
 // ProfileVisit représente une visite de profil
@@ -83,18 +98,78 @@ type UserLike struct {
 	Liker     interface{} `db:"-"`
 }
 
-// ReportData représente un signalement avec informations détaillées
-type ReportData struct {
-	ID           int         `json:"id"`
-	ReporterID   int         `json:"reporter_id"`
-	ReportedID   int         `json:"reported_id"`
-	Reason       string      `json:"reason"`
-	CreatedAt    time.Time   `json:"created_at"`
-	IsProcessed  bool        `json:"is_processed"`
-	ProcessedAt  *time.Time  `json:"processed_at"`
-	AdminComment string      `json:"admin_comment"`
-	ReporterInfo interface{} `json:"reporter_info"`
-	ReportedInfo interface{} `json:"reported_info"`
+// Bornes des pièces jointes d'un signalement
+const (
+	MaxReportMessages = 10
+	MaxReportPhotos   = 5
+)
+
+var (
+	// ErrInvalidReportCategory est retournée pour une catégorie de signalement inconnue
+	ErrInvalidReportCategory = errors.New("catégorie de signalement invalide")
+	// ErrInvalidEvidence est retournée pour une pièce jointe étrangère à la personne signalée
+	ErrInvalidEvidence = errors.New("les pièces jointes doivent être des messages échangés avec cet utilisateur ou des photos de son profil")
+	// ErrReportPending est retournée lorsqu'un signalement de même catégorie est encore en cours
+	ErrReportPending = errors.New("vous avez déjà un signalement en cours pour ce motif")
+)
+
+// UserReport représente un signalement déposé par un utilisateur, avec ses
+// pièces jointes : messages échangés avec la personne signalée et photos de son profil
+type UserReport struct {
+	ReporterID int    `db:"reporter_id"`
+	ReportedID int    `db:"reported_id"`
+	Category   string `db:"category"`
+	Reason     string `db:"reason"`
+	MessageIDs []int  `db:"evidence_message_ids"`
+	PhotoIDs   []int  `db:"evidence_photo_ids"`
+}
+
+// CandidateFilter décrit la présélection SQL des profils proposables à un utilisateur :
+// intérêts mutuels, complétude, blocages, likes, âge et fame rating sont filtrés en base
+type CandidateFilter struct {
+	UserID        int
+	Gender        Gender
+	InterestedIn  []string // Genres qui intéressent l'utilisateur
+	MinAge        int
+	MaxAge        int
+	MinFame       int
+	MaxFame       int
+	ExcludePassed bool          // Exclure les profils écartés par l'utilisateur
+	PassCooldown  time.Duration // Ancienneté au-delà de laquelle un profil écarté redevient proposable (0 = jamais)
+	ViewerAge     int           // Âge de l'utilisateur, confronté aux préférences des candidats (0 = inconnu)
+	SavedSearchID int           // Exclure les profils déjà remontés par cette recherche sauvegardée
+	ChangedSince  *time.Time    // Ne garder que les profils modifiés ou complétés depuis cet instant (nil = tous)
+}
+
+// DiscoveryPreferences représente les critères de découverte choisis par un utilisateur.
+// Les valeurs nulles signifient "sans limite" ; Genders reflète les genres recherchés
+// du profil (InterestedIn), seule source de vérité.
+type DiscoveryPreferences struct {
+	UserID        int       `json:"-"`
+	MinAge        int       `json:"min_age"`
+	MaxAge        int       `json:"max_age"`
+	MaxDistanceKm float64   `json:"max_distance"`
+	Genders       []string  `json:"genders"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// FameParams paramètre le recalcul du fame rating
+type FameParams struct {
+	HalfLifeDays float64 // Demi-vie du poids d'un événement
+	ActorCap     float64 // Contribution maximale d'un même utilisateur
+}
+
+// FameHistoryPoint représente le fame rating d'un utilisateur à une date
+type FameHistoryPoint struct {
+	Date       time.Time `json:"date"`
+	FameRating int       `json:"fame_rating"`
+}
+
+// Candidate associe un profil candidat aux informations publiques de son utilisateur
+type Candidate struct {
+	Profile  *Profile
+	User     *models.User
+	Distance float64 // km depuis le point de recherche
 }
 
 // ProfileRepository est l'interface pour accéder aux données des profils
@@ -108,29 +183,58 @@ type ProfileRepository interface {
 	GetAllTags() ([]Tag, error)
 	GetTagByID(tagID int) (*Tag, error)
 	GetPhotosByUserID(userID int) ([]Photo, error)
+	// ListPhotoPaths retourne le chemin de toutes les photos enregistrées
+	ListPhotoPaths() ([]string, error)
 	AddPhoto(photo *Photo) error
 	RemovePhoto(photoID int) error
 	SetProfilePhoto(photoID int) error
 	IsProfilePhoto(userID int, photoID int) (bool, error)
 	RecordVisit(visitorID, visitedID int) error
 	GetVisitorsForUser(userID int) ([]ProfileVisit, error)
-	LikeUser(likerID, likedID int) error
-	UnlikeUser(likerID, likedID int) error
+	// Méthodes transactionnelles : les effets de bord sont enregistrés dans l'outbox
+	// au sein de la même transaction
+	WithTx(fn func(tx *sql.Tx) error) error
+	LikeUser(tx *sql.Tx, likerID, likedID int) (int, error)
+	UnlikeUser(tx *sql.Tx, likerID, likedID int) (int, error)
+	CheckIfMatchedTx(tx *sql.Tx, user1ID, user2ID int) (bool, error)
 	GetLikesForUser(userID int) ([]UserLike, error)
 	CheckIfLiked(likerID, likedID int) (bool, error)
+	PassUser(passerID, passedID int) error
+	UnpassUser(passerID, passedID int) error
+	UndoLastPass(passerID int) (int, error)
 	CheckIfMatched(user1ID, user2ID int) (bool, error)
 	IsBlocked(userID, blockedID int) (bool, error)
-	ReportUser(reporterID, reportedID int, reason string) error
+	ReportUser(report *UserReport) error
 	GetAllProfiles() ([]*Profile, error)
+	FindWithinRadius(lat, lon, radiusKm float64, filter CandidateFilter) ([]*Candidate, error)
+	// FilterVisible retourne, parmi userIDs, les profils ni bloqués ni masqués par la modération
+	FilterVisible(viewerID int, userIDs []int) (map[int]bool, error)
+	GetDiscoveryPreferences(userID int) (*DiscoveryPreferences, error)
+	SaveDiscoveryPreferences(prefs *DiscoveryPreferences) error
 	UpdateLastConnection(userID int) error
 	SetOnline(userID int, isOnline bool) error
-	UpdateFameRating(userID int) error
-	BlockUser(blockerID, blockedID int) error
+	RecomputeFameRatings(params FameParams) (int64, error)
+	GetFameHistory(userID, days int) ([]FameHistoryPoint, error)
+	// BlockUser s'exécute dans la transaction qui supprime les likes entre les deux utilisateurs
+	BlockUser(tx *sql.Tx, blockerID, blockedID int) error
 	UnblockUser(blockerID, blockedID int) error
 	GetBlockedUsers(userID int) ([]BlockedUser, error)
 	CleanupInactiveUsers(timeoutMinutes int) error
 	GetUserOnlineStatus(userID int) (bool, *time.Time, error)
-	// Méthodes pour les rapports (admin)
-	GetAllReports() ([]ReportData, error)
-	ProcessReport(reportID int, adminComment, action string) error
+}
+
+// TagStatsRepository donne accès aux synonymes et aux fréquences des tags
+type TagStatsRepository interface {
+	GetAliases() (map[string]string, error)
+	GetUserTagNames() (map[int][]string, error)
+	GetTagStats() ([]TagStat, error)
+	ReplaceTagStats(stats []TagStat) error
+}
+
+// RecommendationRepository donne accès à l'historique des likes et aux voisins
+// calculés pour le filtrage collaboratif
+type RecommendationRepository interface {
+	GetLikePairs() ([]LikePair, error)
+	ReplaceNeighbors(neighbors []Neighbor) error
+	GetAffinities(userID int) (map[int]float64, error)
 }
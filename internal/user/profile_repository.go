@@ -4,14 +4,21 @@ package user
 import (
 	"database/sql"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/cduffaut/matcha/internal/database"
 	"github.com/cduffaut/matcha/internal/models"
+	"github.com/lib/pq"
 )
 
 // PostgresProfileRepository est l'implémentation PostgreSQL du ProfileRepository
 type PostgresProfileRepository struct {
 	db *sql.DB
+
+	// Disponibilité de l'extension earthdistance, détectée à la première recherche par rayon
+	spatialOnce   sync.Once
+	earthdistance bool
 }
 
 // NewPostgresProfileRepository crée un nouveau repository pour les profils
@@ -22,7 +29,7 @@ func NewPostgresProfileRepository(db *sql.DB) ProfileRepository {
 // GetByUserID récupère le profil d'un utilisateur par son ID
 func (r *PostgresProfileRepository) GetByUserID(userID int) (*Profile, error) {
 	query := `
-        SELECT user_id, gender, sexual_preferences, biography, birth_date, fame_rating, 
+        SELECT user_id, gender, sexual_preferences, interested_in, biography, birth_date, fame_rating, 
                latitude, longitude, location_name, created_at, updated_at
         FROM user_profiles
         WHERE user_id = $1
@@ -39,6 +46,7 @@ func (r *PostgresProfileRepository) GetByUserID(userID int) (*Profile, error) {
 		&profile.UserID,
 		&gender,
 		&sexPref,
+		pq.Array(&profile.InterestedIn),
 		&bio,
 		&birthDate, // Utiliser birthDate au lieu de &profile.BirthDate
 		&profile.FameRating,
@@ -110,9 +118,9 @@ func (r *PostgresProfileRepository) GetByUserID(userID int) (*Profile, error) {
 func (r *PostgresProfileRepository) Create(profile *Profile) error {
 	query := `
         INSERT INTO user_profiles (
-            user_id, gender, sexual_preferences, biography, birth_date, fame_rating, 
+            user_id, gender, sexual_preferences, interested_in, biography, birth_date, fame_rating, 
             latitude, longitude, location_name
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
         ON CONFLICT (user_id) DO NOTHING
         RETURNING created_at, updated_at
     `
@@ -122,6 +130,7 @@ func (r *PostgresProfileRepository) Create(profile *Profile) error {
 		profile.UserID,
 		profile.Gender,
 		profile.SexualPreference,
+		interestedInArray(profile.InterestedIn),
 		profile.Biography,
 		profile.BirthDate,
 		profile.FameRating,
@@ -144,11 +153,12 @@ func (r *PostgresProfileRepository) Update(profile *Profile) error {
         UPDATE user_profiles
         SET gender = $2, 
             sexual_preferences = $3, 
-            biography = $4, 
-            birth_date = $5, 
-            latitude = $6, 
-            longitude = $7, 
-            location_name = $8,
+            interested_in = $4, 
+            biography = $5, 
+            birth_date = $6, 
+            latitude = $7, 
+            longitude = $8, 
+            location_name = $9,
             updated_at = CURRENT_TIMESTAMP
         WHERE user_id = $1
         RETURNING updated_at
@@ -162,6 +172,7 @@ func (r *PostgresProfileRepository) Update(profile *Profile) error {
 		profile.UserID,
 		profile.Gender,
 		profile.SexualPreference,
+		interestedInArray(profile.InterestedIn),
 		profile.Biography,
 		profile.BirthDate,
 		profile.Latitude,
@@ -181,15 +192,17 @@ func (r *PostgresProfileRepository) Update(profile *Profile) error {
 	profile.UpdatedAt = updatedAt
 	fmt.Printf("SQL Update Success: Profil %d mis à jour\n", profile.UserID)
 
-	// CORRECTION: Mettre à jour le fame rating séparément
-	if err := r.UpdateFameRating(profile.UserID); err != nil {
-		fmt.Printf("Erreur mise à jour fame rating: %v\n", err)
-		// Ne pas faire échouer toute la mise à jour pour ça
-	}
-
 	return nil
 }
 
+// interestedInArray prépare les genres recherchés pour la colonne NOT NULL interested_in
+func interestedInArray(genders []string) interface{} {
+	if genders == nil {
+		genders = []string{}
+	}
+	return pq.Array(genders)
+}
+
 // AddTag ajoute un tag à un utilisateur
 func (r *PostgresProfileRepository) AddTag(userID int, tagName string) error {
 	// Vérifier si le tag existe déjà
@@ -301,6 +314,26 @@ func (r *PostgresProfileRepository) GetTagByID(tagID int) (*Tag, error) {
 	return &tag, nil
 }
 
+// ListPhotoPaths retourne le chemin de toutes les photos enregistrées
+func (r *PostgresProfileRepository) ListPhotoPaths() ([]string, error) {
+	rows, err := r.db.Query("SELECT file_path FROM user_photos")
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des chemins de photos: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un chemin de photo: %w", err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, rows.Err()
+}
+
 // AddPhoto ajoute une photo à un utilisateur
 func (r *PostgresProfileRepository) AddPhoto(photo *Photo) error {
 	// Compter les photos actuelles
@@ -321,8 +354,8 @@ func (r *PostgresProfileRepository) AddPhoto(photo *Photo) error {
 	}
 
 	query := `
-        INSERT INTO user_photos (user_id, file_path, is_profile)
-        VALUES ($1, $2, $3)
+        INSERT INTO user_photos (user_id, file_path, is_profile, content_hash)
+        VALUES ($1, $2, $3, NULLIF($4, ''))
         RETURNING id, created_at, updated_at
     `
 
@@ -331,6 +364,7 @@ func (r *PostgresProfileRepository) AddPhoto(photo *Photo) error {
 		photo.UserID,
 		photo.FilePath,
 		photo.IsProfile,
+		photo.ContentHash,
 	).Scan(&photo.ID, &photo.CreatedAt, &photo.UpdatedAt)
 
 	if err != nil {
@@ -401,7 +435,7 @@ func (r *PostgresProfileRepository) SetProfilePhoto(photoID int) error {
 	}
 
 	// Définir la nouvelle photo de profil
-	_, err = r.db.Exec("UPDATE user_photos SET is_profile = true WHERE id = $1", photoID)
+	_, err = r.db.Exec("UPDATE user_photos SET is_profile = true, updated_at = CURRENT_TIMESTAMP WHERE id = $1", photoID)
 	if err != nil {
 		return fmt.Errorf("erreur lors de la définition de la photo de profil: %w", err)
 	}
@@ -486,11 +520,6 @@ func (r *PostgresProfileRepository) RecordVisit(visitorID, visitedID int) error
 		return fmt.Errorf("erreur lors de l'enregistrement de la visite: %w", err)
 	}
 
-	// Mettre à jour le fame rating
-	if err := r.UpdateFameRating(visitedID); err != nil {
-		return fmt.Errorf("erreur lors de la mise à jour du fame rating: %w", err)
-	}
-
 	return nil
 }
 
@@ -502,6 +531,7 @@ func (r *PostgresProfileRepository) GetVisitorsForUser(userID int) ([]ProfileVis
         FROM profile_visits pv
         JOIN users u ON pv.visitor_id = u.id
         WHERE pv.visited_id = $1
+        AND u.account_status <> 'shadow_banned' -- Visites d'un compte shadow-banni : invisibles
         ORDER BY pv.visited_at DESC
     `
 
@@ -545,11 +575,35 @@ func (r *PostgresProfileRepository) GetVisitorsForUser(userID int) ([]ProfileVis
 	return visits, nil
 }
 
-// LikeUser enregistre un "like" entre deux utilisateurs
-func (r *PostgresProfileRepository) LikeUser(likerID, likedID int) error {
+// WithTx exécute fn dans une transaction sur la base des profils
+func (r *PostgresProfileRepository) WithTx(fn func(tx *sql.Tx) error) error {
+	return database.WithTx(r.db, fn)
+}
+
+// lockPair sérialise les likes/unlikes concurrents entre deux mêmes utilisateurs
+// jusqu'à la fin de la transaction, pour qu'un match mutuel ne soit jamais manqué
+func lockPair(tx *sql.Tx, user1ID, user2ID int) error {
+	low, high := user1ID, user2ID
+	if low > high {
+		low, high = high, low
+	}
+
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock($1, $2)", low, high); err != nil {
+		return fmt.Errorf("erreur lors du verrouillage de la paire d'utilisateurs: %w", err)
+	}
+	return nil
+}
+
+// LikeUser enregistre un "like" entre deux utilisateurs dans la transaction fournie.
+// Retourne l'ID du like créé, ou 0 si le like existait déjà.
+func (r *PostgresProfileRepository) LikeUser(tx *sql.Tx, likerID, likedID int) (int, error) {
+	if err := lockPair(tx, likerID, likedID); err != nil {
+		return 0, err
+	}
+
 	// Vérifier si l'utilisateur a une photo de profil
 	var hasProfilePhoto bool
-	err := r.db.QueryRow(`
+	err := tx.QueryRow(`
         SELECT EXISTS(
             SELECT 1 FROM user_photos
             WHERE user_id = $1 AND is_profile = true
@@ -557,11 +611,11 @@ func (r *PostgresProfileRepository) LikeUser(likerID, likedID int) error {
     `, likerID).Scan(&hasProfilePhoto)
 
 	if err != nil {
-		return fmt.Errorf("erreur lors de la vérification de la photo de profil: %w", err)
+		return 0, fmt.Errorf("erreur lors de la vérification de la photo de profil: %w", err)
 	}
 
 	if !hasProfilePhoto {
-		return fmt.Errorf("vous devez avoir une photo de profil pour liker un utilisateur")
+		return 0, fmt.Errorf("vous devez avoir une photo de profil pour liker un utilisateur")
 	}
 
 	// Enregistrer le like
@@ -569,36 +623,40 @@ func (r *PostgresProfileRepository) LikeUser(likerID, likedID int) error {
         INSERT INTO user_likes (liker_id, liked_id)
         VALUES ($1, $2)
         ON CONFLICT (liker_id, liked_id) DO NOTHING
+        RETURNING id
     `
 
-	_, err = r.db.Exec(query, likerID, likedID)
-	if err != nil {
-		return fmt.Errorf("erreur lors de l'enregistrement du like: %w", err)
+	var likeID int
+	err = tx.QueryRow(query, likerID, likedID).Scan(&likeID)
+	if err == sql.ErrNoRows {
+		return 0, nil
 	}
-
-	// Mettre à jour le fame rating
-	if err := r.UpdateFameRating(likedID); err != nil {
-		return fmt.Errorf("erreur lors de la mise à jour du fame rating: %w", err)
+	if err != nil {
+		return 0, fmt.Errorf("erreur lors de l'enregistrement du like: %w", err)
 	}
 
-	return nil
+	return likeID, nil
 }
 
-// UnlikeUser supprime un "like" entre deux utilisateurs
-func (r *PostgresProfileRepository) UnlikeUser(likerID, likedID int) error {
-	query := "DELETE FROM user_likes WHERE liker_id = $1 AND liked_id = $2"
-
-	_, err := r.db.Exec(query, likerID, likedID)
-	if err != nil {
-		return fmt.Errorf("erreur lors de la suppression du like: %w", err)
+// UnlikeUser supprime un "like" entre deux utilisateurs dans la transaction fournie.
+// Retourne l'ID du like supprimé, ou 0 s'il n'existait pas.
+func (r *PostgresProfileRepository) UnlikeUser(tx *sql.Tx, likerID, likedID int) (int, error) {
+	if err := lockPair(tx, likerID, likedID); err != nil {
+		return 0, err
 	}
 
-	// Mettre à jour le fame rating
-	if err := r.UpdateFameRating(likedID); err != nil {
-		return fmt.Errorf("erreur lors de la mise à jour du fame rating: %w", err)
+	query := "DELETE FROM user_likes WHERE liker_id = $1 AND liked_id = $2 RETURNING id"
+
+	var likeID int
+	err := tx.QueryRow(query, likerID, likedID).Scan(&likeID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("erreur lors de la suppression du like: %w", err)
 	}
 
-	return nil
+	return likeID, nil
 }
 
 // GetLikesForUser récupère les "likes" reçus par un utilisateur
@@ -609,6 +667,7 @@ func (r *PostgresProfileRepository) GetLikesForUser(userID int) ([]UserLike, err
         FROM user_likes ul
         JOIN users u ON ul.liker_id = u.id
         WHERE ul.liked_id = $1
+        AND u.account_status <> 'shadow_banned' -- Likes d'un compte shadow-banni : invisibles
         ORDER BY ul.created_at DESC
     `
 
@@ -671,7 +730,7 @@ func (r *PostgresProfileRepository) CheckIfLiked(likerID, likedID int) (bool, er
 // GetAllProfiles récupère tous les profils
 func (r *PostgresProfileRepository) GetAllProfiles() ([]*Profile, error) {
 	query := `
-        SELECT user_id, gender, sexual_preferences, biography, birth_date, fame_rating, 
+        SELECT user_id, gender, sexual_preferences, interested_in, biography, birth_date, fame_rating, 
                latitude, longitude, location_name, created_at, updated_at
         FROM user_profiles
     `
@@ -695,6 +754,7 @@ func (r *PostgresProfileRepository) GetAllProfiles() ([]*Profile, error) {
 			&profile.UserID,
 			&gender,
 			&sexPref,
+			pq.Array(&profile.InterestedIn),
 			&bio,
 			&birthDate, // Utiliser birthDate au lieu de &profile.BirthDate
 			&profile.FameRating,
@@ -774,6 +834,15 @@ func (r *PostgresProfileRepository) IsBlocked(userID1, userID2 int) (bool, error
 
 // CheckIfMatched vérifie si deux utilisateurs se sont mutuellement likés
 func (r *PostgresProfileRepository) CheckIfMatched(user1ID, user2ID int) (bool, error) {
+	return checkIfMatched(r.db, user1ID, user2ID)
+}
+
+// CheckIfMatchedTx vérifie un match en voyant les écritures non validées de la transaction
+func (r *PostgresProfileRepository) CheckIfMatchedTx(tx *sql.Tx, user1ID, user2ID int) (bool, error) {
+	return checkIfMatched(tx, user1ID, user2ID)
+}
+
+func checkIfMatched(q database.DBTX, user1ID, user2ID int) (bool, error) {
 	query := `
         SELECT EXISTS(
             SELECT 1 FROM user_likes
@@ -785,7 +854,7 @@ func (r *PostgresProfileRepository) CheckIfMatched(user1ID, user2ID int) (bool,
     `
 
 	var matched bool
-	err := r.db.QueryRow(query, user1ID, user2ID).Scan(&matched)
+	err := q.QueryRow(query, user1ID, user2ID).Scan(&matched)
 	if err != nil {
 		return false, fmt.Errorf("erreur lors de la vérification du match: %w", err)
 	}
@@ -793,55 +862,6 @@ func (r *PostgresProfileRepository) CheckIfMatched(user1ID, user2ID int) (bool,
 	return matched, nil
 }
 
-// UpdateFameRating met à jour le fame rating d'un utilisateur
-func (r *PostgresProfileRepository) UpdateFameRating(userID int) error {
-	// Calcul du fame rating basé sur le nombre de visites, de likes et de matchs
-	// Formule simple : visites = 1 point, likes = 2 points, matchs = 5 points
-	query := `
-        WITH user_stats AS (
-            SELECT 
-                COALESCE(visits.visit_count, 0) as visits,
-                COALESCE(likes.like_count, 0) as likes,
-                COALESCE(matches.match_count, 0) as matches
-            FROM (SELECT 1) dummy
-            LEFT JOIN (
-                SELECT COUNT(*) as visit_count
-                FROM profile_visits 
-                WHERE visited_id = $1
-            ) visits ON true
-            LEFT JOIN (
-                SELECT COUNT(*) as like_count
-                FROM user_likes 
-                WHERE liked_id = $1
-            ) likes ON true
-            LEFT JOIN (
-                SELECT COUNT(*) as match_count
-                FROM user_likes ul1
-                WHERE ul1.liked_id = $1
-                AND EXISTS (
-                    SELECT 1 
-                    FROM user_likes ul2 
-                    WHERE ul2.liker_id = ul1.liked_id 
-                    AND ul2.liked_id = ul1.liker_id
-                )
-            ) matches ON true
-        )
-        UPDATE user_profiles
-        SET fame_rating = (
-            SELECT LEAST(100, visits + (likes * 2) + (matches * 5))
-            FROM user_stats
-        )
-        WHERE user_id = $1
-    `
-
-	_, err := r.db.Exec(query, userID)
-	if err != nil {
-		return fmt.Errorf("erreur lors de la mise à jour du fame rating: %w", err)
-	}
-
-	return nil
-}
-
 // UnblockUser débloque un utilisateur
 func (r *PostgresProfileRepository) UnblockUser(blockerID, blockedID int) error {
 	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
@@ -907,15 +927,15 @@ func (r *PostgresProfileRepository) GetBlockedUsers(userID int) ([]BlockedUser,
 	return blockedUsers, nil
 }
 
-// BlockUser bloque un utilisateur
-func (r *PostgresProfileRepository) BlockUser(blockerID, blockedID int) error {
+// BlockUser bloque un utilisateur dans la transaction fournie
+func (r *PostgresProfileRepository) BlockUser(tx *sql.Tx, blockerID, blockedID int) error {
 	query := `
         INSERT INTO user_blocks (blocker_id, blocked_id)
         VALUES ($1, $2)
         ON CONFLICT (blocker_id, blocked_id) DO NOTHING
     `
 
-	_, err := r.db.Exec(query, blockerID, blockedID)
+	_, err := tx.Exec(query, blockerID, blockedID)
 	if err != nil {
 		return fmt.Errorf("erreur lors du blocage de l'utilisateur: %w", err)
 	}
@@ -923,86 +943,50 @@ func (r *PostgresProfileRepository) BlockUser(blockerID, blockedID int) error {
 	return nil
 }
 
-// ReportUser enregistre un signalement d'utilisateur
-func (r *PostgresProfileRepository) ReportUser(reporterID, reportedID int, reason string) error {
-	query := `
-		INSERT INTO user_reports (reporter_id, reported_id, reason)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (reporter_id, reported_id) DO UPDATE
-		SET reason = $3, created_at = CURRENT_TIMESTAMP
-	`
-
-	_, err := r.db.Exec(query, reporterID, reportedID, reason)
-	if err != nil {
-		return fmt.Errorf("erreur lors de l'enregistrement du signalement: %w", err)
+// ReportUser enregistre un signalement après avoir vérifié que les messages joints
+// ont été échangés avec la personne signalée et que les photos jointes sont les siennes
+func (r *PostgresProfileRepository) ReportUser(report *UserReport) error {
+	if len(report.MessageIDs) > 0 {
+		var count int
+		query := `
+			SELECT COUNT(*) FROM messages
+			WHERE id = ANY($1)
+			AND ((sender_id = $2 AND recipient_id = $3) OR (sender_id = $3 AND recipient_id = $2))
+		`
+		if err := r.db.QueryRow(query, pq.Array(report.MessageIDs), report.ReporterID, report.ReportedID).Scan(&count); err != nil {
+			return fmt.Errorf("erreur lors de la vérification des messages joints: %w", err)
+		}
+		if count != len(report.MessageIDs) {
+			return ErrInvalidEvidence
+		}
 	}
 
-	return nil
-}
+	if len(report.PhotoIDs) > 0 {
+		var count int
+		query := "SELECT COUNT(*) FROM user_photos WHERE id = ANY($1) AND user_id = $2"
+		if err := r.db.QueryRow(query, pq.Array(report.PhotoIDs), report.ReportedID).Scan(&count); err != nil {
+			return fmt.Errorf("erreur lors de la vérification des photos jointes: %w", err)
+		}
+		if count != len(report.PhotoIDs) {
+			return ErrInvalidEvidence
+		}
+	}
 
-// GetAllReports récupère tous les signalements avec informations des utilisateurs
-func (r *PostgresProfileRepository) GetAllReports() ([]ReportData, error) {
 	query := `
-		SELECT ur.id, ur.reporter_id, ur.reported_id, ur.reason, ur.created_at,
-		       ur.is_processed, ur.processed_at, ur.admin_comment,
-		       u1.username as reporter_username, u1.first_name as reporter_first_name, u1.last_name as reporter_last_name,
-		       u2.username as reported_username, u2.first_name as reported_first_name, u2.last_name as reported_last_name
-		FROM user_reports ur
-		JOIN users u1 ON ur.reporter_id = u1.id
-		JOIN users u2 ON ur.reported_id = u2.id
-		ORDER BY ur.created_at DESC
+		INSERT INTO user_reports (reporter_id, reported_id, category, reason, evidence_message_ids, evidence_photo_ids)
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`
 
-	rows, err := r.db.Query(query)
-	if err != nil {
-		return nil, fmt.Errorf("erreur lors de la récupération des signalements: %w", err)
+	_, err := r.db.Exec(query, report.ReporterID, report.ReportedID, report.Category, report.Reason,
+		pq.Array(report.MessageIDs), pq.Array(report.PhotoIDs))
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+		return ErrReportPending
 	}
-	defer rows.Close()
-
-	var reports []ReportData
-	for rows.Next() {
-		var report ReportData
-		var processedAt sql.NullTime
-		var adminComment sql.NullString
-		var reporterUsername, reporterFirstName, reporterLastName string
-		var reportedUsername, reportedFirstName, reportedLastName string
-
-		err := rows.Scan(
-			&report.ID, &report.ReporterID, &report.ReportedID, &report.Reason, &report.CreatedAt,
-			&report.IsProcessed, &processedAt, &adminComment,
-			&reporterUsername, &reporterFirstName, &reporterLastName,
-			&reportedUsername, &reportedFirstName, &reportedLastName,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("erreur lors de la lecture d'un signalement: %w", err)
-		}
-
-		if processedAt.Valid {
-			report.ProcessedAt = &processedAt.Time
-		}
-		if adminComment.Valid {
-			report.AdminComment = adminComment.String
-		}
-
-		// Créer les informations des utilisateurs
-		report.ReporterInfo = &models.User{
-			ID:        report.ReporterID,
-			Username:  reporterUsername,
-			FirstName: reporterFirstName,
-			LastName:  reporterLastName,
-		}
-
-		report.ReportedInfo = &models.User{
-			ID:        report.ReportedID,
-			Username:  reportedUsername,
-			FirstName: reportedFirstName,
-			LastName:  reportedLastName,
-		}
-
-		reports = append(reports, report)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement du signalement: %w", err)
 	}
 
-	return reports, nil
+	return nil
 }
 
 // GetUserOnlineStatus récupère le statut en ligne d'un utilisateur
@@ -1129,27 +1113,6 @@ func (r *PostgresProfileRepository) CleanupInactiveUsers(timeoutMinutes int) err
 	return nil
 }
 
-// ProcessReport marque un signalement comme traité
-func (r *PostgresProfileRepository) ProcessReport(reportID int, adminComment, action string) error {
-	query := `
-		UPDATE user_reports 
-		SET is_processed = TRUE, 
-		    processed_at = CURRENT_TIMESTAMP,
-		    admin_comment = $2
-		WHERE id = $1
-	`
-
-	_, err := r.db.Exec(query, reportID, adminComment)
-	if err != nil {
-		return fmt.Errorf("erreur lors du traitement du signalement: %w", err)
-	}
-
-	// Ici on pourrait ajouter une logique pour appliquer l'action (suspension, etc.)
-	// selon la valeur du paramètre 'action'
-
-	return nil
-}
-
 // UpdateLastConnection met à jour l'horodatage de la dernière connexion d'un utilisateur
 func (r *PostgresProfileRepository) UpdateLastConnection(userID int) error {
 	// Vérifier si la colonne last_connection existe dans la table user_profiles
@@ -1,6 +1,9 @@
 package user
 
 import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
@@ -10,6 +13,7 @@ import (
 
 	"github.com/cduffaut/matcha/internal/models"
 	"github.com/cduffaut/matcha/internal/notifications"
+	"github.com/cduffaut/matcha/internal/outbox"
 )
 
 // ProfileService fournit des services liés aux profils utilisateurs
@@ -18,15 +22,17 @@ type ProfileService struct {
 	userRepo            Repository
 	uploadsDir          string
 	notificationService notifications.NotificationService
+	events              outbox.Publisher
 }
 
 // NewProfileService crée un nouveau service de profil
-func NewProfileService(profileRepo ProfileRepository, userRepo Repository, uploadsDir string, notificationService notifications.NotificationService) *ProfileService {
+func NewProfileService(profileRepo ProfileRepository, userRepo Repository, uploadsDir string, notificationService notifications.NotificationService, events outbox.Publisher) *ProfileService {
 	return &ProfileService{
 		profileRepo:         profileRepo,
 		userRepo:            userRepo,
 		uploadsDir:          uploadsDir,
 		notificationService: notificationService,
+		events:              events,
 	}
 }
 
@@ -63,14 +69,8 @@ func (s *ProfileService) UpdateProfile(userID int, profile *Profile) error {
 
 // AddTag ajoute un tag au profil d'un utilisateur
 func (s *ProfileService) AddTag(userID int, tagName string) error {
-	// Nettoyer le nom du tag
-	tagName = strings.TrimSpace(tagName)
-	tagName = strings.ToLower(tagName)
-
-	// Vérifier que le tag commence par #
-	if !strings.HasPrefix(tagName, "#") {
-		tagName = "#" + tagName
-	}
+	// Forme normalisée (minuscules, sans accents), toujours préfixée par #
+	tagName = "#" + NormalizeTag(tagName)
 
 	// Ajouter le tag
 	if err := s.profileRepo.AddTag(userID, tagName); err != nil {
@@ -121,15 +121,23 @@ func (s *ProfileService) BlockUser(blockerID, blockedID int) error {
 		return fmt.Errorf("vous ne pouvez pas vous bloquer vous-même")
 	}
 
-	// Bloquer l'utilisateur
-	if err := s.profileRepo.BlockUser(blockerID, blockedID); err != nil {
+	// Bloquer l'utilisateur et supprimer les likes (donc le match) entre les deux
+	// dans une même transaction, sans notifier l'utilisateur bloqué
+	err := s.profileRepo.WithTx(func(tx *sql.Tx) error {
+		if err := s.profileRepo.BlockUser(tx, blockerID, blockedID); err != nil {
+			return err
+		}
+		for _, pair := range [][2]int{{blockerID, blockedID}, {blockedID, blockerID}} {
+			if _, err := s.profileRepo.UnlikeUser(tx, pair[0], pair[1]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("erreur lors du blocage de l'utilisateur: %w", err)
 	}
 
-	// Supprimer les likes mutuels s'ils existent
-	_ = s.profileRepo.UnlikeUser(blockerID, blockedID)
-	_ = s.profileRepo.UnlikeUser(blockedID, blockerID)
-
 	return nil
 }
 
@@ -224,13 +232,29 @@ func (s *ProfileService) GetPhotos(userID int) ([]Photo, error) {
 	return photos, nil
 }
 
-// ViewProfile enregistre une visite de profil
-func (s *ProfileService) ViewProfile(visitorID, visitedID int) error {
+// ViewProfile enregistre une visite de profil et indique si le profil visité
+// doit en être notifié : la visite d'un compte shadow-banni reste invisible
+func (s *ProfileService) ViewProfile(visitorID, visitedID int) (bool, error) {
 	if err := s.profileRepo.RecordVisit(visitorID, visitedID); err != nil {
-		return fmt.Errorf("erreur lors de l'enregistrement de la visite: %w", err)
+		return false, fmt.Errorf("erreur lors de l'enregistrement de la visite: %w", err)
 	}
 
-	return nil
+	hidden, err := s.isShadowBanned(visitorID)
+	if err != nil {
+		return false, err
+	}
+	return !hidden, nil
+}
+
+// isShadowBanned indique si les actions de l'utilisateur doivent rester
+// invisibles des autres : comme pour ses messages, un compte shadow-banni voit
+// ses actions aboutir sans que leurs destinataires n'en soient notifiés
+func (s *ProfileService) isShadowBanned(userID int) (bool, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return false, fmt.Errorf("erreur lors de la récupération du compte: %w", err)
+	}
+	return user.EffectiveStatus(time.Now()) == models.AccountShadowBanned, nil
 }
 
 // GetVisitors récupère les visiteurs du profil d'un utilisateur
@@ -255,8 +279,8 @@ func (s *ProfileService) LikeUser(likerID, likedID int) (bool, error) {
 		if likerProfile.Gender == "" {
 			return false, fmt.Errorf("complétez votre profil : renseignez votre genre")
 		}
-		if likerProfile.SexualPreference == "" {
-			return false, fmt.Errorf("complétez votre profil : renseignez vos préférences sexuelles")
+		if len(likerProfile.InterestedIn) == 0 {
+			return false, fmt.Errorf("complétez votre profil : indiquez les genres qui vous intéressent")
 		}
 		if strings.TrimSpace(likerProfile.Biography) == "" {
 			return false, fmt.Errorf("complétez votre profil : rédigez une biographie")
@@ -295,40 +319,220 @@ func (s *ProfileService) LikeUser(likerID, likedID int) (bool, error) {
 		return false, fmt.Errorf("ce profil n'est pas encore complet, vous ne pouvez pas le liker")
 	}
 
-	// 3. Enregistrer le like
-	if err := s.profileRepo.LikeUser(likerID, likedID); err != nil {
+	// Même règle que la présélection des candidats : intérêts mutuels
+	if !IsMutuallyCompatible(likerProfile, likedProfile) {
+		return false, fmt.Errorf("ce profil ne correspond pas aux genres qui vous intéressent")
+	}
+
+	hidden, err := s.isShadowBanned(likerID)
+	if err != nil {
 		return false, fmt.Errorf("erreur technique lors du like")
 	}
 
-	// 4. Créer une notification de like (ignorer les erreurs)
-	s.notificationService.NotifyLike(likedID, likerID)
+	// 3. Enregistrer le like et ses effets de bord dans une même transaction
+	var matched bool
+	err = s.profileRepo.WithTx(func(tx *sql.Tx) error {
+		likeID, err := s.profileRepo.LikeUser(tx, likerID, likedID)
+		if err != nil {
+			return err
+		}
+
+		matched, err = s.profileRepo.CheckIfMatchedTx(tx, likerID, likedID)
+		if err != nil {
+			return err
+		}
 
-	// 5. Vérifier s'il y a un match
-	matched, err := s.profileRepo.CheckIfMatched(likerID, likedID)
+		// Like déjà existant : ses effets ont été enregistrés lors du premier like.
+		// Like d'un compte shadow-banni : enregistré, mais sans notification ni push
+		if likeID == 0 || hidden {
+			return nil
+		}
+
+		events, err := likeEvents(likerID, likedID, likeID, matched)
+		if err != nil {
+			return err
+		}
+		return s.events.Enqueue(tx, events...)
+	})
 	if err != nil {
-		return false, fmt.Errorf("like envoyé mais vérification du match échouée")
+		return false, fmt.Errorf("erreur technique lors du like")
 	}
 
-	// 6. Si c'est un match, créer une notification de match (ignorer les erreurs)
+	// 4. Les notifications et le push temps réel sont livrés par le dispatcher ; le
+	// fame rating est recalculé par la tâche planifiée (FameJob)
+	s.events.Wake()
+
+	return matched, nil
+}
+
+// likeEvents construit les effets de bord d'un nouveau like, identifiés par l'ID du like
+func likeEvents(likerID, likedID, likeID int, matched bool) ([]*outbox.Event, error) {
+	builder := &eventBuilder{}
+
+	builder.add(outbox.EventNotification, fmt.Sprintf("notification:like:%d", likeID), outbox.NotificationPayload{
+		UserID:  likedID,
+		FromID:  likerID,
+		Type:    string(notifications.NotificationLike),
+		Message: notifications.DefaultMessage(notifications.NotificationLike),
+	})
+
+	pushType := string(notifications.NotificationLike)
 	if matched {
-		s.notificationService.NotifyMatch(likerID, likedID)
+		pushType = string(notifications.NotificationMatch)
+
+		// Un match notifie les deux utilisateurs
+		for _, pair := range [][2]int{{likerID, likedID}, {likedID, likerID}} {
+			builder.add(outbox.EventNotification, fmt.Sprintf("notification:match:%d:%d", likeID, pair[0]), outbox.NotificationPayload{
+				UserID:  pair[0],
+				FromID:  pair[1],
+				Type:    string(notifications.NotificationMatch),
+				Message: notifications.DefaultMessage(notifications.NotificationMatch),
+			})
+		}
 	}
 
-	return matched, nil
+	builder.add(outbox.EventWebSocketPush, fmt.Sprintf("ws:%s:%d", pushType, likeID), outbox.WebSocketPayload{
+		Type:       pushType,
+		FromUserID: likerID,
+		ToUserID:   likedID,
+	})
+
+	return builder.events, builder.err
 }
 
 // UnlikeUser supprime un "like" d'un utilisateur pour un autre
 func (s *ProfileService) UnlikeUser(likerID, likedID int) error {
-	if err := s.profileRepo.UnlikeUser(likerID, likedID); err != nil {
+	hidden, err := s.isShadowBanned(likerID)
+	if err != nil {
+		return err
+	}
+
+	err = s.profileRepo.WithTx(func(tx *sql.Tx) error {
+		likeID, err := s.profileRepo.UnlikeUser(tx, likerID, likedID)
+		if err != nil || likeID == 0 || hidden {
+			return err
+		}
+
+		builder := &eventBuilder{}
+		builder.add(outbox.EventNotification, fmt.Sprintf("notification:unlike:%d", likeID), outbox.NotificationPayload{
+			UserID:  likedID,
+			FromID:  likerID,
+			Type:    string(notifications.NotificationUnlike),
+			Message: notifications.DefaultMessage(notifications.NotificationUnlike),
+		})
+		builder.add(outbox.EventWebSocketPush, fmt.Sprintf("ws:unlike:%d", likeID), outbox.WebSocketPayload{
+			Type:       string(notifications.NotificationUnlike),
+			FromUserID: likerID,
+			ToUserID:   likedID,
+		})
+		if builder.err != nil {
+			return builder.err
+		}
+
+		return s.events.Enqueue(tx, builder.events...)
+	})
+	if err != nil {
 		return fmt.Errorf("erreur lors de la suppression du like: %w", err)
 	}
 
-	// Créer une notification d'unlike (ignorer les erreurs)
-	s.notificationService.NotifyUnlike(likedID, likerID)
+	s.events.Wake()
+	return nil
+}
 
+// PassUser écarte un profil des suggestions
+func (s *ProfileService) PassUser(passerID, passedID int) error {
+	if passerID == passedID {
+		return fmt.Errorf("vous ne pouvez pas vous écarter vous-même")
+	}
+
+	if _, err := s.userRepo.GetByID(passedID); err != nil {
+		return fmt.Errorf("utilisateur introuvable")
+	}
+
+	if err := s.profileRepo.PassUser(passerID, passedID); err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement du pass: %w", err)
+	}
+	return nil
+}
+
+// UnpassUser rend à nouveau proposable un profil écarté
+func (s *ProfileService) UnpassUser(passerID, passedID int) error {
+	if err := s.profileRepo.UnpassUser(passerID, passedID); err != nil {
+		return fmt.Errorf("erreur lors de l'annulation du pass: %w", err)
+	}
+	return nil
+}
+
+// UndoLastPass annule le dernier pass et retourne l'ID du profil concerné (0 si aucun)
+func (s *ProfileService) UndoLastPass(passerID int) (int, error) {
+	passedID, err := s.profileRepo.UndoLastPass(passerID)
+	if err != nil {
+		return 0, fmt.Errorf("erreur lors de l'annulation du dernier pass: %w", err)
+	}
+	return passedID, nil
+}
+
+// GetDiscoveryPreferences récupère les préférences de découverte d'un utilisateur
+func (s *ProfileService) GetDiscoveryPreferences(userID int) (*DiscoveryPreferences, error) {
+	prefs, err := s.profileRepo.GetDiscoveryPreferences(userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des préférences de découverte: %w", err)
+	}
+	return prefs, nil
+}
+
+// UpdateDiscoveryPreferences enregistre les préférences de découverte d'un utilisateur.
+// Des genres non vides remplacent les genres recherchés du profil, sinon ceux-ci
+// sont conservés ; la validation est faite par le handler.
+func (s *ProfileService) UpdateDiscoveryPreferences(prefs *DiscoveryPreferences) error {
+	profile, err := s.profileRepo.GetByUserID(prefs.UserID)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la récupération du profil: %w", err)
+	}
+
+	if len(prefs.Genders) > 0 {
+		profile.InterestedIn = uniqueGenders(prefs.Genders)
+		profile.SexualPreference = PreferenceFromInterests(profile.Gender, profile.InterestedIn)
+		if err := s.profileRepo.Update(profile); err != nil {
+			return fmt.Errorf("erreur lors de la mise à jour des genres recherchés: %w", err)
+		}
+	}
+	prefs.Genders = profile.InterestedIn
+
+	if err := s.profileRepo.SaveDiscoveryPreferences(prefs); err != nil {
+		return fmt.Errorf("erreur lors de l'enregistrement des préférences de découverte: %w", err)
+	}
 	return nil
 }
 
+// eventBuilder accumule des événements outbox en conservant la première erreur
+type eventBuilder struct {
+	events []*outbox.Event
+	err    error
+}
+
+func (b *eventBuilder) add(eventType outbox.EventType, dedupKey string, payload interface{}) {
+	if b.err != nil {
+		return
+	}
+
+	event, err := outbox.NewEvent(eventType, dedupKey, payload)
+	if err != nil {
+		b.err = err
+		return
+	}
+	b.events = append(b.events, event)
+}
+
+// GetFameHistory récupère l'évolution du fame rating d'un utilisateur
+func (s *ProfileService) GetFameHistory(userID, days int) ([]FameHistoryPoint, error) {
+	history, err := s.profileRepo.GetFameHistory(userID, days)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération de l'historique du fame: %w", err)
+	}
+	return history, nil
+}
+
 // GetLikes récupère les "likes" reçus par un utilisateur
 func (s *ProfileService) GetLikes(userID int) ([]UserLike, error) {
 	likes, err := s.profileRepo.GetLikesForUser(userID)
@@ -384,10 +588,12 @@ func (s *ProfileService) UploadPhotoSecure(userID int, fileData []byte, filename
 	}
 
 	// Si c'est la première photo, ou si isProfile est true, la définir comme photo de profil
+	sum := sha256.Sum256(fileData)
 	photo := &Photo{
-		UserID:    userID,
-		FilePath:  urlPath,
-		IsProfile: isProfile || len(photos) == 0,
+		UserID:      userID,
+		FilePath:    urlPath,
+		IsProfile:   isProfile || len(photos) == 0,
+		ContentHash: hex.EncodeToString(sum[:]),
 	}
 
 	// Ajouter la photo dans la base de données
@@ -443,25 +649,45 @@ func (s *ProfileService) GetUserOnlineStatus(userID int) (bool, *time.Time, erro
 	return s.profileRepo.GetUserOnlineStatus(userID)
 }
 
-// ReportUser signale un utilisateur
-func (s *ProfileService) ReportUser(reporterID, reportedID int, reason string) error {
+// ReportUser signale un utilisateur ; une même personne peut être signalée
+// plusieurs fois, mais un seul signalement par catégorie reste en cours
+func (s *ProfileService) ReportUser(report *UserReport) error {
 	// Vérifier qu'on ne se signale pas soi-même
-	if reporterID == reportedID {
+	if report.ReporterID == report.ReportedID {
 		return fmt.Errorf("vous ne pouvez pas vous signaler vous-même")
 	}
 
+	if !models.IsValidReportCategory(report.Category) {
+		return ErrInvalidReportCategory
+	}
+
+	report.MessageIDs = uniqueIDs(report.MessageIDs)
+	report.PhotoIDs = uniqueIDs(report.PhotoIDs)
+	if len(report.MessageIDs) > MaxReportMessages || len(report.PhotoIDs) > MaxReportPhotos {
+		return fmt.Errorf("au maximum %d messages et %d photos peuvent être joints", MaxReportMessages, MaxReportPhotos)
+	}
+
 	// Vérifier que l'utilisateur signalé existe
-	_, err := s.userRepo.GetByID(reportedID)
+	_, err := s.userRepo.GetByID(report.ReportedID)
 	if err != nil {
 		return fmt.Errorf("utilisateur à signaler non trouvé")
 	}
 
 	// Enregistrer le signalement
-	if err := s.profileRepo.ReportUser(reporterID, reportedID, reason); err != nil {
-		return fmt.Errorf("erreur lors du signalement: %w", err)
-	}
+	return s.profileRepo.ReportUser(report)
+}
 
-	return nil
+// uniqueIDs retire les doublons d'une liste d'identifiants en conservant l'ordre
+func uniqueIDs(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := []int{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
 }
 
 // IsProfileComplete vérifie si un profil remplit toutes les conditions obligatoires
@@ -471,8 +697,8 @@ func (s *ProfileService) IsProfileComplete(profile *Profile) bool {
 		return false
 	}
 
-	// 2. Préférence sexuelle obligatoire
-	if profile.SexualPreference == "" {
+	// 2. Genres recherchés obligatoires
+	if len(profile.InterestedIn) == 0 {
 		return false
 	}
 
@@ -0,0 +1,175 @@
+package user
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/models"
+	"github.com/cduffaut/matcha/internal/outbox"
+)
+
+// serviceProfileRepo simule les seules méthodes utilisées par les likes, les visites et les blocages
+type serviceProfileRepo struct {
+	ProfileRepository
+	profiles map[int]*Profile
+	matched  bool
+	likes    int
+	visits   int
+
+	blocks     [][2]int
+	failUnlike bool
+}
+
+func (r *serviceProfileRepo) GetByUserID(userID int) (*Profile, error) {
+	return r.profiles[userID], nil
+}
+
+// WithTx annule les blocages enregistrés par une transaction en échec
+func (r *serviceProfileRepo) WithTx(fn func(tx *sql.Tx) error) error {
+	blocks := len(r.blocks)
+	if err := fn(nil); err != nil {
+		r.blocks = r.blocks[:blocks]
+		return err
+	}
+	return nil
+}
+
+func (r *serviceProfileRepo) BlockUser(tx *sql.Tx, blockerID, blockedID int) error {
+	r.blocks = append(r.blocks, [2]int{blockerID, blockedID})
+	return nil
+}
+
+func (r *serviceProfileRepo) LikeUser(tx *sql.Tx, likerID, likedID int) (int, error) {
+	r.likes++
+	return r.likes, nil
+}
+
+func (r *serviceProfileRepo) UnlikeUser(tx *sql.Tx, likerID, likedID int) (int, error) {
+	if r.failUnlike {
+		return 0, errors.New("base indisponible")
+	}
+	return 1, nil
+}
+
+func (r *serviceProfileRepo) CheckIfMatchedTx(tx *sql.Tx, user1ID, user2ID int) (bool, error) {
+	return r.matched, nil
+}
+
+func (r *serviceProfileRepo) RecordVisit(visitorID, visitedID int) error {
+	r.visits++
+	return nil
+}
+
+// serviceUserRepo retourne les comptes avec l'état de modération demandé
+type serviceUserRepo struct {
+	Repository
+	statuses map[int]string
+}
+
+func (r *serviceUserRepo) GetByID(id int) (*models.User, error) {
+	return &models.User{ID: id, AccountStatus: r.statuses[id]}, nil
+}
+
+// recordingPublisher conserve les événements mis en file
+type recordingPublisher struct {
+	events []*outbox.Event
+}
+
+func (p *recordingPublisher) Enqueue(tx *sql.Tx, events ...*outbox.Event) error {
+	p.events = append(p.events, events...)
+	return nil
+}
+
+func (p *recordingPublisher) Wake() {}
+
+func completeProfile(userID int, gender Gender, interests ...string) *Profile {
+	birthDate := time.Date(1995, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := profileOf(gender, interests...)
+	profile.UserID = userID
+	profile.Biography = "bio"
+	profile.BirthDate = &birthDate
+	profile.Tags = []Tag{{ID: 1, Name: "#voyage"}}
+	profile.Photos = []Photo{{ID: userID, IsProfile: true}}
+	return profile
+}
+
+func newShadowBanService(likerStatus string, matched bool) (*ProfileService, *serviceProfileRepo, *recordingPublisher) {
+	profiles := &serviceProfileRepo{
+		profiles: map[int]*Profile{
+			1: completeProfile(1, GenderMale, GenderFemale),
+			2: completeProfile(2, GenderFemale, GenderMale),
+		},
+		matched: matched,
+	}
+	users := &serviceUserRepo{statuses: map[int]string{1: likerStatus, 2: models.AccountActive}}
+	events := &recordingPublisher{}
+	return NewProfileService(profiles, users, "", nil, events), profiles, events
+}
+
+func TestShadowBannedActionsStayInvisible(t *testing.T) {
+	tests := []struct {
+		status     string
+		matched    bool
+		wantEvents bool
+	}{
+		{models.AccountActive, false, true},
+		{models.AccountActive, true, true},
+		{models.AccountShadowBanned, false, false},
+		{models.AccountShadowBanned, true, false},
+	}
+
+	for _, tt := range tests {
+		service, profiles, events := newShadowBanService(tt.status, tt.matched)
+
+		// Le like aboutit du point de vue de son auteur, match compris
+		matched, err := service.LikeUser(1, 2)
+		if err != nil {
+			t.Fatalf("%s : LikeUser: %v", tt.status, err)
+		}
+		if matched != tt.matched || profiles.likes != 1 {
+			t.Errorf("%s : matched = %v après %d like(s), attendu %v après 1", tt.status, matched, profiles.likes, tt.matched)
+		}
+		if got := len(events.events) > 0; got != tt.wantEvents {
+			t.Errorf("%s : %d événement(s) du like mis en file", tt.status, len(events.events))
+		}
+
+		events.events = nil
+		if err := service.UnlikeUser(1, 2); err != nil {
+			t.Fatalf("%s : UnlikeUser: %v", tt.status, err)
+		}
+		if got := len(events.events) > 0; got != tt.wantEvents {
+			t.Errorf("%s : %d événement(s) du unlike mis en file", tt.status, len(events.events))
+		}
+
+		notify, err := service.ViewProfile(1, 2)
+		if err != nil {
+			t.Fatalf("%s : ViewProfile: %v", tt.status, err)
+		}
+		if notify != tt.wantEvents || profiles.visits != 1 {
+			t.Errorf("%s : visite notifiée = %v après %d visite(s), attendu %v après 1", tt.status, notify, profiles.visits, tt.wantEvents)
+		}
+	}
+}
+
+func TestBlockUserIsAtomic(t *testing.T) {
+	service, profiles, _ := newShadowBanService(models.AccountActive, true)
+
+	// La suppression des likes échoue : le blocage n'est pas conservé
+	profiles.failUnlike = true
+	if err := service.BlockUser(1, 2); err == nil {
+		t.Fatal("BlockUser devrait échouer")
+	}
+	if len(profiles.blocks) != 0 {
+		t.Errorf("blocages conservés malgré l'échec : %v", profiles.blocks)
+	}
+
+	profiles.failUnlike = false
+	if err := service.BlockUser(1, 2); err != nil {
+		t.Fatalf("BlockUser: %v", err)
+	}
+	if len(profiles.blocks) != 1 {
+		t.Errorf("blocages = %v, attendu un seul", profiles.blocks)
+	}
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cduffaut/matcha/internal/database"
+)
+
+// PostgresRecommendationRepository est l'implémentation PostgreSQL du RecommendationRepository
+type PostgresRecommendationRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRecommendationRepository crée un nouveau repository pour les recommandations
+func NewPostgresRecommendationRepository(db *sql.DB) RecommendationRepository {
+	return &PostgresRecommendationRepository{db: db}
+}
+
+// GetLikePairs récupère les likes, hors paires bloquées dans un sens ou dans l'autre
+func (r *PostgresRecommendationRepository) GetLikePairs() ([]LikePair, error) {
+	query := `
+        SELECT l.liker_id, l.liked_id
+        FROM user_likes l
+        WHERE NOT EXISTS (
+            SELECT 1 FROM user_blocks b
+            WHERE (b.blocker_id = l.liker_id AND b.blocked_id = l.liked_id)
+            OR (b.blocker_id = l.liked_id AND b.blocked_id = l.liker_id)
+        )
+        ORDER BY l.liker_id, l.liked_id
+    `
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des likes: %w", err)
+	}
+	defer rows.Close()
+
+	var likes []LikePair
+	for rows.Next() {
+		var like LikePair
+		if err := rows.Scan(&like.LikerID, &like.LikedID); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un like: %w", err)
+		}
+		likes = append(likes, like)
+	}
+
+	return likes, rows.Err()
+}
+
+// ReplaceNeighbors remplace l'ensemble des voisins en une transaction
+func (r *PostgresRecommendationRepository) ReplaceNeighbors(neighbors []Neighbor) error {
+	return database.WithTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM user_neighbors"); err != nil {
+			return fmt.Errorf("erreur lors de la purge des voisins: %w", err)
+		}
+
+		stmt, err := tx.Prepare("INSERT INTO user_neighbors (user_id, neighbor_id, similarity, rank) VALUES ($1, $2, $3, $4)")
+		if err != nil {
+			return fmt.Errorf("erreur lors de la préparation des voisins: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, neighbor := range neighbors {
+			if _, err := stmt.Exec(neighbor.UserID, neighbor.NeighborID, neighbor.Similarity, neighbor.Rank); err != nil {
+				return fmt.Errorf("erreur lors de l'enregistrement du voisin %d de %d: %w", neighbor.NeighborID, neighbor.UserID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// GetAffinities somme, pour chaque profil, sa similarité avec les profils likés
+// par l'utilisateur ("ceux qui ont liké X ont aussi liké Y")
+func (r *PostgresRecommendationRepository) GetAffinities(userID int) (map[int]float64, error) {
+	query := `
+        SELECT n.neighbor_id, SUM(n.similarity)
+        FROM user_likes l
+        JOIN user_neighbors n ON n.user_id = l.liked_id
+        WHERE l.liker_id = $1 AND n.neighbor_id <> $1
+        GROUP BY n.neighbor_id
+    `
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du calcul des affinités: %w", err)
+	}
+	defer rows.Close()
+
+	affinities := make(map[int]float64)
+	for rows.Next() {
+		var neighborID int
+		var affinity float64
+		if err := rows.Scan(&neighborID, &affinity); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'une affinité: %w", err)
+		}
+		affinities[neighborID] = affinity
+	}
+
+	return affinities, rows.Err()
+}
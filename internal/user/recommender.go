@@ -0,0 +1,110 @@
+package user
+
+import (
+	"math"
+	"sort"
+)
+
+// LikePair représente un like utilisé par le filtrage collaboratif
+type LikePair struct {
+	LikerID int
+	LikedID int
+}
+
+// Neighbor associe un profil à un profil voisin : les personnes qui ont liké
+// UserID ont aussi liké NeighborID
+type Neighbor struct {
+	UserID     int
+	NeighborID int
+	Similarity float64
+	Rank       int // 1 pour le voisin le plus proche
+}
+
+// ComputeNeighbors calcule la similarité item-item entre profils likés
+// (cosinus sur les ensembles de likers) et conserve les k plus proches voisins
+// de chaque profil. Le résultat est déterministe : les égalités sont départagées
+// par identifiant croissant, et les profils sont retournés dans l'ordre des IDs.
+func ComputeNeighbors(likes []LikePair, k int) []Neighbor {
+	likersOf := make(map[int]map[int]bool) // profil liké → likers
+	likedBy := make(map[int][]int)         // liker → profils likés
+	for _, like := range likes {
+		if like.LikerID == like.LikedID {
+			continue
+		}
+		if likersOf[like.LikedID] == nil {
+			likersOf[like.LikedID] = make(map[int]bool)
+		}
+		if likersOf[like.LikedID][like.LikerID] {
+			continue
+		}
+		likersOf[like.LikedID][like.LikerID] = true
+		likedBy[like.LikerID] = append(likedBy[like.LikerID], like.LikedID)
+	}
+
+	// Nombre de likers communs pour chaque paire de profils likés par une même personne
+	cooccurrences := make(map[int]map[int]int)
+	for _, items := range likedBy {
+		for _, a := range items {
+			for _, b := range items {
+				if a == b {
+					continue
+				}
+				if cooccurrences[a] == nil {
+					cooccurrences[a] = make(map[int]int)
+				}
+				cooccurrences[a][b]++
+			}
+		}
+	}
+
+	items := make([]int, 0, len(cooccurrences))
+	for item := range cooccurrences {
+		items = append(items, item)
+	}
+	sort.Ints(items)
+
+	var neighbors []Neighbor
+	for _, item := range items {
+		candidates := make([]Neighbor, 0, len(cooccurrences[item]))
+		for other, common := range cooccurrences[item] {
+			similarity := float64(common) / math.Sqrt(float64(len(likersOf[item])*len(likersOf[other])))
+			candidates = append(candidates, Neighbor{UserID: item, NeighborID: other, Similarity: similarity})
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].Similarity != candidates[j].Similarity {
+				return candidates[i].Similarity > candidates[j].Similarity
+			}
+			return candidates[i].NeighborID < candidates[j].NeighborID
+		})
+		if k > 0 && len(candidates) > k {
+			candidates = candidates[:k]
+		}
+
+		for rank := range candidates {
+			candidates[rank].Rank = rank + 1
+		}
+		neighbors = append(neighbors, candidates...)
+	}
+
+	return neighbors
+}
+
+// normalizeAffinities ramène les affinités entre 0 et 1 (1 pour la plus forte)
+func normalizeAffinities(affinities map[int]float64) map[int]float64 {
+	max := 0.0
+	for _, affinity := range affinities {
+		if affinity > max {
+			max = affinity
+		}
+	}
+
+	normalized := make(map[int]float64, len(affinities))
+	if max == 0 {
+		return normalized
+	}
+	for userID, affinity := range affinities {
+		normalized[userID] = affinity / max
+	}
+	return normalized
+}
@@ -0,0 +1,46 @@
+package user
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecommenderJob recalcule périodiquement les voisins de chaque profil à partir
+// de l'historique des likes
+type RecommenderJob struct {
+	repo     RecommendationRepository
+	k        int
+	interval time.Duration
+}
+
+// NewRecommenderJob crée la tâche de recalcul des voisins (k voisins par profil)
+func NewRecommenderJob(repo RecommendationRepository, k int, interval time.Duration) *RecommenderJob {
+	return &RecommenderJob{repo: repo, k: k, interval: interval}
+}
+
+// Start lance le recalcul périodique, le premier immédiatement
+func (j *RecommenderJob) Start() {
+	go j.run()
+}
+
+func (j *RecommenderJob) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.Refresh(); err != nil {
+			fmt.Printf("Erreur recalcul des recommandations: %v\n", err)
+		}
+		<-ticker.C
+	}
+}
+
+// Refresh recalcule et enregistre les voisins de tous les profils likés
+func (j *RecommenderJob) Refresh() error {
+	likes, err := j.repo.GetLikePairs()
+	if err != nil {
+		return err
+	}
+
+	return j.repo.ReplaceNeighbors(ComputeNeighbors(likes, j.k))
+}
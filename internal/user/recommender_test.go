@@ -0,0 +1,234 @@
+package user
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// seedLikesPerUser nombre de likes attribués à chaque profil du seed
+const seedLikesPerUser = 10
+
+// seedLikes construit un historique de likes figé sur les 500 profils du seed :
+// chaque profil like les seedLikesPerUser profils compatibles (orientation
+// mutuelle) avec lesquels il partage le plus de tags, puis par fame décroissant
+func seedLikes(t testing.TB) []LikePair {
+	t.Helper()
+
+	profiles := readSeedCSV(t, "../../mock/user_profiles.csv")
+	tagRows := readSeedCSV(t, "../../mock/user_tags.csv")
+
+	tags := make(map[int]map[string]bool)
+	for _, row := range tagRows {
+		userID, _ := strconv.Atoi(row[0])
+		if tags[userID] == nil {
+			tags[userID] = make(map[string]bool)
+		}
+		tags[userID][row[1]] = true
+	}
+
+	type seedProfile struct {
+		profile *Profile
+		fame    int
+	}
+	var seed []seedProfile
+	for _, row := range profiles {
+		userID, _ := strconv.Atoi(row[0])
+		fame, _ := strconv.Atoi(row[4])
+		gender := Gender(row[1])
+		seed = append(seed, seedProfile{
+			profile: profileOf(gender, InterestsFromPreference(gender, SexualPreference(row[2]))...),
+			fame:    fame,
+		})
+		seed[len(seed)-1].profile.UserID = userID
+	}
+
+	sharedTags := func(a, b int) int {
+		shared := 0
+		for tag := range tags[a] {
+			if tags[b][tag] {
+				shared++
+			}
+		}
+		return shared
+	}
+
+	var likes []LikePair
+	for _, liker := range seed {
+		var candidates []seedProfile
+		for _, other := range seed {
+			if other.profile.UserID != liker.profile.UserID && IsMutuallyCompatible(liker.profile, other.profile) {
+				candidates = append(candidates, other)
+			}
+		}
+
+		likerID := liker.profile.UserID
+		sort.Slice(candidates, func(i, j int) bool {
+			a, b := candidates[i], candidates[j]
+			if sa, sb := sharedTags(likerID, a.profile.UserID), sharedTags(likerID, b.profile.UserID); sa != sb {
+				return sa > sb
+			}
+			if a.fame != b.fame {
+				return a.fame > b.fame
+			}
+			return a.profile.UserID < b.profile.UserID
+		})
+		if len(candidates) > seedLikesPerUser {
+			candidates = candidates[:seedLikesPerUser]
+		}
+
+		for _, candidate := range candidates {
+			likes = append(likes, LikePair{LikerID: likerID, LikedID: candidate.profile.UserID})
+		}
+	}
+
+	return likes
+}
+
+func readSeedCSV(t testing.TB, path string) [][]string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("fixture du seed introuvable: %v", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("lecture de %s: %v", path, err)
+	}
+	return rows[1:]
+}
+
+func TestComputeNeighbors(t *testing.T) {
+	// 1 et 2 ont liké 10 et 11 ; 3 a liké 10 et 12 ; 4 n'a liké que 13
+	likes := []LikePair{
+		{1, 10}, {1, 11},
+		{2, 10}, {2, 11},
+		{3, 10}, {3, 12},
+		{4, 13},
+	}
+
+	want := []Neighbor{
+		{UserID: 10, NeighborID: 11, Similarity: 2 / math.Sqrt(3*2), Rank: 1},
+		{UserID: 10, NeighborID: 12, Similarity: 1 / math.Sqrt(3*1), Rank: 2},
+		{UserID: 11, NeighborID: 10, Similarity: 2 / math.Sqrt(2*3), Rank: 1},
+		{UserID: 12, NeighborID: 10, Similarity: 1 / math.Sqrt(1*3), Rank: 1},
+	}
+
+	if got := ComputeNeighbors(likes, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeNeighbors = %+v, attendu %+v", got, want)
+	}
+}
+
+func TestComputeNeighborsIgnoresSelfAndDuplicateLikes(t *testing.T) {
+	clean := []LikePair{{1, 10}, {1, 11}, {2, 10}}
+	noisy := append([]LikePair{{1, 1}, {1, 10}, {2, 2}}, clean...)
+
+	if got, want := ComputeNeighbors(noisy, 0), ComputeNeighbors(clean, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("ComputeNeighbors = %+v, attendu %+v", got, want)
+	}
+}
+
+func TestComputeNeighborsKeepsTopKWithIDTieBreak(t *testing.T) {
+	// Un même liker pour tous : les voisins de 10 sont à égalité
+	likes := []LikePair{{1, 14}, {1, 12}, {1, 10}, {1, 13}, {1, 11}}
+
+	var got []int
+	for _, neighbor := range ComputeNeighbors(likes, 2) {
+		if neighbor.UserID == 10 {
+			got = append(got, neighbor.NeighborID)
+		}
+	}
+
+	if want := []int{11, 12}; !reflect.DeepEqual(got, want) {
+		t.Errorf("voisins de 10 = %v, attendu %v", got, want)
+	}
+}
+
+func TestComputeNeighborsSeedFixture(t *testing.T) {
+	const k = 20
+	likes := seedLikes(t)
+	if len(likes) == 0 {
+		t.Fatal("aucun like généré à partir du seed")
+	}
+
+	neighbors := ComputeNeighbors(likes, k)
+
+	// Le résultat ne dépend pas de l'ordre des likes
+	reversed := make([]LikePair, len(likes))
+	for i, like := range likes {
+		reversed[len(likes)-1-i] = like
+	}
+	if !reflect.DeepEqual(ComputeNeighbors(reversed, k), neighbors) {
+		t.Fatal("ComputeNeighbors dépend de l'ordre des likes")
+	}
+
+	// Valeurs de référence du seed : toute variation du calcul doit être délibérée
+	if len(likes) != 5000 || len(neighbors) != 8180 {
+		t.Errorf("%d likes et %d voisins, attendu 5000 et 8180", len(likes), len(neighbors))
+	}
+	var top []int
+	for _, neighbor := range neighbors {
+		if neighbor.UserID == 121 && neighbor.Rank <= 3 {
+			top = append(top, neighbor.NeighborID)
+		}
+	}
+	if want := []int{16, 218, 82}; !reflect.DeepEqual(top, want) {
+		t.Errorf("plus proches voisins du profil le plus liké = %v, attendu %v", top, want)
+	}
+
+	liked := make(map[int]bool)
+	for _, like := range likes {
+		liked[like.LikedID] = true
+	}
+
+	byUser := make(map[int][]Neighbor)
+	for _, neighbor := range neighbors {
+		byUser[neighbor.UserID] = append(byUser[neighbor.UserID], neighbor)
+	}
+
+	for userID, list := range byUser {
+		if !liked[userID] {
+			t.Errorf("profil %d jamais liké mais doté de voisins", userID)
+		}
+		if len(list) > k {
+			t.Errorf("profil %d : %d voisins, au plus %d attendus", userID, len(list), k)
+		}
+		for i, neighbor := range list {
+			if neighbor.Rank != i+1 {
+				t.Errorf("profil %d : rang %d en position %d", userID, neighbor.Rank, i)
+			}
+			if neighbor.NeighborID == userID || !liked[neighbor.NeighborID] {
+				t.Errorf("profil %d : voisin %d invalide", userID, neighbor.NeighborID)
+			}
+			if neighbor.Similarity <= 0 || neighbor.Similarity > 1+1e-9 {
+				t.Errorf("profil %d : similarité %v hors de ]0, 1]", userID, neighbor.Similarity)
+			}
+			if i > 0 {
+				previous := list[i-1]
+				if previous.Similarity < neighbor.Similarity ||
+					(previous.Similarity == neighbor.Similarity && previous.NeighborID > neighbor.NeighborID) {
+					t.Errorf("profil %d : voisins %d et %d mal ordonnés", userID, previous.NeighborID, neighbor.NeighborID)
+				}
+			}
+		}
+	}
+}
+
+func TestNormalizeAffinities(t *testing.T) {
+	got := normalizeAffinities(map[int]float64{1: 0.5, 2: 2, 3: 1})
+	want := map[int]float64{1: 0.25, 2: 1, 3: 0.5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeAffinities = %v, attendu %v", got, want)
+	}
+
+	if got := normalizeAffinities(map[int]float64{1: 0}); len(got) != 0 {
+		t.Errorf("normalizeAffinities sans affinité = %v, attendu vide", got)
+	}
+}
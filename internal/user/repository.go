@@ -41,7 +41,8 @@ func (r *PostgresRepository) Create(user *models.User) error {
 // GetByID récupère un utilisateur par son ID
 func (r *PostgresRepository) GetByID(id int) (*models.User, error) {
 	query := `
-        SELECT id, username, email, first_name, last_name, password, is_verified, 
+        SELECT id, username, email, first_name, last_name, password, is_verified, role,
+               account_status, suspended_until, COALESCE(status_reason, ''),
                verification_token, reset_token, reset_token_expiry, created_at, updated_at
         FROM users
         WHERE id = $1
@@ -56,6 +57,10 @@ func (r *PostgresRepository) GetByID(id int) (*models.User, error) {
 		&user.LastName,
 		&user.Password,
 		&user.IsVerified,
+		&user.Role,
+		&user.AccountStatus,
+		&user.SuspendedUntil,
+		&user.StatusReason,
 		&user.VerificationToken,
 		&user.ResetToken,
 		&user.ResetTokenExpiry,
@@ -76,7 +81,8 @@ func (r *PostgresRepository) GetByID(id int) (*models.User, error) {
 // GetByUsername récupère un utilisateur par son nom d'utilisateur
 func (r *PostgresRepository) GetByUsername(username string) (*models.User, error) {
 	query := `
-        SELECT id, username, email, first_name, last_name, password, is_verified, 
+        SELECT id, username, email, first_name, last_name, password, is_verified, role,
+               account_status, suspended_until, COALESCE(status_reason, ''),
                verification_token, reset_token, reset_token_expiry, created_at, updated_at
         FROM users
         WHERE username = $1
@@ -91,6 +97,10 @@ func (r *PostgresRepository) GetByUsername(username string) (*models.User, error
 		&user.LastName,
 		&user.Password,
 		&user.IsVerified,
+		&user.Role,
+		&user.AccountStatus,
+		&user.SuspendedUntil,
+		&user.StatusReason,
 		&user.VerificationToken, // Maintenant un pointeur
 		&user.ResetToken,        // Maintenant un pointeur
 		&user.ResetTokenExpiry,  // Maintenant un pointeur
@@ -111,7 +121,8 @@ func (r *PostgresRepository) GetByUsername(username string) (*models.User, error
 // GetByEmail récupère un utilisateur par son email
 func (r *PostgresRepository) GetByEmail(email string) (*models.User, error) {
 	query := `
-        SELECT id, username, email, first_name, last_name, password, is_verified, 
+        SELECT id, username, email, first_name, last_name, password, is_verified, role,
+               account_status, suspended_until, COALESCE(status_reason, ''),
                verification_token, reset_token, reset_token_expiry, created_at, updated_at
         FROM users
         WHERE email = $1
@@ -126,6 +137,10 @@ func (r *PostgresRepository) GetByEmail(email string) (*models.User, error) {
 		&user.LastName,
 		&user.Password,
 		&user.IsVerified,
+		&user.Role,
+		&user.AccountStatus,
+		&user.SuspendedUntil,
+		&user.StatusReason,
 		&user.VerificationToken,
 		&user.ResetToken,
 		&user.ResetTokenExpiry,
@@ -170,7 +185,8 @@ func (r *PostgresRepository) SaveVerificationToken(id int, token string) error {
 // GetByVerificationToken récupère un utilisateur par son token de vérification
 func (r *PostgresRepository) GetByVerificationToken(token string) (*models.User, error) {
 	query := `
-        SELECT id, username, email, first_name, last_name, password, is_verified, 
+        SELECT id, username, email, first_name, last_name, password, is_verified, role,
+               account_status, suspended_until, COALESCE(status_reason, ''),
                verification_token, 
                COALESCE(reset_token, ''), -- Utiliser une chaîne vide plutôt que NULL
                COALESCE(reset_token_expiry, '0001-01-01 00:00:00'::timestamp), -- Utiliser une date par défaut
@@ -191,6 +207,10 @@ func (r *PostgresRepository) GetByVerificationToken(token string) (*models.User,
 		&user.LastName,
 		&user.Password,
 		&user.IsVerified,
+		&user.Role,
+		&user.AccountStatus,
+		&user.SuspendedUntil,
+		&user.StatusReason,
 		&user.VerificationToken,
 		&resetToken,
 		&resetTokenExpiry,
@@ -236,7 +256,8 @@ func (r *PostgresRepository) SaveResetToken(id int, token string, expiry time.Ti
 // GetByResetToken récupère un utilisateur par son token de réinitialisation
 func (r *PostgresRepository) GetByResetToken(token string) (*models.User, error) {
 	query := `
-        SELECT id, username, email, first_name, last_name, password, is_verified, 
+        SELECT id, username, email, first_name, last_name, password, is_verified, role,
+               account_status, suspended_until, COALESCE(status_reason, ''),
                verification_token, reset_token, reset_token_expiry, created_at, updated_at
         FROM users
         WHERE reset_token = $1 AND reset_token_expiry > CURRENT_TIMESTAMP
@@ -251,6 +272,10 @@ func (r *PostgresRepository) GetByResetToken(token string) (*models.User, error)
 		&user.LastName,
 		&user.Password,
 		&user.IsVerified,
+		&user.Role,
+		&user.AccountStatus,
+		&user.SuspendedUntil,
+		&user.StatusReason,
 		&user.VerificationToken,
 		&user.ResetToken,
 		&user.ResetTokenExpiry,
@@ -0,0 +1,197 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cduffaut/matcha/internal/session"
+	"github.com/cduffaut/matcha/internal/validation"
+	"goji.io/pat"
+)
+
+// SavedSearchHandlers gère les requêtes HTTP pour les recherches sauvegardées
+type SavedSearchHandlers struct {
+	service *SavedSearchService
+}
+
+// NewSavedSearchHandlers crée de nouveaux gestionnaires pour les recherches sauvegardées
+func NewSavedSearchHandlers(service *SavedSearchService) *SavedSearchHandlers {
+	return &SavedSearchHandlers{service: service}
+}
+
+// savedSearchRequest est le corps attendu pour créer ou modifier une recherche
+type savedSearchRequest struct {
+	Name        string        `json:"name"`
+	Filters     FilterOptions `json:"filters"`
+	EmailAlerts bool          `json:"email_alerts"`
+}
+
+// decodeSavedSearch lit, nettoie et valide une recherche ; écrit la réponse d'erreur le cas échéant
+func decodeSavedSearch(w http.ResponseWriter, r *http.Request) (*SavedSearch, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, 1<<16)
+
+	var req savedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "Invalid request format or too large"}`))
+		return nil, false
+	}
+
+	req.Name = validation.SanitizeInput(strings.TrimSpace(req.Name))
+	var tags []string
+	for _, tag := range req.Filters.Tags {
+		if tag = validation.SanitizeInput(strings.TrimSpace(tag)); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	req.Filters.Tags = tags
+	if req.Filters.SortOrder != "asc" {
+		req.Filters.SortOrder = "desc"
+	}
+
+	var validationErrors validation.ValidationErrors
+	if err := validation.ValidateSavedSearchName(req.Name); err != nil {
+		validationErrors = append(validationErrors, err.(validation.ValidationError))
+	}
+	f := req.Filters
+	validationErrors = append(validationErrors, validation.ValidateSearchFilters(f.MinAge, f.MaxAge, f.MinFame, f.MaxFame, f.MaxDistance, f.Tags)...)
+
+	if len(validationErrors) > 0 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"errors":  validationErrors,
+			"message": "Données invalides",
+		})
+		return nil, false
+	}
+
+	return &SavedSearch{
+		Name:        req.Name,
+		Filters:     req.Filters,
+		EmailAlerts: req.EmailAlerts,
+	}, true
+}
+
+// writeSavedSearchError traduit les erreurs métier en statut HTTP
+func writeSavedSearchError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrSavedSearchNotFound):
+		http.Error(w, "Recherche introuvable", http.StatusNotFound)
+	case errors.Is(err, ErrSavedSearchNameTaken):
+		http.Error(w, "Une recherche porte déjà ce nom", http.StatusConflict)
+	case errors.Is(err, ErrTooManySavedSearches):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, "Erreur lors de l'enregistrement de la recherche", http.StatusInternalServerError)
+	}
+}
+
+// ListHandler liste les recherches sauvegardées de l'utilisateur
+func (h *SavedSearchHandlers) ListHandler(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	searches, err := h.service.List(userSession.UserID)
+	if err != nil {
+		http.Error(w, "Erreur lors de la récupération des recherches", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"searches": searches,
+	})
+}
+
+// CreateHandler sauvegarde une nouvelle recherche
+func (h *SavedSearchHandlers) CreateHandler(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	search, ok := decodeSavedSearch(w, r)
+	if !ok {
+		return
+	}
+
+	search.UserID = userSession.UserID
+	if err := h.service.Create(search); err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Recherche sauvegardée",
+		"search":  search,
+	})
+}
+
+// UpdateHandler modifie une recherche sauvegardée
+func (h *SavedSearchHandlers) UpdateHandler(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	searchID, err := strconv.Atoi(pat.Param(r, "searchID"))
+	if err != nil {
+		http.Error(w, "ID de recherche invalide", http.StatusBadRequest)
+		return
+	}
+
+	search, ok := decodeSavedSearch(w, r)
+	if !ok {
+		return
+	}
+
+	search.ID = searchID
+	search.UserID = userSession.UserID
+	if err := h.service.Update(search); err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Recherche mise à jour",
+		"search":  search,
+	})
+}
+
+// DeleteHandler supprime une recherche sauvegardée
+func (h *SavedSearchHandlers) DeleteHandler(w http.ResponseWriter, r *http.Request) {
+	userSession, ok := session.FromContext(r.Context())
+	if !ok {
+		http.Error(w, "Utilisateur non connecté", http.StatusUnauthorized)
+		return
+	}
+
+	searchID, err := strconv.Atoi(pat.Param(r, "searchID"))
+	if err != nil {
+		http.Error(w, "ID de recherche invalide", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.Delete(userSession.UserID, searchID); err != nil {
+		writeSavedSearchError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Recherche supprimée",
+	})
+}
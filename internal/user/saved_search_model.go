@@ -0,0 +1,37 @@
+package user
+
+import "time"
+
+// SavedSearch représente des critères de recherche nommés par un utilisateur,
+// réexécutés périodiquement pour l'alerter des nouveaux profils correspondants
+type SavedSearch struct {
+	ID          int           `json:"id"`
+	UserID      int           `json:"-"`
+	Name        string        `json:"name"`
+	Filters     FilterOptions `json:"filters"`
+	EmailAlerts bool          `json:"email_alerts"`
+	LastRunAt   *time.Time    `json:"last_run_at"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+// SavedSearchRepository est l'interface pour accéder aux recherches sauvegardées
+type SavedSearchRepository interface {
+	// Create enregistre la recherche, ou retourne ErrTooManySavedSearches si
+	// l'utilisateur en a déjà limit
+	Create(search *SavedSearch, limit int) error
+	Update(search *SavedSearch) error
+	Delete(userID, searchID int) error
+	GetByID(userID, searchID int) (*SavedSearch, error)
+	ListByUser(userID int) ([]*SavedSearch, error)
+	ListAll() ([]*SavedSearch, error)
+	// RecordHits mémorise les profils remontés et retourne ceux qui ne l'étaient pas encore
+	RecordHits(searchID int, userIDs []int) ([]int, error)
+	ResetHits(searchID int) error
+	MarkRun(searchID int) error
+}
+
+// SearchAlertMailer envoie les alertes email des recherches sauvegardées
+type SearchAlertMailer interface {
+	SendSavedSearchAlert(to, username, searchName string, count int, searchLink string) error
+}
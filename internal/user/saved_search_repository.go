@@ -0,0 +1,248 @@
+package user
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cduffaut/matcha/internal/database"
+	"github.com/lib/pq"
+)
+
+// ErrSavedSearchNotFound est retournée pour une recherche inexistante ou appartenant à un autre utilisateur
+var ErrSavedSearchNotFound = fmt.Errorf("recherche sauvegardée introuvable")
+
+// ErrSavedSearchNameTaken est retournée lorsque l'utilisateur a déjà une recherche portant ce nom
+var ErrSavedSearchNameTaken = fmt.Errorf("une recherche porte déjà ce nom")
+
+// isUniqueViolation reconnaît la violation de la contrainte UNIQUE (user_id, name)
+func isUniqueViolation(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505"
+}
+
+// PostgresSavedSearchRepository est l'implémentation PostgreSQL du SavedSearchRepository
+type PostgresSavedSearchRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresSavedSearchRepository crée un nouveau repository pour les recherches sauvegardées
+func NewPostgresSavedSearchRepository(db *sql.DB) SavedSearchRepository {
+	return &PostgresSavedSearchRepository{db: db}
+}
+
+const savedSearchColumns = "id, user_id, name, filters, email_alerts, last_run_at, created_at, updated_at"
+
+func scanSavedSearch(scanner interface{ Scan(...interface{}) error }) (*SavedSearch, error) {
+	search := &SavedSearch{}
+	var filters []byte
+	var lastRunAt sql.NullTime
+
+	err := scanner.Scan(
+		&search.ID,
+		&search.UserID,
+		&search.Name,
+		&filters,
+		&search.EmailAlerts,
+		&lastRunAt,
+		&search.CreatedAt,
+		&search.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(filters, &search.Filters); err != nil {
+		return nil, fmt.Errorf("critères de la recherche %d illisibles: %w", search.ID, err)
+	}
+	if lastRunAt.Valid {
+		search.LastRunAt = &lastRunAt.Time
+	}
+
+	return search, nil
+}
+
+func (r *PostgresSavedSearchRepository) list(query string, args ...interface{}) ([]*SavedSearch, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des recherches sauvegardées: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []*SavedSearch{}
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'une recherche sauvegardée: %w", err)
+		}
+		searches = append(searches, search)
+	}
+
+	return searches, rows.Err()
+}
+
+// Create enregistre une nouvelle recherche si l'utilisateur en a moins de
+// limit ; le verrou sur son compte sérialise les créations concurrentes, pour
+// que le comptage et l'insertion ne puissent pas être devancés
+func (r *PostgresSavedSearchRepository) Create(search *SavedSearch, limit int) error {
+	filters, err := json.Marshal(search.Filters)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'encodage des critères: %w", err)
+	}
+
+	return database.WithTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("SELECT 1 FROM users WHERE id = $1 FOR UPDATE", search.UserID); err != nil {
+			return fmt.Errorf("erreur lors du verrouillage du compte: %w", err)
+		}
+
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM saved_searches WHERE user_id = $1", search.UserID).Scan(&count); err != nil {
+			return fmt.Errorf("erreur lors du comptage des recherches: %w", err)
+		}
+		if count >= limit {
+			return ErrTooManySavedSearches
+		}
+
+		query := `
+            INSERT INTO saved_searches (user_id, name, filters, email_alerts)
+            VALUES ($1, $2, $3, $4)
+            RETURNING id, created_at, updated_at
+        `
+
+		err := tx.QueryRow(query, search.UserID, search.Name, filters, search.EmailAlerts).
+			Scan(&search.ID, &search.CreatedAt, &search.UpdatedAt)
+		if isUniqueViolation(err) {
+			return ErrSavedSearchNameTaken
+		}
+		if err != nil {
+			return fmt.Errorf("erreur lors de la création de la recherche: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Update modifie le nom, les critères et les alertes d'une recherche
+func (r *PostgresSavedSearchRepository) Update(search *SavedSearch) error {
+	filters, err := json.Marshal(search.Filters)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'encodage des critères: %w", err)
+	}
+
+	query := `
+        UPDATE saved_searches
+        SET name = $3, filters = $4, email_alerts = $5, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND user_id = $2
+        RETURNING updated_at
+    `
+
+	err = r.db.QueryRow(query, search.ID, search.UserID, search.Name, filters, search.EmailAlerts).Scan(&search.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return ErrSavedSearchNotFound
+	}
+	if isUniqueViolation(err) {
+		return ErrSavedSearchNameTaken
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de la mise à jour de la recherche: %w", err)
+	}
+
+	return nil
+}
+
+// Delete supprime une recherche de l'utilisateur
+func (r *PostgresSavedSearchRepository) Delete(userID, searchID int) error {
+	result, err := r.db.Exec("DELETE FROM saved_searches WHERE id = $1 AND user_id = $2", searchID, userID)
+	if err != nil {
+		return fmt.Errorf("erreur lors de la suppression de la recherche: %w", err)
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return ErrSavedSearchNotFound
+	}
+
+	return nil
+}
+
+// GetByID récupère une recherche de l'utilisateur
+func (r *PostgresSavedSearchRepository) GetByID(userID, searchID int) (*SavedSearch, error) {
+	query := "SELECT " + savedSearchColumns + " FROM saved_searches WHERE id = $1 AND user_id = $2"
+
+	search, err := scanSavedSearch(r.db.QueryRow(query, searchID, userID))
+	if err == sql.ErrNoRows {
+		return nil, ErrSavedSearchNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération de la recherche: %w", err)
+	}
+
+	return search, nil
+}
+
+// ListByUser récupère les recherches d'un utilisateur
+func (r *PostgresSavedSearchRepository) ListByUser(userID int) ([]*SavedSearch, error) {
+	return r.list("SELECT "+savedSearchColumns+" FROM saved_searches WHERE user_id = $1 ORDER BY created_at, id", userID)
+}
+
+// ListAll récupère toutes les recherches, les moins récemment exécutées d'abord
+func (r *PostgresSavedSearchRepository) ListAll() ([]*SavedSearch, error) {
+	return r.list("SELECT " + savedSearchColumns + " FROM saved_searches ORDER BY last_run_at NULLS FIRST, id")
+}
+
+// RecordHits mémorise les profils remontés et retourne ceux qui ne l'étaient pas encore
+func (r *PostgresSavedSearchRepository) RecordHits(searchID int, userIDs []int) ([]int, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(userIDs))
+	for i, id := range userIDs {
+		ids[i] = int64(id)
+	}
+
+	query := `
+        INSERT INTO saved_search_hits (search_id, user_id)
+        SELECT $1, unnest($2::INTEGER[])
+        ON CONFLICT (search_id, user_id) DO NOTHING
+        RETURNING user_id
+    `
+
+	var inserted []int
+	err := database.WithTx(r.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query(query, searchID, pq.Array(ids))
+		if err != nil {
+			return fmt.Errorf("erreur lors de l'enregistrement des résultats: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var userID int
+			if err := rows.Scan(&userID); err != nil {
+				return fmt.Errorf("erreur lors de la lecture d'un résultat: %w", err)
+			}
+			inserted = append(inserted, userID)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
+
+// ResetHits oublie les profils remontés, après une modification des critères
+func (r *PostgresSavedSearchRepository) ResetHits(searchID int) error {
+	if _, err := r.db.Exec("DELETE FROM saved_search_hits WHERE search_id = $1", searchID); err != nil {
+		return fmt.Errorf("erreur lors de la réinitialisation des résultats: %w", err)
+	}
+	return nil
+}
+
+// MarkRun enregistre la date de dernière exécution
+func (r *PostgresSavedSearchRepository) MarkRun(searchID int) error {
+	if _, err := r.db.Exec("UPDATE saved_searches SET last_run_at = CURRENT_TIMESTAMP WHERE id = $1", searchID); err != nil {
+		return fmt.Errorf("erreur lors de la mise à jour de la recherche: %w", err)
+	}
+	return nil
+}
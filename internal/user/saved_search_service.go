@@ -0,0 +1,194 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cduffaut/matcha/internal/notifications"
+)
+
+const (
+	// MaxSavedSearches limite le nombre de recherches sauvegardées par utilisateur
+	MaxSavedSearches = 20
+	// savedSearchOverlap recouvre la durée d'une exécution : un profil modifié
+	// pendant la recherche précédente est réexaminé, les doublons étant écartés
+	// par saved_search_hits
+	savedSearchOverlap = 5 * time.Minute
+)
+
+// ErrTooManySavedSearches est retournée lorsque l'utilisateur a atteint MaxSavedSearches
+var ErrTooManySavedSearches = fmt.Errorf("vous ne pouvez pas sauvegarder plus de %d recherches", MaxSavedSearches)
+
+// SavedSearchService gère les recherches sauvegardées et leurs alertes
+type SavedSearchService struct {
+	repo          SavedSearchRepository
+	browsing      *BrowsingService
+	userRepo      Repository
+	notifications notifications.NotificationService
+	mailer        SearchAlertMailer
+	baseURL       string
+	every         time.Duration
+}
+
+// NewSavedSearchService crée un nouveau service de recherches sauvegardées
+func NewSavedSearchService(repo SavedSearchRepository, browsing *BrowsingService, userRepo Repository, notificationService notifications.NotificationService, mailer SearchAlertMailer, baseURL string, every time.Duration) *SavedSearchService {
+	return &SavedSearchService{
+		repo:          repo,
+		browsing:      browsing,
+		userRepo:      userRepo,
+		notifications: notificationService,
+		mailer:        mailer,
+		baseURL:       baseURL,
+		every:         every,
+	}
+}
+
+// List récupère les recherches sauvegardées d'un utilisateur
+func (s *SavedSearchService) List(userID int) ([]*SavedSearch, error) {
+	return s.repo.ListByUser(userID)
+}
+
+// Create enregistre une recherche ; les profils qui y correspondent déjà
+// servent de référence et ne déclencheront pas d'alerte. Sans cette référence,
+// la recherche est supprimée : sa première exécution alerterait sur tous les
+// profils existants
+func (s *SavedSearchService) Create(search *SavedSearch) error {
+	if err := s.repo.Create(search, MaxSavedSearches); err != nil {
+		return err
+	}
+
+	if err := s.baseline(search); err != nil {
+		if deleteErr := s.repo.Delete(search.UserID, search.ID); deleteErr != nil {
+			return fmt.Errorf("%w (suppression de la recherche incomplète impossible: %v)", err, deleteErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Update modifie une recherche ; la référence est recalculée avec les nouveaux critères
+func (s *SavedSearchService) Update(search *SavedSearch) error {
+	if err := s.repo.Update(search); err != nil {
+		return err
+	}
+
+	if err := s.repo.ResetHits(search.ID); err != nil {
+		return err
+	}
+
+	return s.baseline(search)
+}
+
+// Delete supprime une recherche
+func (s *SavedSearchService) Delete(userID, searchID int) error {
+	return s.repo.Delete(userID, searchID)
+}
+
+// baseline mémorise tous les profils correspondant actuellement à la recherche, sans alerter
+func (s *SavedSearchService) baseline(search *SavedSearch) error {
+	_, err := s.newMatches(search, nil)
+	if err != nil && !isProfileIncomplete(err) {
+		return err
+	}
+	return nil
+}
+
+// newMatches exécute la recherche, restreinte aux profils modifiés ou complétés
+// depuis since (nil : tous), et retourne ceux qu'elle n'avait encore jamais remontés
+func (s *SavedSearchService) newMatches(search *SavedSearch, since *time.Time) ([]int, error) {
+	options := search.Filters
+	options.SavedSearchID = search.ID
+	options.ChangedSince = since
+
+	results, err := s.browsing.rankSearch(search.UserID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int, 0, len(results))
+	for _, result := range results {
+		userIDs = append(userIDs, result.Profile.UserID)
+	}
+
+	return s.repo.RecordHits(search.ID, userIDs)
+}
+
+// Start lance l'exécution périodique des recherches sauvegardées
+func (s *SavedSearchService) Start() {
+	go s.run()
+}
+
+func (s *SavedSearchService) run() {
+	ticker := time.NewTicker(s.every)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.RunAlerts(); err != nil {
+			fmt.Printf("Erreur exécution des recherches sauvegardées: %v\n", err)
+		}
+	}
+}
+
+// RunAlerts exécute toutes les recherches sauvegardées et alerte leurs
+// propriétaires des profils apparus depuis la dernière exécution
+func (s *SavedSearchService) RunAlerts() error {
+	searches, err := s.repo.ListAll()
+	if err != nil {
+		return err
+	}
+
+	for _, search := range searches {
+		if err := s.runAlert(search); err != nil {
+			fmt.Printf("Erreur recherche sauvegardée %d: %v\n", search.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// runAlert n'examine que les profils modifiés ou complétés depuis la dernière
+// exécution de la recherche, ou depuis sa création
+func (s *SavedSearchService) runAlert(search *SavedSearch) error {
+	since := search.CreatedAt
+	if search.LastRunAt != nil {
+		since = *search.LastRunAt
+	}
+	since = since.Add(-savedSearchOverlap)
+
+	matches, err := s.newMatches(search, &since)
+	if err != nil && !isProfileIncomplete(err) {
+		return err
+	}
+
+	if len(matches) > 0 {
+		if err := s.notifications.NotifySearchMatch(search.UserID, matches[0], search.Name, len(matches)); err != nil {
+			fmt.Printf("Erreur notification recherche sauvegardée %d: %v\n", search.ID, err)
+		}
+
+		if search.EmailAlerts {
+			s.sendAlertEmail(search, len(matches))
+		}
+	}
+
+	return s.repo.MarkRun(search.ID)
+}
+
+func (s *SavedSearchService) sendAlertEmail(search *SavedSearch, count int) {
+	owner, err := s.userRepo.GetByID(search.UserID)
+	if err != nil {
+		fmt.Printf("Erreur récupération de l'utilisateur %d: %v\n", search.UserID, err)
+		return
+	}
+
+	if err := s.mailer.SendSavedSearchAlert(owner.Email, owner.Username, search.Name, count, s.baseURL+"/browse"); err != nil {
+		fmt.Printf("Erreur envoi de l'alerte email à l'utilisateur %d: %v\n", search.UserID, err)
+	}
+}
+
+// isProfileIncomplete reconnaît l'erreur de SearchProfiles pour un profil incomplet :
+// la recherche est simplement ignorée jusqu'à ce que le profil soit complété
+func isProfileIncomplete(err error) bool {
+	return strings.Contains(err.Error(), "votre profil doit être complété")
+}
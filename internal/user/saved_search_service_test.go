@@ -0,0 +1,51 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// memorySavedSearches conserve les recherches créées et supprimées
+type memorySavedSearches struct {
+	SavedSearchRepository
+	created []int
+	deleted []int
+}
+
+func (r *memorySavedSearches) Create(search *SavedSearch, limit int) error {
+	if len(r.created)-len(r.deleted) >= limit {
+		return ErrTooManySavedSearches
+	}
+	search.ID = len(r.created) + 1
+	r.created = append(r.created, search.ID)
+	return nil
+}
+
+func (r *memorySavedSearches) Delete(userID, searchID int) error {
+	r.deleted = append(r.deleted, searchID)
+	return nil
+}
+
+// unavailableProfiles simule une base des profils indisponible
+type unavailableProfiles struct {
+	ProfileRepository
+}
+
+func (r *unavailableProfiles) GetByUserID(userID int) (*Profile, error) {
+	return nil, errors.New("base indisponible")
+}
+
+func TestCreateSavedSearchDeletesItWithoutBaseline(t *testing.T) {
+	searches := &memorySavedSearches{}
+	browsing := NewBrowsingService(nil, &unavailableProfiles{}, nil, nil, nil, nil, 0, nil)
+	service := NewSavedSearchService(searches, browsing, nil, nil, nil, "", time.Hour)
+
+	if err := service.Create(&SavedSearch{UserID: 1, Name: "Paris"}); err == nil {
+		t.Fatal("Create devrait échouer sans référence")
+	}
+	if len(searches.created) != 1 || len(searches.deleted) != 1 || searches.deleted[0] != searches.created[0] {
+		t.Errorf("recherches créées %v, supprimées %v : la recherche sans référence doit être supprimée",
+			searches.created, searches.deleted)
+	}
+}
@@ -0,0 +1,76 @@
+package user
+
+import (
+	"math"
+
+	"github.com/cduffaut/matcha/internal/config"
+)
+
+// ScoreInput regroupe les éléments disponibles pour noter un candidat
+type ScoreInput struct {
+	Current    *Profile
+	Candidate  *Profile
+	Distance   float64 // km
+	SharedTags []Tag
+	Affinity   float64 // Proximité (0 à 1) avec les profils likés par l'utilisateur
+	HasLikes   bool    // L'utilisateur a un historique de likes exploitable
+}
+
+// ScoreBreakdown détaille le score de compatibilité : chaque composante est
+// normalisée entre 0 et 1, Total est la moyenne pondérée utilisée pour le classement
+type ScoreBreakdown struct {
+	Distance float64
+	Tags     float64
+	Fame     float64
+	Behavior float64
+	Total    float64
+}
+
+// Scorer calcule le score de compatibilité d'un candidat
+type Scorer interface {
+	Score(input ScoreInput) ScoreBreakdown
+}
+
+// WeightedScorer est le classement par défaut : moyenne pondérée de la proximité,
+// des intérêts communs, du fame rating et de l'affinité issue des likes. Sans
+// historique de likes, l'affinité est écartée et les autres poids renormalisés :
+// les valeurs par défaut redonnent alors 0,5 distance, 0,3 tags et 0,2 fame.
+type WeightedScorer struct {
+	cfg  config.ScoringConfig
+	tags *TagIndex // Poids IDF des tags ; nil pour compter les tags communs
+}
+
+// NewWeightedScorer crée un scorer pondéré à partir de la configuration
+func NewWeightedScorer(cfg config.ScoringConfig, tags *TagIndex) *WeightedScorer {
+	return &WeightedScorer{cfg: cfg, tags: tags}
+}
+
+// Score implémente Scorer
+func (w *WeightedScorer) Score(input ScoreInput) ScoreBreakdown {
+	scores := ScoreBreakdown{
+		Distance: math.Max(0, 1.0-input.Distance/w.cfg.MaxDistanceKm),
+		Tags:     math.Min(1, float64(len(input.SharedTags))/float64(w.cfg.MaxCommonTags)),
+		Fame:     math.Min(1, float64(input.Candidate.FameRating)/float64(w.cfg.MaxFameRating)),
+		Behavior: math.Max(0, math.Min(1, input.Affinity)),
+	}
+	if w.tags != nil {
+		// Similarité pondérée par l'IDF : un tag rare en commun pèse plus qu'un tag répandu
+		scores.Tags = w.tags.Similarity(input.Current.Tags, input.Candidate.Tags)
+	}
+
+	behaviorWeight := w.cfg.BehaviorWeight
+	if !input.HasLikes {
+		behaviorWeight = 0
+	}
+	totalWeight := w.cfg.DistanceWeight + w.cfg.TagsWeight + w.cfg.FameWeight + behaviorWeight
+	if totalWeight == 0 {
+		return scores
+	}
+
+	scores.Total = (scores.Distance*w.cfg.DistanceWeight +
+		scores.Tags*w.cfg.TagsWeight +
+		scores.Fame*w.cfg.FameWeight +
+		scores.Behavior*behaviorWeight) / totalWeight
+
+	return scores
+}
@@ -0,0 +1,73 @@
+package user
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cduffaut/matcha/internal/config"
+)
+
+// defaultScoring reprend les valeurs par défaut de la configuration
+var defaultScoring = config.ScoringConfig{
+	DistanceWeight: 0.45,
+	TagsWeight:     0.27,
+	FameWeight:     0.18,
+	BehaviorWeight: 0.1,
+	MaxDistanceKm:  100,
+	MaxCommonTags:  5,
+	MaxFameRating:  100,
+}
+
+func TestWeightedScorer(t *testing.T) {
+	tags := func(n int) []Tag { return make([]Tag, n) }
+
+	tests := []struct {
+		name     string
+		distance float64
+		shared   int
+		fame     int
+		affinity float64
+		hasLikes bool
+		want     float64
+	}{
+		// Sans historique de likes : formule historique 0,5 / 0,3 / 0,2
+		{"sans likes, scores maximaux", 0, 5, 100, 0, false, 1},
+		{"sans likes, distance seule", 50, 0, 0, 0, false, 0.5 * 0.5},
+		{"sans likes, tags plafonnés", 100, 8, 0, 0, false, 0.3},
+		{"sans likes, fame seul", 150, 0, 40, 0, false, 0.2 * 0.4},
+		{"sans likes, mélange", 20, 2, 50, 0, false, 0.5*0.8 + 0.3*0.4 + 0.2*0.5},
+		{"affinité ignorée sans likes", 20, 2, 50, 1, false, 0.5*0.8 + 0.3*0.4 + 0.2*0.5},
+
+		// Avec historique : l'affinité compte pour 10 %
+		{"avec likes, scores maximaux", 0, 5, 100, 1, true, 1},
+		{"avec likes, affinité seule", 100, 0, 0, 1, true, 0.1},
+		{"avec likes, sans affinité", 0, 5, 100, 0, true, 0.9},
+		{"affinité bornée à 1", 100, 0, 0, 3, true, 0.1},
+	}
+
+	scorer := NewWeightedScorer(defaultScoring, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scores := scorer.Score(ScoreInput{
+				Current:    &Profile{},
+				Candidate:  &Profile{FameRating: tt.fame},
+				Distance:   tt.distance,
+				SharedTags: tags(tt.shared),
+				Affinity:   tt.affinity,
+				HasLikes:   tt.hasLikes,
+			})
+			if math.Abs(scores.Total-tt.want) > 1e-9 {
+				t.Errorf("Total = %v, attendu %v (%+v)", scores.Total, tt.want, scores)
+			}
+		})
+	}
+}
+
+func TestWeightedScorerWithoutWeights(t *testing.T) {
+	scorer := NewWeightedScorer(config.ScoringConfig{MaxDistanceKm: 100, MaxCommonTags: 5, MaxFameRating: 100}, nil)
+
+	scores := scorer.Score(ScoreInput{Current: &Profile{}, Candidate: &Profile{FameRating: 100}, HasLikes: true})
+	if scores.Total != 0 {
+		t.Errorf("Total = %v, attendu 0 sans poids", scores.Total)
+	}
+}
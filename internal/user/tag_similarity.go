@@ -0,0 +1,164 @@
+package user
+
+import (
+	"math"
+	"strings"
+	"sync"
+)
+
+// maxAliasDepth borne la résolution des alias chaînés (et protège des cycles)
+const maxAliasDepth = 4
+
+// accentFolder retire les accents courants du français
+var accentFolder = strings.NewReplacer(
+	"à", "a", "â", "a", "ä", "a", "á", "a", "ã", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"î", "i", "ï", "i", "í", "i",
+	"ô", "o", "ö", "o", "ó", "o", "õ", "o",
+	"ù", "u", "û", "u", "ü", "u", "ú", "u",
+	"ç", "c", "ñ", "n", "ÿ", "y",
+	"œ", "oe", "æ", "ae",
+)
+
+// NormalizeTag ramène un tag à sa forme de comparaison : sans # initial,
+// en minuscules et sans accents ("#Cinéma" → "cinema")
+func NormalizeTag(name string) string {
+	name = strings.TrimLeft(strings.TrimSpace(name), "#")
+	return accentFolder.Replace(strings.ToLower(name))
+}
+
+// TagStat représente la fréquence d'un tag canonique parmi les utilisateurs
+type TagStat struct {
+	Canonical string
+	UserCount int
+	IDF       float64
+}
+
+// TagIndex résout les synonymes de tags et fournit leur poids IDF : partager
+// un tag rare compte davantage que partager un tag répandu. Il est alimenté
+// par TagStatsJob et lu concurremment par le classement des suggestions.
+type TagIndex struct {
+	mu         sync.RWMutex
+	aliases    map[string]string  // alias normalisé → tag canonique
+	idf        map[string]float64 // tag canonique → IDF
+	defaultIDF float64            // IDF d'un tag absent des statistiques
+}
+
+// NewTagIndex crée un index vide : tous les tags ont le même poids
+func NewTagIndex() *TagIndex {
+	return &TagIndex{
+		aliases:    make(map[string]string),
+		idf:        make(map[string]float64),
+		defaultIDF: 1,
+	}
+}
+
+// SetAliases remplace la table des synonymes
+func (i *TagIndex) SetAliases(aliases map[string]string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.aliases = aliases
+}
+
+// SetStats remplace les poids IDF ; defaultIDF s'applique aux tags inconnus
+func (i *TagIndex) SetStats(stats []TagStat, defaultIDF float64) {
+	idf := make(map[string]float64, len(stats))
+	for _, stat := range stats {
+		idf[stat.Canonical] = stat.IDF
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.idf = idf
+	i.defaultIDF = defaultIDF
+}
+
+// Canonical retourne la forme canonique d'un tag, synonymes résolus
+func (i *TagIndex) Canonical(name string) string {
+	key := NormalizeTag(name)
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	for depth := 0; depth < maxAliasDepth; depth++ {
+		next, ok := i.aliases[key]
+		if !ok {
+			break
+		}
+		key = next
+	}
+	return key
+}
+
+// IDF retourne le poids d'un tag canonique
+func (i *TagIndex) IDF(canonical string) float64 {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if idf, ok := i.idf[canonical]; ok {
+		return idf
+	}
+	return i.defaultIDF
+}
+
+// canonicalSet retourne les formes canoniques distinctes d'une liste de tags
+func (i *TagIndex) canonicalSet(tags []Tag) map[string]bool {
+	set := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		set[i.Canonical(tag.Name)] = true
+	}
+	return set
+}
+
+// Shared retourne les tags de tags2 équivalents (synonymes compris) à un tag de tags1
+func (i *TagIndex) Shared(tags1, tags2 []Tag) []Tag {
+	wanted := i.canonicalSet(tags1)
+
+	var shared []Tag
+	for _, tag := range tags2 {
+		canonical := i.Canonical(tag.Name)
+		if wanted[canonical] {
+			shared = append(shared, tag)
+			delete(wanted, canonical) // un synonyme ne compte qu'une fois
+		}
+	}
+	return shared
+}
+
+// HasAll indique si les tags couvrent tous les tags recherchés, synonymes compris
+func (i *TagIndex) HasAll(tags []Tag, wanted []string) bool {
+	have := i.canonicalSet(tags)
+	for _, name := range wanted {
+		if !have[i.Canonical(name)] {
+			return false
+		}
+	}
+	return true
+}
+
+// Similarity calcule la similarité cosinus entre deux ensembles de tags
+// pondérés par leur IDF (entre 0 et 1)
+func (i *TagIndex) Similarity(tags1, tags2 []Tag) float64 {
+	set1, set2 := i.canonicalSet(tags1), i.canonicalSet(tags2)
+
+	var dot, norm1, norm2 float64
+	for tag := range set1 {
+		weight := i.IDF(tag)
+		norm1 += weight * weight
+		if set2[tag] {
+			dot += weight * weight
+		}
+	}
+	for tag := range set2 {
+		weight := i.IDF(tag)
+		norm2 += weight * weight
+	}
+
+	if norm1 == 0 || norm2 == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(norm1*norm2)
+}
+
+// computeIDF calcule l'IDF lissé d'un tag porté par userCount des totalUsers utilisateurs
+func computeIDF(userCount, totalUsers int) float64 {
+	return math.Log(float64(totalUsers+1)/float64(userCount+1)) + 1
+}
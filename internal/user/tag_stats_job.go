@@ -0,0 +1,107 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// TagStatsJob recalcule périodiquement la fréquence des tags canoniques
+// (synonymes regroupés) et met à jour les poids IDF de l'index
+type TagStatsJob struct {
+	repo     TagStatsRepository
+	index    *TagIndex
+	interval time.Duration
+}
+
+// NewTagStatsJob crée la tâche de recalcul des statistiques de tags
+func NewTagStatsJob(repo TagStatsRepository, index *TagIndex, interval time.Duration) *TagStatsJob {
+	return &TagStatsJob{repo: repo, index: index, interval: interval}
+}
+
+// Start charge les dernières statistiques enregistrées puis lance le recalcul périodique
+func (j *TagStatsJob) Start() {
+	if aliases, err := j.repo.GetAliases(); err != nil {
+		fmt.Printf("Erreur chargement synonymes de tags: %v\n", err)
+	} else {
+		j.index.SetAliases(aliases)
+	}
+
+	if stats, err := j.repo.GetTagStats(); err != nil {
+		fmt.Printf("Erreur chargement statistiques de tags: %v\n", err)
+	} else if len(stats) > 0 {
+		j.index.SetStats(stats, maxIDF(stats))
+	}
+
+	go j.run()
+}
+
+func (j *TagStatsJob) run() {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := j.Refresh(); err != nil {
+			fmt.Printf("Erreur recalcul des statistiques de tags: %v\n", err)
+		}
+		<-ticker.C
+	}
+}
+
+// Refresh recharge les synonymes, recompte les tags et enregistre les nouveaux IDF
+func (j *TagStatsJob) Refresh() error {
+	aliases, err := j.repo.GetAliases()
+	if err != nil {
+		return err
+	}
+	j.index.SetAliases(aliases)
+
+	userTags, err := j.repo.GetUserTagNames()
+	if err != nil {
+		return err
+	}
+
+	// Chaque utilisateur compte une fois par tag canonique
+	counts := make(map[string]int)
+	for _, names := range userTags {
+		seen := make(map[string]bool, len(names))
+		for _, name := range names {
+			canonical := j.index.Canonical(name)
+			if canonical == "" || seen[canonical] {
+				continue
+			}
+			seen[canonical] = true
+			counts[canonical]++
+		}
+	}
+
+	totalUsers := len(userTags)
+	stats := make([]TagStat, 0, len(counts))
+	for canonical, count := range counts {
+		stats = append(stats, TagStat{
+			Canonical: canonical,
+			UserCount: count,
+			IDF:       computeIDF(count, totalUsers),
+		})
+	}
+	sort.Slice(stats, func(a, b int) bool { return stats[a].Canonical < stats[b].Canonical })
+
+	if err := j.repo.ReplaceTagStats(stats); err != nil {
+		return err
+	}
+
+	// Un tag encore inconnu est le plus rare qui soit
+	j.index.SetStats(stats, computeIDF(0, totalUsers))
+	return nil
+}
+
+// maxIDF retourne le plus grand IDF des statistiques, utilisé pour les tags inconnus
+func maxIDF(stats []TagStat) float64 {
+	max := 1.0
+	for _, stat := range stats {
+		if stat.IDF > max {
+			max = stat.IDF
+		}
+	}
+	return max
+}
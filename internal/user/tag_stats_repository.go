@@ -0,0 +1,107 @@
+package user
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/cduffaut/matcha/internal/database"
+)
+
+// PostgresTagStatsRepository est l'implémentation PostgreSQL du TagStatsRepository
+type PostgresTagStatsRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTagStatsRepository crée un nouveau repository pour les statistiques de tags
+func NewPostgresTagStatsRepository(db *sql.DB) TagStatsRepository {
+	return &PostgresTagStatsRepository{db: db}
+}
+
+// GetAliases récupère la table des synonymes (alias normalisé → tag canonique)
+func (r *PostgresTagStatsRepository) GetAliases() (map[string]string, error) {
+	rows, err := r.db.Query("SELECT alias, canonical FROM tag_aliases")
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des synonymes de tags: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := make(map[string]string)
+	for rows.Next() {
+		var alias, canonical string
+		if err := rows.Scan(&alias, &canonical); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un synonyme: %w", err)
+		}
+		aliases[NormalizeTag(alias)] = NormalizeTag(canonical)
+	}
+
+	return aliases, rows.Err()
+}
+
+// GetUserTagNames récupère les noms de tags de chaque utilisateur
+func (r *PostgresTagStatsRepository) GetUserTagNames() (map[int][]string, error) {
+	query := `
+        SELECT ut.user_id, t.name
+        FROM user_tags ut
+        JOIN tags t ON t.id = ut.tag_id
+    `
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des tags utilisateurs: %w", err)
+	}
+	defer rows.Close()
+
+	userTags := make(map[int][]string)
+	for rows.Next() {
+		var userID int
+		var name string
+		if err := rows.Scan(&userID, &name); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'un tag utilisateur: %w", err)
+		}
+		userTags[userID] = append(userTags[userID], name)
+	}
+
+	return userTags, rows.Err()
+}
+
+// GetTagStats récupère les dernières fréquences calculées
+func (r *PostgresTagStatsRepository) GetTagStats() ([]TagStat, error) {
+	rows, err := r.db.Query("SELECT canonical, user_count, idf FROM tag_stats")
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de la récupération des statistiques de tags: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []TagStat
+	for rows.Next() {
+		var stat TagStat
+		if err := rows.Scan(&stat.Canonical, &stat.UserCount, &stat.IDF); err != nil {
+			return nil, fmt.Errorf("erreur lors de la lecture d'une statistique de tag: %w", err)
+		}
+		stats = append(stats, stat)
+	}
+
+	return stats, rows.Err()
+}
+
+// ReplaceTagStats remplace l'ensemble des statistiques en une transaction
+func (r *PostgresTagStatsRepository) ReplaceTagStats(stats []TagStat) error {
+	return database.WithTx(r.db, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM tag_stats"); err != nil {
+			return fmt.Errorf("erreur lors de la purge des statistiques de tags: %w", err)
+		}
+
+		stmt, err := tx.Prepare("INSERT INTO tag_stats (canonical, user_count, idf) VALUES ($1, $2, $3)")
+		if err != nil {
+			return fmt.Errorf("erreur lors de la préparation des statistiques de tags: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, stat := range stats {
+			if _, err := stmt.Exec(stat.Canonical, stat.UserCount, stat.IDF); err != nil {
+				return fmt.Errorf("erreur lors de l'enregistrement des statistiques du tag %s: %w", stat.Canonical, err)
+			}
+		}
+		return nil
+	})
+}
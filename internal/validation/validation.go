@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Règles de validation
@@ -20,8 +21,18 @@ const (
 	MaxLocationLength  = 100
 	MinNameLength      = 2
 	MaxNameLength      = 50
+
+	MinDiscoveryAge        = 18
+	MaxDiscoveryAge        = 120
+	MaxDiscoveryDistanceKm = 20000
+
+	MaxSavedSearchNameLength = 50
+	MaxSearchTags            = 10
 )
 
+// Genders liste les genres acceptés pour un profil et pour ses centres d'intérêt
+var Genders = []string{"male", "female", "non_binary", "other"}
+
 // ValidationError représente une erreur de validation
 type ValidationError struct {
 	Field   string `json:"field"`
@@ -270,9 +281,11 @@ func ValidateTag(tag string) error {
 
 // ValidateGender valide un genre
 func ValidateGender(gender string) error {
-	validGenders := []string{"", "male", "female"}
+	if gender == "" {
+		return nil
+	}
 
-	for _, valid := range validGenders {
+	for _, valid := range Genders {
 		if gender == valid {
 			return nil
 		}
@@ -281,6 +294,24 @@ func ValidateGender(gender string) error {
 	return ValidationError{Field: "gender", Message: "genre invalide"}
 }
 
+// ValidateInterestedIn valide l'ensemble des genres qui intéressent un utilisateur
+func ValidateInterestedIn(genders []string) error {
+	if len(genders) == 0 {
+		return ValidationError{Field: "interested_in", Message: "sélectionnez au moins un genre"}
+	}
+	if len(genders) > len(Genders) {
+		return ValidationError{Field: "interested_in", Message: "trop de genres sélectionnés"}
+	}
+
+	for _, gender := range genders {
+		if gender == "" || ValidateGender(gender) != nil {
+			return ValidationError{Field: "interested_in", Message: "genre recherché invalide"}
+		}
+	}
+
+	return nil
+}
+
 // ValidateSexualPreference valide une préférence sexuelle
 func ValidateSexualPreference(pref string) error {
 	validPrefs := []string{"", "heterosexual", "homosexual", "bisexual"}
@@ -294,6 +325,69 @@ func ValidateSexualPreference(pref string) error {
 	return ValidationError{Field: "sexual_preference", Message: "préférence sexuelle invalide"}
 }
 
+// ValidateDiscoveryPreferences valide les préférences de découverte (0 = sans limite)
+func ValidateDiscoveryPreferences(minAge, maxAge int, maxDistance float64, genders []string) ValidationErrors {
+	var errors ValidationErrors
+
+	if minAge != 0 && (minAge < MinDiscoveryAge || minAge > MaxDiscoveryAge) {
+		errors = append(errors, ValidationError{Field: "min_age", Message: fmt.Sprintf("l'âge minimum doit être entre %d et %d ans", MinDiscoveryAge, MaxDiscoveryAge)})
+	}
+
+	if maxAge != 0 && (maxAge < MinDiscoveryAge || maxAge > MaxDiscoveryAge) {
+		errors = append(errors, ValidationError{Field: "max_age", Message: fmt.Sprintf("l'âge maximum doit être entre %d et %d ans", MinDiscoveryAge, MaxDiscoveryAge)})
+	}
+
+	if minAge != 0 && maxAge != 0 && minAge > maxAge {
+		errors = append(errors, ValidationError{Field: "max_age", Message: "l'âge maximum doit être supérieur à l'âge minimum"})
+	}
+
+	if maxDistance < 0 || maxDistance > MaxDiscoveryDistanceKm {
+		errors = append(errors, ValidationError{Field: "max_distance", Message: fmt.Sprintf("la distance maximale doit être entre 0 et %d km", MaxDiscoveryDistanceKm)})
+	}
+
+	for _, gender := range genders {
+		if gender == "" || ValidateGender(gender) != nil {
+			errors = append(errors, ValidationError{Field: "genders", Message: "genre recherché invalide"})
+			break
+		}
+	}
+
+	return errors
+}
+
+// ValidateSavedSearchName valide le nom d'une recherche sauvegardée
+func ValidateSavedSearchName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ValidationError{Field: "name", Message: "le nom de la recherche est requis"}
+	}
+	if utf8.RuneCountInString(name) > MaxSavedSearchNameLength {
+		return ValidationError{Field: "name", Message: fmt.Sprintf("le nom doit contenir au maximum %d caractères", MaxSavedSearchNameLength)}
+	}
+	return nil
+}
+
+// ValidateSearchFilters valide les critères d'une recherche (0 = sans limite)
+func ValidateSearchFilters(minAge, maxAge, minFame, maxFame int, maxDistance float64, tags []string) ValidationErrors {
+	errors := ValidateDiscoveryPreferences(minAge, maxAge, maxDistance, nil)
+
+	if minFame < 0 || maxFame < 0 || (maxFame > 0 && minFame > maxFame) {
+		errors = append(errors, ValidationError{Field: "fame", Message: "plage de fame rating invalide"})
+	}
+
+	if len(tags) > MaxSearchTags {
+		errors = append(errors, ValidationError{Field: "tags", Message: fmt.Sprintf("%d tags au maximum", MaxSearchTags)})
+	}
+	for _, tag := range tags {
+		if strings.TrimSpace(tag) == "" || len(tag) > MaxTagLength {
+			errors = append(errors, ValidationError{Field: "tags", Message: "tag recherché invalide"})
+			break
+		}
+	}
+
+	return errors
+}
+
 // ValidateCoordinates valide des coordonnées GPS
 func ValidateCoordinates(lat, lon float64) error {
 	if lat < -90 || lat > 90 {
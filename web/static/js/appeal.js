@@ -0,0 +1,117 @@
+// Page de contestation d'une suspension ou d'un bannissement
+
+const APPEAL_STATUS_LABELS = {
+    open: 'Reçue',
+    under_review: 'En cours d\'examen',
+    accepted: 'Acceptée',
+    rejected: 'Rejetée'
+};
+
+const ACCOUNT_STATUS_LABELS = {
+    suspended: 'Votre compte est suspendu',
+    banned: 'Votre compte a été banni'
+};
+
+document.addEventListener('DOMContentLoaded', () => {
+    const form = document.getElementById('appeal-form');
+    if (form) {
+        form.addEventListener('submit', submitAppeal);
+    }
+    loadAppeals();
+});
+
+function escapeText(value) {
+    const div = document.createElement('div');
+    div.textContent = value || '';
+    return div.innerHTML;
+}
+
+async function loadAppeals() {
+    try {
+        const response = await fetch('/api/appeals', {
+            headers: { 'Accept': 'application/json' }
+        });
+        if (!response.ok) {
+            showAppealFeedback('Impossible de charger vos contestations', true);
+            return;
+        }
+
+        const data = await response.json();
+        renderAccount(data);
+        renderAppeals(data.appeals || []);
+
+        // Une seule contestation en cours à la fois
+        const pending = (data.appeals || []).some(a => a.status === 'open' || a.status === 'under_review');
+        document.getElementById('appeal-form').style.display = data.can_appeal && !pending ? '' : 'none';
+    } catch (error) {
+        showAppealFeedback('Erreur de connexion', true);
+    }
+}
+
+function renderAccount(data) {
+    const container = document.getElementById('account-status');
+    if (!data.can_appeal) {
+        container.innerHTML = '<p>Votre compte ne fait l\'objet d\'aucune restriction. <a href="/login">Se connecter</a></p>';
+        return;
+    }
+
+    let text = ACCOUNT_STATUS_LABELS[data.account_status] || 'Votre compte fait l\'objet d\'une restriction';
+    if (data.suspended_until) {
+        text += ` jusqu'au ${new Date(data.suspended_until).toLocaleString('fr-FR')}`;
+    }
+    container.innerHTML = `<p><strong>${escapeText(text)}.</strong></p>` +
+        (data.reason ? `<p>Motif : ${escapeText(data.reason)}</p>` : '');
+}
+
+function renderAppeals(appeals) {
+    const container = document.getElementById('appeals');
+    if (appeals.length === 0) {
+        container.innerHTML = '';
+        return;
+    }
+
+    container.innerHTML = '<h2>Mes contestations</h2>' + appeals.map(appeal => `
+        <div class="appeal">
+            <p><strong>${escapeText(APPEAL_STATUS_LABELS[appeal.status] || appeal.status)}</strong>
+               — ${new Date(appeal.created_at).toLocaleDateString('fr-FR')}</p>
+            <p>${escapeText(appeal.message)}</p>
+            ${appeal.comment ? `<p>Réponse : ${escapeText(appeal.comment)}</p>` : ''}
+        </div>
+    `).join('');
+}
+
+async function submitAppeal(e) {
+    e.preventDefault();
+
+    const message = document.getElementById('appeal-message').value.trim();
+    if (!message) {
+        showAppealFeedback('Votre message est vide', true);
+        return;
+    }
+
+    try {
+        const response = await fetch('/api/appeals', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ message })
+        });
+        const data = await response.json();
+
+        if (!response.ok) {
+            showAppealFeedback(data.error || 'Erreur lors de l\'envoi', true);
+            return;
+        }
+
+        document.getElementById('appeal-message').value = '';
+        showAppealFeedback('Votre contestation a été envoyée. Vous serez prévenu par email.', false);
+        loadAppeals();
+    } catch (error) {
+        showAppealFeedback('Erreur de connexion', true);
+    }
+}
+
+function showAppealFeedback(message, isError) {
+    const feedback = document.getElementById('appeal-feedback');
+    feedback.className = isError ? 'error' : 'success';
+    feedback.textContent = message;
+}
@@ -61,9 +61,15 @@ async function handleLogin(e) {
             onSuccess: (data) => {
                 showSuccess('Connexion réussie !');
             },
-            onError: (error) => {
+            onError: (error, data) => {
                 // L'erreur est déjà affichée par handleFormSubmission
                 console.log('Erreur de connexion gérée:', error);
+                // Compte suspendu ou banni : proposer la contestation
+                if (data && data.appeal_url) {
+                    setTimeout(() => {
+                        window.location.href = data.appeal_url;
+                    }, 2000);
+                }
             }
         });
         
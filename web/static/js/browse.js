@@ -5,6 +5,9 @@ document.addEventListener('DOMContentLoaded', function() {
     const limit = 20;
     let isLoading = false;
     let hasMoreResults = true;
+    // Curseur de la page suivante et critères de la recherche en cours (null = suggestions)
+    let nextCursor = null;
+    let currentSearchParams = null;
     let selectedTags = [];
     let availableTags = [];
 
@@ -34,6 +37,35 @@ document.addEventListener('DOMContentLoaded', function() {
         if (loadMoreBtn) {
             loadMoreBtn.addEventListener('click', loadMoreProfiles);
         }
+
+        // Boutons des cartes de profil (pas de gestionnaire inline : CSP)
+        if (profilesContainer) {
+            profilesContainer.addEventListener('click', handleProfileAction);
+        }
+    }
+
+    // Délègue les clics sur les boutons des cartes et des messages
+    function handleProfileAction(e) {
+        const button = e.target.closest('button');
+        if (!button) return;
+
+        if (button.classList.contains('retry-btn')) {
+            location.reload();
+            return;
+        }
+
+        const userId = parseInt(button.dataset.userId, 10);
+        switch (button.dataset.action) {
+            case 'view':
+                window.viewProfile(userId);
+                break;
+            case 'pass':
+                window.passProfile(userId);
+                break;
+            case 'like':
+                window.likeProfile(userId);
+                break;
+        }
     }
 
     // Gestion de la soumission du formulaire de recherche
@@ -58,9 +90,9 @@ document.addEventListener('DOMContentLoaded', function() {
             searchParams['tags'] = cleanTags.join(',');
         }
         
-        // Réinitialiser l'offset pour une nouvelle recherche
-        currentOffset = 0;
-        hasMoreResults = true;
+        // Réinitialiser la pagination pour une nouvelle recherche
+        resetPagination();
+        currentSearchParams = searchParams;
         
         await handleSearch(searchParams);
     }
@@ -73,8 +105,19 @@ document.addEventListener('DOMContentLoaded', function() {
         showLoading();
 
         try {
-            const response = await fetch(`/api/suggestions?limit=${limit}&offset=${currentOffset}`);
+            const params = new URLSearchParams({ limit: limit });
+            if (nextCursor) {
+                params.set('cursor', nextCursor);
+            }
+            const response = await fetch(`/api/suggestions?${params.toString()}`);
             
+            if (response.status === 410) {
+                // Classement expiré : repartir de la première page
+                resetPagination();
+                isLoading = false;
+                return await loadSuggestions();
+            }
+
             if (!response.ok) {
                 throw new Error(`HTTP error! status: ${response.status}`);
             }
@@ -99,7 +142,8 @@ document.addEventListener('DOMContentLoaded', function() {
             }
             
             // Gérer la pagination
-            if (suggestions.length < limit) {
+            nextCursor = data.next_cursor || null;
+            if (!nextCursor) {
                 hasMoreResults = false;
                 hideLoadMoreButton();
             } else {
@@ -124,12 +168,20 @@ document.addEventListener('DOMContentLoaded', function() {
         try {
             const params = new URLSearchParams({
                 ...searchParams,
-                limit: limit,
-                offset: currentOffset
+                limit: limit
             });
+            if (nextCursor) {
+                params.set('cursor', nextCursor);
+            }
             
             const response = await fetch(`/api/search?${params.toString()}`);
             
+            if (response.status === 410) {
+                resetPagination();
+                isLoading = false;
+                return await handleSearch(searchParams);
+            }
+
             if (!response.ok) {
                 throw new Error(`HTTP error! status: ${response.status}`);
             }
@@ -152,7 +204,8 @@ document.addEventListener('DOMContentLoaded', function() {
             }
             
             // Gérer la pagination
-            if (results.length < limit) {
+            nextCursor = data.next_cursor || null;
+            if (!nextCursor) {
                 hasMoreResults = false;
                 hideLoadMoreButton();
             } else {
@@ -171,8 +224,18 @@ document.addEventListener('DOMContentLoaded', function() {
     async function loadMoreProfiles() {
         if (!hasMoreResults || isLoading) return;
         
-        currentOffset += limit;
-        await loadSuggestions();
+        if (currentSearchParams) {
+            await handleSearch(currentSearchParams);
+        } else {
+            await loadSuggestions();
+        }
+    }
+
+    // Repartir de la première page (nouveau classement côté serveur)
+    function resetPagination() {
+        currentOffset = 0;
+        nextCursor = null;
+        hasMoreResults = true;
     }
 
     // Afficher les profils (remplace le contenu)
@@ -184,7 +247,7 @@ document.addEventListener('DOMContentLoaded', function() {
                 <div class="no-profiles">
                     <h3>Aucun profil trouvé</h3>
                     <p>Aucun profil ne correspond à vos critères de recherche.</p>
-                    <button onclick="location.reload()" class="retry-btn">Voir toutes les suggestions</button>
+                    <button type="button" class="retry-btn">Voir toutes les suggestions</button>
                 </div>
             `;
             hideLoadMoreButton();
@@ -239,6 +302,13 @@ document.addEventListener('DOMContentLoaded', function() {
             ? tags.map(tag => `<span class="tag">${tag.Name || tag.name}</span>`).join('')
             : '<span class="no-tags">Aucun intérêt</span>';
         
+        // Raisons de la suggestion
+        const reasons = profile.Reasons || [];
+        const reasonsHTML = reasons.length > 0
+            ? `<ul class="match-reasons">${reasons.map(reason =>
+                `<li data-code="${escapeHtml(reason.code)}">${escapeHtml(reason.message)}</li>`).join('')}</ul>`
+            : '';
+        
         // Distance
         const distanceText = profile.Distance 
             ? `${Math.round(profile.Distance)} km` 
@@ -275,9 +345,11 @@ document.addEventListener('DOMContentLoaded', function() {
                     <div class="profile-tags">
                         ${tagsHTML}
                     </div>
+                    ${reasonsHTML}
                     <div class="profile-actions">
-                        <button onclick="viewProfile(${userId})" class="view-btn">Voir le profil</button>
-                        <button onclick="likeProfile(${userId})" class="like-btn">👍 Liker</button>
+                        <button type="button" data-action="view" data-user-id="${userId}" class="view-btn">Voir le profil</button>
+                        <button type="button" data-action="pass" data-user-id="${userId}" class="pass-btn">✖ Passer</button>
+                        <button type="button" data-action="like" data-user-id="${userId}" class="like-btn">👍 Liker</button>
                     </div>
                 </div>
             </div>
@@ -316,7 +388,7 @@ document.addEventListener('DOMContentLoaded', function() {
                 <div class="message-icon">❌</div>
                 <h3>Erreur</h3>
                 <p>${message}</p>
-                <button onclick="location.reload()" class="retry-btn">Réessayer</button>
+                <button type="button" class="retry-btn">Réessayer</button>
             </div>
         `;
     }
@@ -707,6 +779,7 @@ document.addEventListener('DOMContentLoaded', function() {
         `;
 
         const styleSheet = document.createElement("style");
+        styleSheet.nonce = window.cspNonce || '';
         styleSheet.textContent = additionalCSS;
         document.head.appendChild(styleSheet);
     }
@@ -734,7 +807,8 @@ document.addEventListener('DOMContentLoaded', function() {
                     alert('👍 Like envoyé !');
                 }
                 // Recharger les suggestions pour mettre à jour l'affichage
-                currentOffset = 0;
+                resetPagination();
+                currentSearchParams = null;
                 loadSuggestions();
             } else {
                 alert(data.error || 'Erreur lors du like');
@@ -744,6 +818,68 @@ document.addEventListener('DOMContentLoaded', function() {
         }
     };
 
+    window.passProfile = async function(userId) {
+        try {
+            const response = await fetch(`/api/profile/${userId}/pass`, {
+                method: 'POST',
+                headers: {
+                    'Content-Type': 'application/json',
+                }
+            });
+
+            if (!response.ok) {
+                const data = await response.json();
+                alert(data.error || 'Erreur lors du pass');
+                return;
+            }
+
+            const card = document.querySelector(`.profile-card[data-user-id="${userId}"]`);
+            if (card) {
+                card.remove();
+            }
+            showUndoPass();
+        } catch (error) {
+            alert('Erreur lors du pass');
+        }
+    };
+
+    // Proposer d'annuler le dernier pass pendant quelques secondes
+    function showUndoPass() {
+        let banner = document.getElementById('undo-pass-banner');
+        if (!banner) {
+            banner = document.createElement('div');
+            banner.id = 'undo-pass-banner';
+            banner.className = 'undo-pass-banner';
+            banner.innerHTML = '<span>Profil écarté</span> <button type="button">Annuler</button>';
+            banner.querySelector('button').addEventListener('click', undoLastPass);
+            document.body.appendChild(banner);
+        }
+
+        banner.style.display = 'flex';
+        clearTimeout(banner.hideTimer);
+        banner.hideTimer = setTimeout(() => {
+            banner.style.display = 'none';
+        }, 6000);
+    }
+
+    async function undoLastPass() {
+        const banner = document.getElementById('undo-pass-banner');
+        if (banner) {
+            banner.style.display = 'none';
+        }
+
+        try {
+            const response = await fetch('/api/profile/pass/undo', { method: 'POST' });
+            if (response.ok) {
+                resetPagination();
+                currentSearchParams = null;
+                loadSuggestions();
+            }
+        } catch (error) {
+            alert('Erreur lors de l\'annulation');
+        }
+    }
+
     // Initialiser le système de tags
     function initTagsSearch() {
         const tagInput = document.getElementById('tags-search');
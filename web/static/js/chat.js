@@ -222,7 +222,7 @@ function updateChatHeader(userName) {
     
     if (chatHeader) {
         chatHeader.innerHTML = `
-            <button class="back-button" onclick="handleBackButton()">←</button>
+            <button class="back-button" type="button">←</button>
             <span>${userName || 'Chat'}</span>
         `;
     }
@@ -235,7 +235,7 @@ function resetChatHeader() {
     if (chatHeader) {
         if (isMobileView) {
             chatHeader.innerHTML = `
-                <button class="back-button" onclick="handleBackButton()">←</button>
+                <button class="back-button" type="button">←</button>
                 <span>Sélectionnez une conversation</span>
             `;
         } else {
@@ -593,6 +593,16 @@ async function sendMessage(e) {
 // ✅ CONFIGURATION DES EVENT LISTENERS (VERSION SÉCURISÉE)
 function setupEventListeners() {
     
+    // Bouton retour, recréé avec l'en-tête (pas de gestionnaire inline : CSP)
+    const chatHeader = document.getElementById('chat-header');
+    if (chatHeader) {
+        chatHeader.addEventListener('click', function(e) {
+            if (e.target.closest('.back-button')) {
+                handleBackButton();
+            }
+        });
+    }
+
     const messageForm = document.getElementById('message-form');
     
     if (messageForm) {
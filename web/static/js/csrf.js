@@ -0,0 +1,46 @@
+// Ajoute le jeton CSRF aux requêtes modifiantes envoyées par fetch au serveur
+(function() {
+    'use strict';
+
+    const CSRF_HEADER = 'X-CSRF-Token';
+    const MODIFYING_METHODS = ['POST', 'PUT', 'DELETE', 'PATCH'];
+
+    // Le cookie est toujours à jour (il change à la connexion) ; la balise meta
+    // des pages d'authentification sert de repli
+    function csrfToken() {
+        const match = document.cookie.match(/(?:^|;\s*)matcha_csrf=([^;]+)/);
+        if (match) {
+            return decodeURIComponent(match[1]);
+        }
+        const meta = document.querySelector('meta[name="csrf-token"]');
+        return meta ? meta.content : '';
+    }
+
+    const originalFetch = window.fetch;
+
+    window.fetch = async function(input, init) {
+        init = init || {};
+        const isRequest = input instanceof Request;
+        const method = (init.method || (isRequest ? input.method : 'GET')).toUpperCase();
+        const url = new URL(isRequest ? input.url : input, window.location.href);
+
+        if (!MODIFYING_METHODS.includes(method) || url.origin !== window.location.origin) {
+            return originalFetch(input, init);
+        }
+
+        const send = () => {
+            const headers = new Headers(init.headers || (isRequest ? input.headers : undefined));
+            headers.set(CSRF_HEADER, csrfToken());
+            return originalFetch(input, Object.assign({}, init, { headers: headers }));
+        };
+
+        const response = await send();
+        // Jeton émis pour une session précédente : le serveur vient d'en poser un nouveau
+        if (response.status === 403 && response.headers.get('X-CSRF-Failure') === 'token') {
+            return send();
+        }
+        return response;
+    };
+
+    window.csrfToken = csrfToken;
+})();
@@ -6,6 +6,9 @@
 
 console.log('🔧 Chargement du gestionnaire d\'erreurs...');
 
+// Nonce CSP de la page, requis pour les balises <style> créées dynamiquement
+window.cspNonce = (document.currentScript && document.currentScript.nonce) || '';
+
 (function() {
     'use strict';
 
@@ -396,7 +399,7 @@ console.log('🔧 Chargement du gestionnaire d\'erreurs...');
                  response.status === 404 ? 'Ressource non trouvée' :
                  'Erreur de connexion');
             
-            onError(errorMessage);
+            onError(errorMessage, data);
             showErrorFunction(errorMessage);
             return { success: false, error: errorMessage };
             
@@ -410,6 +413,7 @@ console.log('🔧 Chargement du gestionnaire d\'erreurs...');
 
     // Ajouter des styles CSS pour les animations
     const style = document.createElement('style');
+    style.nonce = window.cspNonce || '';
     style.textContent = `
         @keyframes slideIn {
             from { transform: translateX(100%); opacity: 0; }
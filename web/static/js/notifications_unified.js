@@ -2,6 +2,7 @@ class NotificationManager {
     constructor() {
         this.updateInterval = null;
         this.websocket = null;
+        this.eventSource = null;
         this.reconnectAttempts = 0;
         this.maxReconnectAttempts = 5;
         this.isConnected = false;
@@ -217,6 +218,14 @@ class NotificationManager {
                 backgroundColor = '#2196F3';
                 icon = '👁️';
                 break;
+            case 'search_match':
+                backgroundColor = '#673AB7';
+                icon = '🔍';
+                break;
+            case 'report_resolved':
+                backgroundColor = '#607D8B';
+                icon = '🛡️';
+                break;
             case 'message':
                 backgroundColor = '#4CAF50';
                 icon = '💬';
@@ -250,6 +259,7 @@ class NotificationManager {
         // Ajouter les animations CSS
         if (!document.getElementById('toast-animations')) {
             const style = document.createElement('style');
+            style.nonce = window.cspNonce || '';
             style.id = 'toast-animations';
             style.textContent = `
                 @keyframes slideInRight {
@@ -297,7 +307,35 @@ class NotificationManager {
             setTimeout(() => {
                 this.connectWebSocket();
             }, delay);
+        } else {
+            // WebSocket bloqué (proxy) : basculer sur le flux SSE
+            this.connectEventSource();
+        }
+    }
+
+    connectEventSource() {
+        if (this.eventSource || typeof EventSource === 'undefined') {
+            return;
         }
+
+        // Le navigateur gère seul la reconnexion et renvoie Last-Event-ID
+        this.eventSource = new EventSource('/api/notifications/stream');
+
+        this.eventSource.onopen = () => {
+            this.isConnected = true;
+        };
+
+        this.eventSource.addEventListener('notification', (event) => {
+            try {
+                this.handleWebSocketMessage(JSON.parse(event.data));
+            } catch (parseError) {
+                // Message mal formé ignoré, le flux continue
+            }
+        });
+
+        this.eventSource.onerror = () => {
+            this.isConnected = false;
+        };
     }
 
     async loadCounters() {
@@ -64,14 +64,11 @@ document.addEventListener('DOMContentLoaded', function() {
         updateLocationBtn.addEventListener('click', updateLocation);
     }
 
-    // Fonction pour permettre la modification manuelle (garder votre fonction existante)
-    window.enableManualLocation = function() {
-        const locationInput = document.getElementById('location');
-        if (locationInput) {
-            locationInput.readOnly = false;
-            locationInput.placeholder = "Entrez votre ville ou coordonnées (ex: Paris ou 48.8566, 2.3522)";
-        }
-    };
+    // Permettre la modification manuelle de la localisation
+    const manualLocationBtn = document.getElementById('manual-location');
+    if (manualLocationBtn) {
+        manualLocationBtn.addEventListener('click', enableManualLocation);
+    }
 });
 
 // Fonction pour sauvegarder le profil
@@ -87,18 +84,21 @@ async function saveProfile(e) {
 
     // Récupérer les valeurs des champs correctement
     const genderSelect = document.getElementById('gender');
-    const sexualPrefSelect = document.getElementById('sexual_preference');
     const biographyTextarea = document.getElementById('biography');
     const locationInput = document.getElementById('location');
 
     const profileData = {
         gender: genderSelect ? genderSelect.value : '',
-        sexual_preference: sexualPrefSelect ? sexualPrefSelect.value : '',
         biography: biographyTextarea ? biographyTextarea.value.trim() : '',
         birth_date: birthDate,
         location_name: locationInput ? locationInput.value.trim() : ''
     };
 
+    const interestedIn = getInterestedIn();
+    if (interestedIn.length > 0) {
+        profileData.interested_in = interestedIn;
+    }
+
     // Gérer les coordonnées existantes
     if (profileData.location_name && profileData.location_name.includes(',')) {
         const coords = profileData.location_name.split(',');
@@ -610,12 +610,13 @@ function showNotification(message, type = 'info', duration = 5000) {
             width: 20px;
             height: 20px;
             opacity: 0.7;
-        " onclick="this.parentElement.remove()">×</button>
+        ">×</button>
     `;
 
     // Ajouter les styles d'animation si ils n'existent pas
     if (!document.querySelector('#notification-styles')) {
         const style = document.createElement('style');
+        style.nonce = window.cspNonce || '';
         style.id = 'notification-styles';
         style.textContent = `
             @keyframes slideInRight {
@@ -808,11 +809,16 @@ function isValidCoordinates(lat, lon) {
     );
 }
 
+// Genres cochés dans "Genres qui m'intéressent"
+function getInterestedIn() {
+    return Array.from(document.querySelectorAll('input[name="interested_in"]:checked'))
+        .map(input => input.value);
+}
+
 // Sauvegarder la localisation dans le profil
 async function saveLocationToProfile(location) {
     const currentData = {
         gender: document.getElementById('gender')?.value || '',
-        sexual_preference: document.getElementById('sexual_preference')?.value || '',
         biography: document.getElementById('biography')?.value || '',
         birth_date: document.getElementById('birth_date')?.value ? 
             document.getElementById('birth_date').value + 'T00:00:00Z' : null,
@@ -821,6 +827,11 @@ async function saveLocationToProfile(location) {
         location_name: location.city || `${location.latitude.toFixed(4)}, ${location.longitude.toFixed(4)}`
     };
 
+    const interestedIn = getInterestedIn();
+    if (interestedIn.length > 0) {
+        currentData.interested_in = interestedIn;
+    }
+
     // Nettoyer les données vides
     Object.keys(currentData).forEach(key => {
         if (currentData[key] === '' && key !== 'biography') {
@@ -913,6 +924,9 @@ function getGPSLocation() {
 // Ajoutez aussi une fonction pour permettre la modification manuelle
 function enableManualLocation() {
     const locationInput = document.getElementById('location');
+    if (!locationInput) {
+        return;
+    }
     locationInput.readOnly = false;
     locationInput.placeholder = "Entrez votre ville ou coordonnées (ex: Paris ou 48.8566, 2.3522)";
 }
@@ -0,0 +1,33 @@
+// Service worker affichant les notifications Web Push reçues quand aucun onglet n'est ouvert
+self.addEventListener('push', function(event) {
+    let data = {};
+    try {
+        data = event.data ? event.data.json() : {};
+    } catch (error) {
+        data = { body: event.data ? event.data.text() : '' };
+    }
+
+    event.waitUntil(
+        self.registration.showNotification(data.title || 'Matcha', {
+            body: data.body || 'Nouvelle notification',
+            tag: data.type ? data.type + '-' + (data.from_user_id || '') : undefined,
+            data: { url: data.url || '/notifications' }
+        })
+    );
+});
+
+self.addEventListener('notificationclick', function(event) {
+    event.notification.close();
+    const url = (event.notification.data && event.notification.data.url) || '/notifications';
+
+    event.waitUntil(
+        clients.matchAll({ type: 'window', includeUncontrolled: true }).then(function(windowClients) {
+            for (const client of windowClients) {
+                if (new URL(client.url).pathname === url && 'focus' in client) {
+                    return client.focus();
+                }
+            }
+            return clients.openWindow(url);
+        })
+    );
+});
@@ -0,0 +1,103 @@
+// Gestion de l'abonnement Web Push (notifications quand l'onglet est fermé)
+const PUSH_WORKER_URL = '/static/js/push-sw.js';
+
+function pushSupported() {
+    return 'serviceWorker' in navigator && 'PushManager' in window && 'Notification' in window;
+}
+
+function urlBase64ToUint8Array(base64String) {
+    const padding = '='.repeat((4 - base64String.length % 4) % 4);
+    const base64 = (base64String + padding).replace(/-/g, '+').replace(/_/g, '/');
+    const raw = atob(base64);
+    const output = new Uint8Array(raw.length);
+    for (let i = 0; i < raw.length; i++) {
+        output[i] = raw.charCodeAt(i);
+    }
+    return output;
+}
+
+async function getPushRegistration() {
+    return navigator.serviceWorker.register(PUSH_WORKER_URL);
+}
+
+async function enablePushNotifications() {
+    const permission = await Notification.requestPermission();
+    if (permission !== 'granted') {
+        return false;
+    }
+
+    const keyResponse = await fetch('/api/push/public-key', { credentials: 'include' });
+    if (!keyResponse.ok) {
+        return false;
+    }
+    const { public_key } = await keyResponse.json();
+
+    const registration = await getPushRegistration();
+    let subscription = await registration.pushManager.getSubscription();
+    if (!subscription) {
+        subscription = await registration.pushManager.subscribe({
+            userVisibleOnly: true,
+            applicationServerKey: urlBase64ToUint8Array(public_key)
+        });
+    }
+
+    const response = await fetch('/api/push/subscribe', {
+        method: 'POST',
+        credentials: 'include',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(subscription.toJSON())
+    });
+    return response.ok;
+}
+
+async function disablePushNotifications() {
+    const registration = await getPushRegistration();
+    const subscription = await registration.pushManager.getSubscription();
+    if (!subscription) {
+        return true;
+    }
+
+    await fetch('/api/push/subscribe', {
+        method: 'DELETE',
+        credentials: 'include',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ endpoint: subscription.endpoint })
+    });
+    return subscription.unsubscribe();
+}
+
+async function isPushEnabled() {
+    if (Notification.permission !== 'granted') {
+        return false;
+    }
+    const registration = await navigator.serviceWorker.getRegistration(PUSH_WORKER_URL);
+    if (!registration) {
+        return false;
+    }
+    return (await registration.pushManager.getSubscription()) !== null;
+}
+
+document.addEventListener('DOMContentLoaded', async function() {
+    const button = document.getElementById('toggle-push');
+    if (!button || !pushSupported()) {
+        return;
+    }
+
+    let enabled = await isPushEnabled();
+    const refreshLabel = () => {
+        button.textContent = enabled ? 'Désactiver les notifications push' : 'Activer les notifications push';
+    };
+    refreshLabel();
+    button.hidden = false;
+
+    button.addEventListener('click', async function() {
+        button.disabled = true;
+        try {
+            enabled = enabled ? !(await disablePushNotifications()) : await enablePushNotifications();
+        } catch (error) {
+            console.error('Erreur notifications push:', error);
+        }
+        refreshLabel();
+        button.disabled = false;
+    });
+});
@@ -75,17 +75,18 @@ async function likeUser(userId) {
             showSuccessMessage('✅ ' + data.message);
             
             // Mettre à jour l'interface
-            const likeButton = document.querySelector(`button[onclick="likeUser(${userId})"]`);
+            const likeButton = document.querySelector(`button[data-action="like-user"][data-user-id="${userId}"]`);
             if (likeButton) {
-                likeButton.outerHTML = `<button onclick="unlikeUser(${userId})" class="unlike-button" type="button">💔 Ne plus liker</button>`;
+                likeButton.outerHTML = `<button data-action="unlike-user" data-user-id="${userId}" class="unlike-button" type="button">💔 Ne plus liker</button>`;
             }
             
             // Afficher le bouton de chat si c'est un match
             if (data.matched) {
                 const actionsContainer = document.querySelector('.profile-actions');
-                if (actionsContainer && !document.querySelector(`button[onclick="openChat(${userId})"]`)) {
+                if (actionsContainer && !document.querySelector(`button[data-action="open-chat"][data-user-id="${userId}"]`)) {
                     const chatButton = document.createElement('button');
-                    chatButton.onclick = () => openChat(userId);
+                    chatButton.dataset.action = 'open-chat';
+                    chatButton.dataset.userId = userId;
                     chatButton.className = 'chat-button';
                     chatButton.type = 'button';
                     chatButton.textContent = '💬 Discuter';
@@ -122,13 +123,13 @@ async function unlikeUser(userId) {
             showSuccessMessage('✅ ' + data.message);
             
             // Mettre à jour l'interface
-            const unlikeButton = document.querySelector(`button[onclick="unlikeUser(${userId})"]`);
+            const unlikeButton = document.querySelector(`button[data-action="unlike-user"][data-user-id="${userId}"]`);
             if (unlikeButton) {
-                unlikeButton.outerHTML = `<button onclick="likeUser(${userId})" class="like-button" type="button">👍 Liker</button>`;
+                unlikeButton.outerHTML = `<button data-action="like-user" data-user-id="${userId}" class="like-button" type="button">👍 Liker</button>`;
             }
             
             // Supprimer le bouton de chat s'il existe
-            const chatButton = document.querySelector(`button[onclick="openChat(${userId})"]`);
+            const chatButton = document.querySelector(`button[data-action="open-chat"][data-user-id="${userId}"]`);
             if (chatButton) {
                 chatButton.remove();
             }
@@ -195,7 +196,25 @@ async function reportUser(userId) {
         return;
     }
 
-    const reason = prompt('Pourquoi signalez-vous cet utilisateur ?');
+    const categories = [
+        ['spam', 'Spam'],
+        ['harassment', 'Harcèlement'],
+        ['fake_profile', 'Faux profil'],
+        ['underage', 'Utilisateur mineur'],
+        ['inappropriate_photos', 'Photos inappropriées'],
+        ['other', 'Autre']
+    ];
+    const choice = prompt(
+        'Motif du signalement :\n' +
+        categories.map(([, label], i) => `${i + 1}. ${label}`).join('\n')
+    );
+    const index = parseInt(choice, 10) - 1;
+    if (isNaN(index) || !categories[index]) {
+        showErrorMessage('Motif du signalement requis');
+        return;
+    }
+
+    const reason = prompt('Décrivez ce qui s\'est passé :');
     if (!reason || reason.trim().length === 0) {
         showErrorMessage('Raison du signalement requise');
         return;
@@ -208,6 +227,7 @@ async function reportUser(userId) {
                 'Content-Type': 'application/json'
             },
             body: JSON.stringify({
+                category: categories[index][0],
                 reason: reason.trim()
             })
         });
@@ -216,8 +236,8 @@ async function reportUser(userId) {
             const data = await response.json();
             showSuccessMessage('✅ ' + data.message);
         } else {
-            const data = await response.json();
-            showErrorMessage(data.error || 'Erreur lors du signalement');
+            const error = await response.text();
+            showErrorMessage(error.trim() || 'Erreur lors du signalement');
         }
     } catch (error) {
         showErrorMessage('Erreur de connexion lors du signalement');
@@ -248,6 +268,23 @@ function initUserProfileStatus() {
     }
 }
 
+// Boutons d'action du profil, sans gestionnaire inline (interdit par la CSP)
+const profileActions = {
+    'like-user': likeUser,
+    'unlike-user': unlikeUser,
+    'open-chat': openChat,
+    'block-user': blockUser,
+    'report-user': reportUser
+};
+
+document.addEventListener('click', function(e) {
+    const button = e.target.closest('button[data-action]');
+    if (!button || !profileActions[button.dataset.action]) {
+        return;
+    }
+    profileActions[button.dataset.action](parseInt(button.dataset.userId, 10));
+});
+
 // Auto-initialisation
 document.addEventListener('DOMContentLoaded', function() {
     // Attendre que le manager soit initialisé
@@ -180,6 +180,7 @@ function injectStatusCSS() {
     `;
 
     const styleSheet = document.createElement("style");
+    styleSheet.nonce = window.cspNonce || '';
     styleSheet.textContent = statusCSS;
     document.head.appendChild(styleSheet);
 }